@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/controlado/go-yazio/internal/session"
+	"github.com/controlado/go-yazio/pkg/yazio"
+)
+
+var (
+	errInvalidConfig = errors.New("invalid gateway config")
+)
+
+// config is the file listing who can use the gateway:
+//
+//	{"users": [{"name": "alice", "key_sha256": "…", "session": "alice.json"}]}
+//
+// Session paths are relative to the config file.
+type config struct {
+	Users []struct {
+		Name      string `json:"name"`
+		KeySHA256 string `json:"key_sha256"`
+		Session   string `json:"session"`
+	} `json:"users"`
+}
+
+// account is a user of the gateway, identified by its API key.
+type account struct {
+	name    string
+	keyHash []byte
+	store   *session.Store
+	mu      sync.Mutex // mu serializes token refreshes.
+}
+
+// user resumes the session of acc, refreshing an expired token once
+// even when concurrent requests find it expired.
+func (acc *account) user(ctx context.Context, api *yazio.API) (*yazio.User, error) {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	return acc.store.Resume(ctx, api)
+}
+
+// hashKey returns the hex-encoded SHA-256 of an API key,
+// as written in the config.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadAccounts reads the accounts of the config at path.
+//
+// On failure the error wraps either:
+//   - [errInvalidConfig]
+//   - Other: generic (file system related)
+func loadAccounts(path string) ([]*account, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	var cfg config
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidConfig, err)
+	}
+
+	if len(cfg.Users) == 0 {
+		return nil, fmt.Errorf("%w: no users", errInvalidConfig)
+	}
+
+	var (
+		dir      = filepath.Dir(path)
+		accounts = make([]*account, 0, len(cfg.Users))
+		seen     = make(map[string]bool)
+	)
+
+	for i, u := range cfg.Users {
+		keyHash, err := hex.DecodeString(u.KeySHA256)
+		if err != nil || len(keyHash) != sha256.Size {
+			return nil, fmt.Errorf("%w: user %d: key_sha256 should be a hex-encoded SHA-256", errInvalidConfig, i)
+		}
+
+		switch {
+		case u.Name == "":
+			return nil, fmt.Errorf("%w: user %d: blank name", errInvalidConfig, i)
+		case u.Session == "":
+			return nil, fmt.Errorf("%w: user %q: blank session", errInvalidConfig, u.Name)
+		case seen[u.Name], seen[u.KeySHA256]:
+			return nil, fmt.Errorf("%w: user %q: duplicate name or key", errInvalidConfig, u.Name)
+		}
+		seen[u.Name], seen[u.KeySHA256] = true, true
+
+		sessionPath := u.Session
+		if !filepath.IsAbs(sessionPath) {
+			sessionPath = filepath.Join(dir, sessionPath)
+		}
+
+		accounts = append(accounts, &account{
+			name:    u.Name,
+			keyHash: keyHash,
+			store:   session.NewStore(sessionPath),
+		})
+	}
+
+	return accounts, nil
+}
+
+// accountByKey returns the account holding key, comparing
+// every one of them in constant time.
+func accountByKey(accounts []*account, key string) (*account, bool) {
+	var (
+		sum   = sha256.Sum256([]byte(key))
+		found *account
+	)
+
+	for _, acc := range accounts {
+		if subtle.ConstantTimeCompare(sum[:], acc.keyHash) == 1 {
+			found = acc
+		}
+	}
+
+	return found, found != nil
+}
@@ -0,0 +1,160 @@
+// Command yazio-gateway serves the YAZIO data of stored
+// sessions as a small REST API, behind per-user API keys.
+//
+// Usage:
+//
+//	yazio-gateway -config PATH [-addr ADDR] [-base-url URL]
+//	yazio-gateway -hash-key < key
+//
+// The config lists the users of the gateway, each with the SHA-256
+// of its API key (print one with -hash-key) and the session file
+// written by 'yazio login':
+//
+//	{"users": [{"name": "alice", "key_sha256": "…", "session": "alice.json"}]}
+//
+// Requests authenticate with "Authorization: Bearer <key>", and
+// expired tokens are refreshed (and stored again) on demand. The
+// routes are described by the OpenAPI spec served at /openapi.json.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/yazio"
+)
+
+const (
+	baseURLEnv = "YAZIO_BASE_URL"
+
+	shutdownTimeout = 10 * time.Second
+)
+
+// Exit codes
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run serves the gateway until ctx is done, returning the exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("yazio-gateway", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var (
+		addr       = fs.String("addr", "localhost:8080", "listen `address`")
+		configPath = fs.String("config", "", "config file `path`")
+		baseURL    = fs.String("base-url", os.Getenv(baseURLEnv), "YAZIO API base `url`")
+		printHash  = fs.Bool("hash-key", false, "print the SHA-256 of the API key read from stdin")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	if *printHash {
+		key, err := bufio.NewReader(stdin).ReadString('\n')
+		if key = strings.TrimSpace(key); key == "" {
+			fmt.Fprintf(stderr, "yazio-gateway: reading key: %v\n", err)
+			return exitFailure
+		}
+		fmt.Fprintln(stdout, hashKey(key))
+		return exitOK
+	}
+
+	if *configPath == "" {
+		fmt.Fprintln(stderr, "yazio-gateway: -config is required")
+		fs.Usage()
+		return exitUsage
+	}
+
+	g, err := newGateway(*configPath, *baseURL)
+	if err != nil {
+		fmt.Fprintf(stderr, "yazio-gateway: %v\n", err)
+		return exitFailure
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintf(stderr, "yazio-gateway: %v\n", err)
+		return exitFailure
+	}
+
+	fmt.Fprintf(stdout, "Serving %d users on http://%s\n", len(g.accounts), ln.Addr())
+	if err := serve(ctx, ln, g.handler()); err != nil {
+		fmt.Fprintf(stderr, "yazio-gateway: %v\n", err)
+		return exitFailure
+	}
+
+	return exitOK
+}
+
+func newGateway(configPath, baseURL string) (*gateway, error) {
+	accounts, err := loadAccounts(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []yazio.Option
+	if baseURL != "" {
+		opts = append(opts, yazio.WithBaseURL(baseURL))
+	}
+
+	api, err := yazio.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &gateway{
+		api:      api,
+		accounts: accounts,
+		loc:      time.Local,
+	}
+
+	return g, nil
+}
+
+// serve serves h on ln until ctx is done, then
+// waits for in-flight requests to finish.
+func serve(ctx context.Context, ln net.Listener, h http.Handler) error {
+	srv := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return srv.Shutdown(shutdownCtx)
+}
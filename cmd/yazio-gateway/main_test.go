@@ -0,0 +1,372 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/session"
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/internal/testutil/fakeuser"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/yazio"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
+	"github.com/google/uuid"
+)
+
+const testKey = "s3cret-key"
+
+// client calls a gateway in front of a fake YAZIO,
+// with a single account logged in.
+type client struct {
+	t     *testing.T
+	fake  *yaziotest.Server
+	url   string
+	store *session.Store
+}
+
+func newClient(t *testing.T) *client {
+	t.Helper()
+
+	var (
+		fake = yaziotest.New(t)
+		dir  = t.TempDir()
+	)
+
+	u := fakeuser.Login(t, fake)
+
+	store := session.NewStore(filepath.Join(dir, "joao.json"))
+	assert.NoError(t, store.Save(session.FromToken(yaziotest.DefaultUsername, u.Token())))
+
+	configPath := filepath.Join(dir, "gateway.json")
+	config := fmt.Sprintf(`{"users": [{"name": "joao", "key_sha256": %q, "session": "joao.json"}]}`, hashKey(testKey))
+	assert.NoError(t, os.WriteFile(configPath, []byte(config), 0o600))
+
+	g, err := newGateway(configPath, fake.URL)
+	assert.NoError(t, err)
+
+	srv := httptest.NewServer(g.handler())
+	t.Cleanup(srv.Close)
+
+	return &client{t: t, fake: fake, url: srv.URL, store: store}
+}
+
+// do sends a request with key, decoding the response into out (if not nil).
+func (c *client) do(key, method, path, body string, out any) int {
+	c.t.Helper()
+
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, c.url+path, r)
+	assert.NoError(c.t, err)
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(c.t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(c.t, resp.Header.Get("Content-Type"), "application/json")
+	if out != nil {
+		assert.NoError(c.t, json.NewDecoder(resp.Body).Decode(out))
+	}
+
+	return resp.StatusCode
+}
+
+func TestGateway_Workflow(t *testing.T) {
+	t.Parallel()
+
+	var (
+		c     = newClient(t)
+		today = time.Now().Format(layoutDay)
+	)
+
+	var profile map[string]string
+	assert.Equal(t, c.do(testKey, http.MethodGet, "/me", "", &profile), http.StatusOK)
+	assert.Equal(t, profile["first_name"], "João")
+
+	var created struct{ ID string }
+	status := c.do(testKey, http.MethodPost, "/products", `{
+		"name": "Oats",
+		"category": "cereals",
+		"nutrients": {
+			"energy.energy": 380, "nutrient.carb": 60, "nutrient.fat": 7,
+			"nutrient.protein": 13, "nutrient.dietaryfiber": 10
+		},
+		"servings": [{"kind": "bowl", "amount": 50}]
+	}`, &created)
+	assert.Equal(t, status, http.StatusCreated)
+	assert.Equal(t, len(c.fake.Products()), 1)
+	assert.Equal(t, c.fake.Products()[0].ID.String(), created.ID)
+
+	var entry map[string]any
+	body := fmt.Sprintf(`{"meal": "Breakfast", "food_id": %q, "serving": "bowl", "amount": 50}`, created.ID)
+	assert.Equal(t, c.do(testKey, http.MethodPost, "/diary", body, &entry), http.StatusCreated)
+	assert.Equal(t, entry["meal"], "breakfast")
+	assert.Equal(t, entry["quantity"], 1.0)
+
+	var entries []map[string]any
+	assert.Equal(t, c.do(testKey, http.MethodGet, "/diary?date="+today, "", &entries), http.StatusOK)
+	assert.Equal(t, len(entries), 1)
+	assert.Equal(t, entries[0]["id"], entry["id"])
+
+	var macros []map[string]any
+	assert.Equal(t, c.do(testKey, http.MethodGet, "/macros?from="+today+"&to="+today, "", &macros), http.StatusOK)
+	assert.DeepEqual(t, macros, []map[string]any{
+		{"date": today, "energy": 190.0, "carb": 30.0, "fat": 3.5, "protein": 6.5},
+	})
+
+	var fiber struct {
+		Kind string
+		Unit string
+		Days []struct {
+			Date  string
+			Value float64
+		}
+	}
+	assert.Equal(t, c.do(testKey, http.MethodGet, "/intake/nutrient.dietaryfiber?to="+today, "", &fiber), http.StatusOK)
+	assert.Equal(t, fiber.Unit, "g")
+	assert.Equal(t, fiber.Days[len(fiber.Days)-1].Value, 5.0)
+
+	assert.NoError(t, c.fake.Consume(yaziotest.DefaultUsername, yaziotest.ConsumedItem{
+		ID:        uuid.New(),
+		ProductID: uuid.MustParse(created.ID),
+		Date:      time.Now(),
+		Daytime:   "lunch",
+		Amount:    30,
+		Quantity:  1,
+	}))
+
+	entries = nil
+	assert.Equal(t, c.do(testKey, http.MethodGet, "/diary?date="+today, "", &entries), http.StatusOK)
+	assert.Equal(t, len(entries), 2)
+	for _, e := range entries {
+		_, hasServing := e["serving"]
+		assert.Equal(t, hasServing, e["meal"] == "breakfast")
+	}
+
+	again := fmt.Sprintf(`{"id": %q, "meal": "lunch", "food_id": %q, "amount": 50}`, entry["id"], created.ID)
+	assert.Equal(t, c.do(testKey, http.MethodPost, "/diary", again, nil), http.StatusConflict)
+}
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	writeJSON(rec, http.StatusOK, map[string]float64{"energy": math.NaN()})
+
+	var body errorJSON
+	assert.Equal(t, rec.Code, http.StatusInternalServerError)
+	assert.Equal(t, rec.Header().Get("Content-Type"), "application/json")
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, strings.HasPrefix(body.Error, "encoding response: "), true)
+}
+
+func TestGateway_Errors(t *testing.T) {
+	t.Parallel()
+
+	c := newClient(t)
+
+	testBlocks := []struct {
+		name   string
+		key    string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{name: "missing key", method: http.MethodGet, path: "/me", want: http.StatusUnauthorized},
+		{name: "unknown key", key: "guess", method: http.MethodGet, path: "/me", want: http.StatusUnauthorized},
+		{name: "invalid date", key: testKey, method: http.MethodGet, path: "/macros?from=12/04/2025", want: http.StatusBadRequest},
+		{name: "reversed range", key: testKey, method: http.MethodGet, path: "/macros?from=2025-04-12&to=2025-04-01", want: http.StatusBadRequest},
+		{name: "range too long", key: testKey, method: http.MethodGet, path: "/macros?from=2024-01-01&to=2025-04-01", want: http.StatusBadRequest},
+		{name: "unknown kind", key: testKey, method: http.MethodGet, path: "/intake/vitamin.z", want: http.StatusNotFound},
+		{name: "unknown field", key: testKey, method: http.MethodPost, path: "/products", body: `{"name": "Oats", "calories": 1}`, want: http.StatusBadRequest},
+		{name: "unknown category", key: testKey, method: http.MethodPost, path: "/products", body: `{"name": "Gizmo", "category": "gadgets"}`, want: http.StatusBadRequest},
+		{
+			name: "invalid food", key: testKey, method: http.MethodPost, path: "/products",
+			body: `{"name": "Air", "category": "miscellaneous", "nutrients": {"energy.energy": 900, "nutrient.carb": 1}}`,
+			want: http.StatusUnprocessableEntity,
+		},
+		{name: "unknown meal", key: testKey, method: http.MethodPost, path: "/diary", body: `{"meal": "brunch"}`, want: http.StatusBadRequest},
+		{
+			name: "missing amount", key: testKey, method: http.MethodPost, path: "/diary",
+			body: `{"meal": "lunch", "food_id": "8f2a7c1e-6b5d-4e3f-9a1b-0c2d3e4f5a6b"}`,
+			want: http.StatusBadRequest,
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out errorJSON
+			assert.Equal(t, c.do(tb.key, tb.method, tb.path, tb.body, &out), tb.want)
+			assert.Equal(t, out.Error != "", true)
+		})
+	}
+}
+
+func TestGateway_RefreshesExpiredSession(t *testing.T) {
+	t.Parallel()
+
+	c := newClient(t)
+
+	sess, err := c.store.Load()
+	assert.NoError(t, err)
+
+	c.fake.ExpireTokens()
+	sess.ExpiresAt = time.Now().Add(-time.Minute)
+	assert.NoError(t, c.store.Save(sess))
+
+	assert.Equal(t, c.do(testKey, http.MethodGet, "/me", "", nil), http.StatusOK)
+
+	refreshed, err := c.store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, refreshed.Refresh != sess.Refresh, true)
+
+	assert.NoError(t, c.store.Clear())
+	assert.Equal(t, c.do(testKey, http.MethodGet, "/me", "", nil), http.StatusServiceUnavailable)
+}
+
+func TestGateway_OpenAPI(t *testing.T) {
+	t.Parallel()
+
+	c := newClient(t)
+
+	var spec struct {
+		OpenAPI string
+		Paths   map[string]map[string]struct {
+			OperationID string
+			Responses   map[string]any
+		}
+	}
+	assert.Equal(t, c.do("", http.MethodGet, "/openapi.json", "", &spec), http.StatusOK)
+	assert.Equal(t, spec.OpenAPI, "3.0.3")
+
+	for _, rt := range routes {
+		op, ok := spec.Paths[rt.path][strings.ToLower(rt.method)]
+		assert.Equal(t, ok, true)
+		assert.Equal(t, op.Responses[fmt.Sprint(rt.status)] != nil, true)
+	}
+	assert.Equal(t, spec.Paths["/intake/{kind}"]["get"].OperationID, "getIntakeKind")
+}
+
+func TestStatusOf(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		err  error
+		want int
+	}{
+		{errors.New("boom"), http.StatusInternalServerError},
+		{errorf(http.StatusBadRequest, "bad date"), http.StatusBadRequest},
+		{session.ErrNoSession, http.StatusServiceUnavailable},
+		{fmt.Errorf("%w: %w", session.ErrExpired, yazio.ErrInvalidCredentials), http.StatusServiceUnavailable},
+		{yazio.ErrExpiredToken, http.StatusServiceUnavailable},
+		{food.ErrAlreadyExists, http.StatusConflict},
+		{errors.Join(food.ErrNegativeNutrient, food.ErrDuplicateServing), http.StatusUnprocessableEntity},
+		{fmt.Errorf("%w: %w", yazio.ErrRequestingToYazio, errors.New("dial")), http.StatusBadGateway},
+		{fmt.Errorf("%w: %w", yazio.ErrDecodingResponse, errors.New("eof")), http.StatusBadGateway},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(fmt.Sprint(tb.err), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, statusOf(tb.err), tb.want)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name  string
+		stdin string
+		args  []string
+		want  int
+		out   string
+	}{
+		{name: "hash key", stdin: testKey + "\n", args: []string{"-hash-key"}, want: exitOK, out: hashKey(testKey) + "\n"},
+		{name: "blank key", args: []string{"-hash-key"}, want: exitFailure},
+		{name: "missing config", want: exitUsage},
+		{name: "unknown flag", args: []string{"-port", "80"}, want: exitUsage},
+		{name: "unreadable config", args: []string{"-config", "missing.json"}, want: exitFailure},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), tb.args, strings.NewReader(tb.stdin), &stdout, &stderr)
+			assert.Equal(t, code, tb.want)
+			if tb.out != "" {
+				assert.Equal(t, stdout.String(), tb.out)
+			}
+		})
+	}
+}
+
+func TestLoadAccounts(t *testing.T) {
+	t.Parallel()
+
+	hash := hashKey(testKey)
+
+	testBlocks := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{name: "valid", config: `{"users": [{"name": "a", "key_sha256": "` + hash + `", "session": "a.json"}]}`},
+		{name: "not json", config: `{`, wantErr: true},
+		{name: "no users", config: `{"users": []}`, wantErr: true},
+		{name: "short hash", config: `{"users": [{"name": "a", "key_sha256": "abc", "session": "a.json"}]}`, wantErr: true},
+		{name: "blank session", config: `{"users": [{"name": "a", "key_sha256": "` + hash + `"}]}`, wantErr: true},
+		{
+			name: "duplicate key",
+			config: `{"users": [{"name": "a", "key_sha256": "` + hash + `", "session": "a.json"},` +
+				`{"name": "b", "key_sha256": "` + hash + `", "session": "b.json"}]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "gateway.json")
+			assert.NoError(t, os.WriteFile(path, []byte(tb.config), 0o600))
+
+			accounts, err := loadAccounts(path)
+			assert.WantErr(t, tb.wantErr, err)
+			if tb.wantErr {
+				if !errors.Is(err, errInvalidConfig) {
+					t.Fatalf("got %v, want %v", err, errInvalidConfig)
+				}
+				return
+			}
+
+			acc, ok := accountByKey(accounts, testKey)
+			assert.Equal(t, ok, true)
+			assert.Equal(t, acc.store.Path(), filepath.Join(filepath.Dir(path), "a.json"))
+		})
+	}
+}
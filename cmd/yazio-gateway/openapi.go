@@ -0,0 +1,209 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/meal"
+)
+
+// object is a JSON object of the OpenAPI spec.
+type object = map[string]any
+
+func ref(schema string) object {
+	return object{"$ref": "#/components/schemas/" + schema}
+}
+
+func props(required []string, properties object) object {
+	o := object{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		o["required"] = required
+	}
+	return o
+}
+
+func arrayOf(items object) object {
+	return object{"type": "array", "items": items}
+}
+
+var (
+	stringSchema = object{"type": "string"}
+	numberSchema = object{"type": "number"}
+	uuidSchema   = object{"type": "string", "format": "uuid"}
+	dateSchema   = object{"type": "string", "format": "date"}
+	timeSchema   = object{"type": "string", "format": "date-time"}
+)
+
+func enumOf[S ~string](values []S) object {
+	enum := make([]string, 0, len(values))
+	for _, v := range values {
+		enum = append(enum, string(v))
+	}
+	return object{"type": "string", "enum": enum}
+}
+
+// schemas are the components referenced by the routes.
+func schemas() object {
+	serving := enumOf(food.ServingKinds())
+
+	return object{
+		"Error": props([]string{"error"}, object{"error": stringSchema}),
+		"Profile": props(nil, object{
+			"id":           uuidSchema,
+			"first_name":   stringSchema,
+			"last_name":    stringSchema,
+			"email":        stringSchema,
+			"registration": dateSchema,
+			"birth":        dateSchema,
+		}),
+		"MacrosList": arrayOf(props(nil, object{
+			"date":    dateSchema,
+			"energy":  numberSchema,
+			"carb":    numberSchema,
+			"fat":     numberSchema,
+			"protein": numberSchema,
+		})),
+		"Intake": props(nil, object{
+			"kind": stringSchema,
+			"unit": stringSchema,
+			"days": arrayOf(props(nil, object{"date": dateSchema, "value": numberSchema})),
+		}),
+		"NewProduct": props([]string{"name", "category", "nutrients"}, object{
+			"id":        uuidSchema,
+			"name":      object{"type": "string", "minLength": 3},
+			"category":  enumOf(food.Categories()),
+			"base_unit": object{"type": "string", "enum": []string{"g", "ml"}, "default": "g"},
+			"nutrients": object{
+				"type":                 "object",
+				"description":          "values per 100 of the base unit, keyed by intake kind ID",
+				"additionalProperties": numberSchema,
+			},
+			"servings": arrayOf(props([]string{"kind", "amount"}, object{"kind": serving, "amount": numberSchema})),
+			"public":   object{"type": "boolean"},
+		}),
+		"Created": props(nil, object{"id": uuidSchema}),
+		"Entry": props(nil, object{
+			"id":       uuidSchema,
+			"time":     timeSchema,
+			"meal":     enumOf(meal.Times()),
+			"food_id":  uuidSchema,
+			"serving":  serving,
+			"amount":   numberSchema,
+			"quantity": numberSchema,
+		}),
+		"EntryList": arrayOf(ref("Entry")),
+		"NewEntry": props([]string{"meal", "food_id", "amount"}, object{
+			"id":       uuidSchema,
+			"time":     object{"type": "string", "format": "date-time", "description": "defaults to now"},
+			"meal":     enumOf(meal.Times()),
+			"food_id":  uuidSchema,
+			"serving":  object{"type": "string", "enum": serving["enum"], "default": food.Portion},
+			"amount":   object{"type": "number", "description": "weight of one serving, in the food base unit"},
+			"quantity": object{"type": "number", "default": 1},
+		}),
+	}
+}
+
+// errorStatuses are the error responses every route may answer with.
+var errorStatuses = map[int]string{
+	http.StatusBadRequest:          "invalid request",
+	http.StatusUnauthorized:        "missing or unknown API key",
+	http.StatusBadGateway:          "YAZIO failed or answered unexpectedly",
+	http.StatusServiceUnavailable:  "stored session is missing or expired",
+	http.StatusInternalServerError: "unexpected failure",
+}
+
+// openAPI describes routes as an OpenAPI 3 spec.
+func openAPI(routes []route) object {
+	errorResponse := func(description string) object {
+		return object{
+			"description": description,
+			"content":     object{"application/json": object{"schema": ref("Error")}},
+		}
+	}
+
+	paths := object{}
+	for _, rt := range routes {
+		responses := object{
+			strconv.Itoa(rt.status): object{
+				"description": http.StatusText(rt.status),
+				"content":     object{"application/json": object{"schema": ref(rt.response)}},
+			},
+		}
+		for status, description := range errorStatuses {
+			responses[strconv.Itoa(status)] = errorResponse(description)
+		}
+		if rt.body != "" {
+			responses[strconv.Itoa(http.StatusConflict)] = errorResponse("already exists")
+			responses[strconv.Itoa(http.StatusUnprocessableEntity)] = errorResponse("invalid food")
+		}
+		if strings.Contains(rt.path, "{") {
+			responses[strconv.Itoa(http.StatusNotFound)] = errorResponse("unknown path parameter")
+		}
+
+		op := object{
+			"summary":     rt.summary,
+			"operationId": strings.ToLower(rt.method) + operationName(rt.path),
+			"responses":   responses,
+		}
+
+		if len(rt.params) > 0 {
+			params := make([]object, 0, len(rt.params))
+			for _, p := range rt.params {
+				params = append(params, object{
+					"name":        p.name,
+					"in":          p.in,
+					"required":    p.in == "path",
+					"description": p.description,
+					"example":     p.example,
+					"schema":      stringSchema,
+				})
+			}
+			op["parameters"] = params
+		}
+
+		if rt.body != "" {
+			op["requestBody"] = object{
+				"required": true,
+				"content":  object{"application/json": object{"schema": ref(rt.body)}},
+			}
+		}
+
+		item, _ := paths[rt.path].(object)
+		if item == nil {
+			item = object{}
+			paths[rt.path] = item
+		}
+		item[strings.ToLower(rt.method)] = op
+	}
+
+	return object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":   "YAZIO gateway",
+			"version": "1.0.0",
+		},
+		"security": []object{{"apiKey": []string{}}},
+		"paths":    paths,
+		"components": object{
+			"schemas": schemas(),
+			"securitySchemes": object{
+				"apiKey": object{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+}
+
+// operationName turns a path into a camel case name (e.g.
+// "/intake/{kind}" into "IntakeKind").
+func operationName(path string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
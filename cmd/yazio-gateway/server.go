@@ -0,0 +1,536 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/session"
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/diary"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/meal"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+	"github.com/controlado/go-yazio/pkg/visibility"
+	"github.com/controlado/go-yazio/pkg/yazio"
+	"github.com/google/uuid"
+)
+
+const (
+	layoutDay = "2006-01-02"
+
+	defaultRangeDays = 7
+	maxRangeDays     = 366
+	maxBodyBytes     = 1 << 20
+)
+
+// gateway serves the data of its accounts.
+type gateway struct {
+	api      *yazio.API
+	accounts []*account
+	loc      *time.Location
+}
+
+// handlerFunc handles an authenticated request, returning
+// the value encoded as the JSON response.
+type handlerFunc func(g *gateway, r *http.Request, u *yazio.User) (any, error)
+
+// route is an endpoint of the gateway, also
+// describing it for the OpenAPI spec.
+type route struct {
+	method   string
+	path     string
+	summary  string
+	params   []param
+	body     string // body names the request schema, if any.
+	response string // response names the response schema.
+	status   int
+	handle   handlerFunc
+}
+
+// param is a path or query parameter of a [route].
+type param struct {
+	name        string
+	in          string // "path" or "query"
+	description string
+	example     string
+}
+
+var (
+	fromParam = param{"from", "query", "first day (YYYY-MM-DD), defaults to a week before to", "2025-04-01"}
+	toParam   = param{"to", "query", "last day (YYYY-MM-DD), defaults to today", "2025-04-07"}
+)
+
+// routes lists the endpoints of the gateway.
+var routes = []route{
+	{
+		method: http.MethodGet, path: "/me",
+		summary:  "Profile of the user",
+		response: "Profile", status: http.StatusOK,
+		handle: (*gateway).me,
+	},
+	{
+		method: http.MethodGet, path: "/macros",
+		summary:  "Daily macros of a date range",
+		params:   []param{fromParam, toParam},
+		response: "MacrosList", status: http.StatusOK,
+		handle: (*gateway).macros,
+	},
+	{
+		method: http.MethodGet, path: "/intake/{kind}",
+		summary: "Daily intakes of a nutrient in a date range",
+		params: []param{
+			{"kind", "path", "intake kind ID", intake.VitaminC.ID()},
+			fromParam, toParam,
+		},
+		response: "Intake", status: http.StatusOK,
+		handle: (*gateway).intake,
+	},
+	{
+		method: http.MethodPost, path: "/products",
+		summary: "Register a new food (product)",
+		body:    "NewProduct", response: "Created", status: http.StatusCreated,
+		handle: (*gateway).addProduct,
+	},
+	{
+		method: http.MethodGet, path: "/diary",
+		summary: "Diary entries of a day",
+		params: []param{
+			{"date", "query", "day (YYYY-MM-DD), defaults to today", "2025-04-12"},
+		},
+		response: "EntryList", status: http.StatusOK,
+		handle: (*gateway).diary,
+	},
+	{
+		method: http.MethodPost, path: "/diary",
+		summary: "Log a food in the diary",
+		body:    "NewEntry", response: "Entry", status: http.StatusCreated,
+		handle: (*gateway).addEntry,
+	},
+}
+
+// handler returns the [http.Handler] of every route,
+// plus the OpenAPI spec at /openapi.json.
+func (g *gateway) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	for _, rt := range routes {
+		mux.Handle(rt.method+" "+rt.path, g.serve(rt))
+	}
+
+	spec := openAPI(routes)
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, spec)
+	})
+
+	return mux
+}
+
+// serve authenticates the request, resumes the session
+// of its account and writes the outcome of rt.
+func (g *gateway) serve(rt route) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			writeError(w, errorf(http.StatusUnauthorized, "missing bearer API key"))
+			return
+		}
+
+		acc, ok := accountByKey(g.accounts, key)
+		if !ok {
+			writeError(w, errorf(http.StatusUnauthorized, "unknown API key"))
+			return
+		}
+
+		u, err := acc.user(r.Context(), g.api)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+		v, err := rt.handle(g, r, u)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, rt.status, v)
+	})
+}
+
+// httpError is an error with the status it's served with.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e httpError) Error() string {
+	return e.err.Error()
+}
+
+func (e httpError) Unwrap() error {
+	return e.err
+}
+
+func errorf(status int, format string, args ...any) error {
+	return httpError{status: status, err: fmt.Errorf(format, args...)}
+}
+
+// statusOf maps err to the status it's served with,
+// checking the most specific errors first.
+func statusOf(err error) int {
+	var he httpError
+
+	switch {
+	case errors.As(err, &he):
+		return he.status
+	case errors.Is(err, session.ErrNoSession),
+		errors.Is(err, session.ErrCorruptSession),
+		errors.Is(err, session.ErrExpired),
+		errors.Is(err, yazio.ErrExpiredToken):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, food.ErrAlreadyExists),
+		errors.Is(err, diary.ErrAlreadyExists):
+		return http.StatusConflict
+	case food.IsValidationError(err):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, yazio.ErrRequestingToYazio),
+		errors.Is(err, yazio.ErrDecodingResponse):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusOf(err), errorJSON{Error: err.Error()})
+}
+
+// writeJSON encodes v before writing status,
+// answering 500 instead if v fails to encode.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		status = http.StatusInternalServerError
+		buf.Reset()
+		_ = json.NewEncoder(&buf).Encode(errorJSON{Error: fmt.Sprintf("encoding response: %v", err)})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = buf.WriteTo(w)
+}
+
+// decodeBody decodes the JSON body of r into v, rejecting unknown fields.
+func decodeBody(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return errorf(http.StatusBadRequest, "invalid body: %w", err)
+	}
+	return nil
+}
+
+// day parses the query param name as a day, defaulting to def.
+func (g *gateway) day(r *http.Request, name string, def time.Time) (time.Time, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseInLocation(layoutDay, s, g.loc)
+	if err != nil {
+		return d, errorf(http.StatusBadRequest, "invalid %s %q: want YYYY-MM-DD", name, s)
+	}
+	return d, nil
+}
+
+// dateRange parses the from and to query params.
+func (g *gateway) dateRange(r *http.Request) (rng date.Range, err error) {
+	now := time.Now().In(g.loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, g.loc)
+
+	if rng.End, err = g.day(r, "to", today); err != nil {
+		return rng, err
+	}
+	if rng.Start, err = g.day(r, "from", rng.End.AddDate(0, 0, 1-defaultRangeDays)); err != nil {
+		return rng, err
+	}
+
+	switch {
+	case rng.Start.After(rng.End):
+		return rng, errorf(http.StatusBadRequest, "from %s is after to %s", rng.Start.Format(layoutDay), rng.End.Format(layoutDay))
+	case rng.End.Sub(rng.Start) >= maxRangeDays*24*time.Hour:
+		return rng, errorf(http.StatusBadRequest, "range is over %d days", maxRangeDays)
+	}
+
+	return rng, nil
+}
+
+type profileJSON struct {
+	ID           string `json:"id"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	Email        string `json:"email"`
+	Registration string `json:"registration"`
+	Birth        string `json:"birth"`
+}
+
+func (g *gateway) me(r *http.Request, u *yazio.User) (any, error) {
+	d, err := u.Data(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return profileJSON{
+		ID:           d.ID.String(),
+		FirstName:    d.FirstName,
+		LastName:     d.LastName,
+		Email:        d.Email.String(),
+		Registration: d.Registration.Format(layoutDay),
+		Birth:        d.Birth.Format(layoutDay),
+	}, nil
+}
+
+type macrosJSON struct {
+	Date    string  `json:"date"`
+	Energy  float64 `json:"energy"`
+	Carb    float64 `json:"carb"`
+	Fat     float64 `json:"fat"`
+	Protein float64 `json:"protein"`
+}
+
+func (g *gateway) macros(r *http.Request, u *yazio.User) (any, error) {
+	rng, err := g.dateRange(r)
+	if err != nil {
+		return nil, err
+	}
+
+	mr, err := u.Macros(r.Context(), rng)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]macrosJSON, 0, len(mr))
+	for _, m := range mr {
+		out = append(out, macrosJSON{m.Date.Format(layoutDay), m.Energy, m.Carb, m.Fat, m.Protein})
+	}
+	return out, nil
+}
+
+type (
+	intakeJSON struct {
+		Kind string          `json:"kind"`
+		Unit string          `json:"unit"`
+		Days []intakeDayJSON `json:"days"`
+	}
+	intakeDayJSON struct {
+		Date  string  `json:"date"`
+		Value float64 `json:"value"`
+	}
+)
+
+func (g *gateway) intake(r *http.Request, u *yazio.User) (any, error) {
+	k, err := intake.KindByID(r.PathValue("kind"))
+	if err != nil {
+		return nil, httpError{status: http.StatusNotFound, err: err}
+	}
+
+	rng, err := g.dateRange(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, err := u.Intake(r.Context(), k, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	out := intakeJSON{Kind: k.ID(), Unit: k.Unit(), Days: make([]intakeDayJSON, 0, len(sr))}
+	for _, s := range sr {
+		if !s.HasData() {
+			continue
+		}
+		out.Days = append(out.Days, intakeDayJSON{s.Date.Format(layoutDay), s.Value})
+	}
+	return out, nil
+}
+
+type (
+	newProductJSON struct {
+		ID        *uuid.UUID              `json:"id"`
+		Name      string                  `json:"name"`
+		Category  string                  `json:"category"`
+		BaseUnit  unit.Base               `json:"base_unit"`
+		Nutrients map[intake.Kind]float64 `json:"nutrients"`
+		Servings  []servingJSON           `json:"servings"`
+		Public    bool                    `json:"public"`
+	}
+	servingJSON struct {
+		Kind   string  `json:"kind"`
+		Amount float64 `json:"amount"`
+	}
+	createdJSON struct {
+		ID uuid.UUID `json:"id"`
+	}
+)
+
+func (g *gateway) addProduct(r *http.Request, u *yazio.User) (any, error) {
+	var body newProductJSON
+	if err := decodeBody(r, &body); err != nil {
+		return nil, err
+	}
+
+	opts := []food.Option{}
+	if body.ID != nil {
+		opts = append(opts, food.WithID(*body.ID))
+	}
+
+	switch body.BaseUnit {
+	case "", unit.Gram:
+	case unit.Milliliter:
+		opts = append(opts, food.WithBaseUnit(unit.Milliliter))
+	default:
+		return nil, errorf(http.StatusBadRequest, "invalid base_unit %q: want g or ml", body.BaseUnit)
+	}
+
+	cat, err := food.ParseCategory(body.Category)
+	if err != nil {
+		return nil, httpError{status: http.StatusBadRequest, err: err}
+	}
+
+	for _, s := range body.Servings {
+		sk, err := food.ParseServingKind(s.Kind)
+		if err != nil {
+			return nil, httpError{status: http.StatusBadRequest, err: err}
+		}
+		opts = append(opts, food.WithNewServing(sk, s.Amount))
+	}
+
+	f, err := food.New(body.Name, cat, body.Nutrients, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := u.AddFood(r.Context(), f, visibility.Food(body.Public)); err != nil {
+		return nil, err
+	}
+
+	return createdJSON{ID: f.ID}, nil
+}
+
+type (
+	entryJSON struct {
+		ID       uuid.UUID `json:"id"`
+		Time     time.Time `json:"time"`
+		Meal     meal.Time `json:"meal"`
+		FoodID   uuid.UUID `json:"food_id"`
+		Serving  string    `json:"serving,omitempty"`
+		Amount   float64   `json:"amount"`
+		Quantity float64   `json:"quantity"`
+	}
+	newEntryJSON struct {
+		ID       *uuid.UUID `json:"id"`
+		Time     *time.Time `json:"time"`
+		Meal     string     `json:"meal"`
+		FoodID   uuid.UUID  `json:"food_id"`
+		Serving  string     `json:"serving"`
+		Amount   float64    `json:"amount"`
+		Quantity float64    `json:"quantity"`
+	}
+)
+
+func newEntryJSONOf(e diary.Entry) entryJSON {
+	return entryJSON{
+		ID:       e.ID,
+		Time:     e.Date,
+		Meal:     e.Meal,
+		FoodID:   e.FoodID,
+		Serving:  e.Serving.Kind.String(),
+		Amount:   e.Serving.Amount,
+		Quantity: e.Quantity,
+	}
+}
+
+func (g *gateway) diary(r *http.Request, u *yazio.User) (any, error) {
+	day, err := g.day(r, "date", time.Now().In(g.loc))
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := u.Diary(r.Context(), day)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]entryJSON, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, newEntryJSONOf(e))
+	}
+	return out, nil
+}
+
+func (g *gateway) addEntry(r *http.Request, u *yazio.User) (any, error) {
+	var body newEntryJSON
+	if err := decodeBody(r, &body); err != nil {
+		return nil, err
+	}
+
+	m, err := meal.Parse(body.Meal)
+	if err != nil {
+		return nil, httpError{status: http.StatusBadRequest, err: err}
+	}
+
+	switch {
+	case body.FoodID == uuid.Nil:
+		return nil, errorf(http.StatusBadRequest, "missing food_id")
+	case body.Amount <= 0:
+		return nil, errorf(http.StatusBadRequest, "amount %g should be positive", body.Amount)
+	case body.Quantity < 0:
+		return nil, errorf(http.StatusBadRequest, "quantity %g should be positive", body.Quantity)
+	}
+
+	e := diary.Entry{
+		ID:       uuid.New(),
+		Date:     time.Now().In(g.loc),
+		Meal:     m,
+		FoodID:   body.FoodID,
+		Serving:  food.Serving{Kind: food.Portion, Amount: body.Amount},
+		Quantity: body.Quantity,
+	}
+	if body.ID != nil {
+		e.ID = *body.ID
+	}
+	if body.Time != nil {
+		e.Date = body.Time.In(g.loc)
+	}
+	if body.Serving != "" {
+		if e.Serving.Kind, err = food.ParseServingKind(body.Serving); err != nil {
+			return nil, httpError{status: http.StatusBadRequest, err: err}
+		}
+	}
+	if e.Quantity == 0 {
+		e.Quantity = 1
+	}
+
+	if err := u.AddEntry(r.Context(), e); err != nil {
+		return nil, err
+	}
+
+	return newEntryJSONOf(e), nil
+}
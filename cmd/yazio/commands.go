@@ -0,0 +1,843 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/session"
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/meal"
+	"github.com/controlado/go-yazio/pkg/domain/reference"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+	"github.com/controlado/go-yazio/pkg/export"
+	"github.com/controlado/go-yazio/pkg/ics"
+	"github.com/controlado/go-yazio/pkg/importer"
+	"github.com/controlado/go-yazio/pkg/metrics"
+	"github.com/controlado/go-yazio/pkg/report"
+	"github.com/controlado/go-yazio/pkg/visibility"
+	"github.com/controlado/go-yazio/pkg/yazio"
+	"github.com/google/uuid"
+)
+
+const (
+	layoutDay = "2006-01-02"
+
+	defaultRangeDays = 7
+)
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet("yazio "+name, flag.ContinueOnError)
+}
+
+// parse parses args, marking failures as [usageError].
+//
+// The flag package reports parsing errors (and -h) to stderr itself.
+func parse(fs *flag.FlagSet, args []string, stderr io.Writer) error {
+	return parseArgs(fs, args, stderr, 0)
+}
+
+// parseArgs is like [parse], but accepts up to
+// maxArgs positional arguments after the flags.
+func parseArgs(fs *flag.FlagSet, args []string, stderr io.Writer, maxArgs int) error {
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{err: err, reported: true}
+	}
+
+	if fs.NArg() > maxArgs {
+		return usageErrorf("unexpected arguments: %v", fs.Args()[maxArgs:])
+	}
+
+	return nil
+}
+
+func usageErrorf(format string, args ...any) error {
+	return usageError{err: fmt.Errorf(format, args...)}
+}
+
+// rangeFlags registers -from and -to, defaulting to the last week.
+func rangeFlags(fs *flag.FlagSet) func() (date.Range, error) {
+	var (
+		today = time.Now().Format(layoutDay)
+		from  = fs.String("from", "", "first `day` (YYYY-MM-DD), defaults to a week before -to")
+		to    = fs.String("to", today, "last `day` (YYYY-MM-DD)")
+	)
+
+	return func() (r date.Range, err error) {
+		if r.End, err = time.ParseInLocation(layoutDay, *to, time.Local); err != nil {
+			return r, usageErrorf("invalid -to: %w", err)
+		}
+
+		r.Start = r.End.AddDate(0, 0, 1-defaultRangeDays)
+		if *from != "" {
+			if r.Start, err = time.ParseInLocation(layoutDay, *from, time.Local); err != nil {
+				return r, usageErrorf("invalid -from: %w", err)
+			}
+		}
+
+		if r.Start.After(r.End) {
+			return r, usageErrorf("-from %s is after -to %s", r.Start.Format(layoutDay), *to)
+		}
+
+		return r, nil
+	}
+}
+
+// kindsFlag registers -kind, a comma-separated list of intake kinds.
+func kindsFlag(fs *flag.FlagSet, usage string) func() ([]intake.Kind, error) {
+	ids := fs.String("kind", "", usage)
+
+	return func() ([]intake.Kind, error) {
+		if *ids == "" {
+			return nil, nil
+		}
+
+		var ks []intake.Kind
+		for id := range strings.SplitSeq(*ids, ",") {
+			k, err := intake.KindByID(strings.TrimSpace(id))
+			if err != nil {
+				return nil, usageError{err: err}
+			}
+			ks = append(ks, k)
+		}
+
+		return ks, nil
+	}
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+func (a *app) login(ctx context.Context, args []string) error {
+	fs := newFlagSet("login")
+	username := fs.String("username", "", "account `email`; the password is read from "+passwordEnv+" or stdin")
+
+	if err := parse(fs, args, a.stderr); err != nil {
+		return err
+	}
+
+	if *username == "" {
+		return usageErrorf("-username is required")
+	}
+
+	password := os.Getenv(passwordEnv)
+	if password == "" {
+		sc := bufio.NewScanner(a.stdin)
+		if sc.Scan() {
+			password = strings.TrimSpace(sc.Text())
+		}
+	}
+
+	if password == "" {
+		return usageErrorf("no password given through %s or stdin", passwordEnv)
+	}
+
+	u, err := a.api.Login(ctx, yazio.NewPasswordCred(*username, password))
+	if err != nil {
+		return err
+	}
+
+	if err := a.store.Save(session.FromToken(*username, u.Token())); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Logged in as %s\n", *username)
+	return nil
+}
+
+func (a *app) logout(_ context.Context, args []string) error {
+	if err := parse(newFlagSet("logout"), args, a.stderr); err != nil {
+		return err
+	}
+
+	if err := a.store.Clear(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(a.stdout, "Logged out")
+	return nil
+}
+
+func (a *app) whoami(ctx context.Context, args []string) error {
+	fs := newFlagSet("whoami")
+	f := formatFlag(fs, tableFormat)
+
+	if err := parse(fs, args, a.stderr); err != nil {
+		return err
+	}
+
+	u, err := a.user(ctx)
+	if err != nil {
+		return err
+	}
+
+	d, err := u.Data(ctx)
+	if err != nil {
+		return err
+	}
+
+	type profile struct {
+		ID           string `json:"id"`
+		FirstName    string `json:"first_name"`
+		LastName     string `json:"last_name"`
+		Email        string `json:"email"`
+		Registration string `json:"registration"`
+		Birth        string `json:"birth"`
+	}
+
+	p := profile{
+		ID:           d.ID.String(),
+		FirstName:    d.FirstName,
+		LastName:     d.LastName,
+		Email:        d.Email.String(),
+		Registration: d.Registration.Format(layoutDay),
+		Birth:        d.Birth.Format(layoutDay),
+	}
+
+	return output{
+		header: []string{"id", "first_name", "last_name", "email", "registration", "birth"},
+		rows:   [][]string{{p.ID, p.FirstName, p.LastName, p.Email, p.Registration, p.Birth}},
+		value:  p,
+	}.write(a.stdout, *f)
+}
+
+type macrosRow struct {
+	Date    string  `json:"date"`
+	Energy  float64 `json:"energy"`
+	Carb    float64 `json:"carb"`
+	Fat     float64 `json:"fat"`
+	Protein float64 `json:"protein"`
+}
+
+func newMacrosRow(m intake.Macros) macrosRow {
+	return macrosRow{
+		Date:    m.Date.Format(layoutDay),
+		Energy:  m.Energy,
+		Carb:    m.Carb,
+		Fat:     m.Fat,
+		Protein: m.Protein,
+	}
+}
+
+func (r macrosRow) strings() []string {
+	return []string{
+		r.Date,
+		formatNumber(r.Energy),
+		formatNumber(r.Carb),
+		formatNumber(r.Fat),
+		formatNumber(r.Protein),
+	}
+}
+
+var macrosHeader = []string{"date", "energy_kcal", "carb_g", "fat_g", "protein_g"}
+
+func (a *app) macros(ctx context.Context, args []string) error {
+	fs := newFlagSet("macros")
+	var (
+		f         = formatFlag(fs, tableFormat)
+		dateRange = rangeFlags(fs)
+	)
+
+	if err := parse(fs, args, a.stderr); err != nil {
+		return err
+	}
+
+	r, err := dateRange()
+	if err != nil {
+		return err
+	}
+
+	u, err := a.user(ctx)
+	if err != nil {
+		return err
+	}
+
+	mr, err := u.Macros(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	var (
+		out  = output{header: macrosHeader}
+		rows = make([]macrosRow, len(mr))
+	)
+
+	for i, m := range mr {
+		rows[i] = newMacrosRow(m)
+		out.rows = append(out.rows, rows[i].strings())
+	}
+	out.value = rows
+
+	return out.write(a.stdout, *f)
+}
+
+type intakesRow struct {
+	Date    string                  `json:"date"`
+	Intakes map[intake.Kind]float64 `json:"intakes"`
+}
+
+// intakesHeader names each kind column with its unit.
+func intakesHeader(ks []intake.Kind) []string {
+	header := make([]string, len(ks))
+	for i, k := range ks {
+		header[i] = fmt.Sprintf("%s_%s", k, k.Unit())
+	}
+	return header
+}
+
+// intakesColumns returns the values of ks in row,
+// leaving days without data blank.
+func intakesColumns(ks []intake.Kind, row intake.MatrixRow) []string {
+	columns := make([]string, len(ks))
+	for i, k := range ks {
+		if v, ok := row.Values[k]; ok {
+			columns[i] = formatNumber(v)
+		}
+	}
+	return columns
+}
+
+func (a *app) intake(ctx context.Context, args []string) error {
+	fs := newFlagSet("intake")
+	var (
+		f         = formatFlag(fs, tableFormat)
+		dateRange = rangeFlags(fs)
+		kinds     = kindsFlag(fs, "comma-separated intake `kinds` (e.g. vitamin.c,mineral.zinc)")
+	)
+
+	if err := parse(fs, args, a.stderr); err != nil {
+		return err
+	}
+
+	r, err := dateRange()
+	if err != nil {
+		return err
+	}
+
+	ks, err := kinds()
+	if err != nil {
+		return err
+	}
+	if len(ks) == 0 {
+		return usageErrorf("-kind is required")
+	}
+
+	u, err := a.user(ctx)
+	if err != nil {
+		return err
+	}
+
+	m, err := u.Intakes(ctx, ks, r)
+	if err != nil {
+		return err
+	}
+
+	var (
+		out  = output{header: slices.Concat([]string{"date"}, intakesHeader(m.Kinds))}
+		rows = make([]intakesRow, len(m.Rows))
+	)
+
+	for i, row := range m.Rows {
+		day := row.Date.Format(layoutDay)
+		rows[i] = intakesRow{Date: day, Intakes: row.Values}
+		out.rows = append(out.rows, append([]string{day}, intakesColumns(m.Kinds, row)...))
+	}
+	out.value = rows
+
+	return out.write(a.stdout, *f)
+}
+
+// pairsFlag collects repeated "key=value" flags with numeric values.
+type pairsFlag struct {
+	keys   []string
+	values []float64
+}
+
+func (p *pairsFlag) String() string {
+	pairs := make([]string, len(p.keys))
+	for i, k := range p.keys {
+		pairs[i] = fmt.Sprintf("%s=%g", k, p.values[i])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (p *pairsFlag) Set(s string) error {
+	key, raw, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("%q is not key=value", s)
+	}
+
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return fmt.Errorf("value of %q: %w", key, err)
+	}
+
+	p.keys = append(p.keys, strings.TrimSpace(key))
+	p.values = append(p.values, v)
+	return nil
+}
+
+func (a *app) addFood(ctx context.Context, args []string) error {
+	fs := newFlagSet("add-food")
+	var (
+		name      = fs.String("name", "", "food `name`")
+		category  = fs.String("category", food.Miscellaneous.String(), "food `category`")
+		baseUnit  = fs.String("unit", unit.Gram.String(), "base `unit` of the nutrients: g or ml")
+		energy    = fs.Float64("energy", 0, "energy (kcal) per 100 base units")
+		carb      = fs.Float64("carb", 0, "carbohydrate (g) per 100 base units")
+		fat       = fs.Float64("fat", 0, "fat (g) per 100 base units")
+		protein   = fs.Float64("protein", 0, "protein (g) per 100 base units")
+		public    = fs.Bool("public", false, "make the food visible to every user")
+		nutrients pairsFlag
+		servings  pairsFlag
+	)
+	fs.Var(&nutrients, "nutrient", "extra nutrient as `kind=value` per 100 base units (repeatable)")
+	fs.Var(&servings, "serving", "serving as `kind=amount` in base units (repeatable)")
+
+	if err := parse(fs, args, a.stderr); err != nil {
+		return err
+	}
+
+	cat, err := food.ParseCategory(*category)
+	if err != nil {
+		return err
+	}
+
+	bu := unit.Base(*baseUnit)
+	if bu != unit.Gram && bu != unit.Milliliter {
+		return usageErrorf("invalid -unit %q: want g or ml", *baseUnit)
+	}
+
+	nut := food.Nutrients{
+		intake.Energy:  *energy,
+		intake.Carb:    *carb,
+		intake.Fat:     *fat,
+		intake.Protein: *protein,
+	}
+	for i, id := range nutrients.keys {
+		k, err := intake.KindByID(id)
+		if err != nil {
+			return usageError{err: err}
+		}
+		nut[k] = nutrients.values[i]
+	}
+
+	opts := []food.Option{food.WithBaseUnit(bu)}
+	for i, kind := range servings.keys {
+		sk, err := food.ParseServingKind(kind)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, food.WithNewServing(sk, servings.values[i]))
+	}
+
+	f, err := food.New(*name, cat, nut, opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	u, err := a.user(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := u.AddFood(ctx, f, visibility.Food(*public)); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(a.stdout, f.ID)
+	return nil
+}
+
+func (a *app) logFood(ctx context.Context, args []string) error {
+	fs := newFlagSet("log")
+	var (
+		foodID  = fs.String("food", "", "food `id`")
+		mealArg = fs.String("meal", meal.Snack.String(), "`meal`: breakfast, lunch, dinner or snack")
+		kind    = fs.String("serving", food.Portion.String(), "serving `kind`")
+		amount  = fs.Float64("amount", 100, "serving amount in base units")
+	)
+
+	if err := parse(fs, args, a.stderr); err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(*foodID)
+	if err != nil {
+		return usageErrorf("invalid -food %q: %w", *foodID, err)
+	}
+
+	mealTime, err := meal.Parse(*mealArg)
+	if err != nil {
+		return usageError{err: err}
+	}
+
+	sk, err := food.ParseServingKind(*kind)
+	if err != nil {
+		return usageError{err: err}
+	}
+
+	if *amount <= 0 {
+		return usageErrorf("invalid -amount %g: must be positive", *amount)
+	}
+
+	u, err := a.user(ctx)
+	if err != nil {
+		return err
+	}
+
+	serving := food.Serving{Kind: sk, Amount: *amount}
+	if err := u.EntryFood(ctx, mealTime, id, serving); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Logged %s (%g) of %s as %s\n", sk, *amount, id, mealTime)
+	return nil
+}
+
+func (a *app) export(ctx context.Context, args []string) error {
+	fs := newFlagSet("export")
+	var (
+		dateRange = rangeFlags(fs)
+		kinds     = kindsFlag(fs, "comma-separated intake `kinds` to export along with macros")
+		formatArg = fs.String("format", export.CSV.String(), "export `format`: csv, json or ndjson")
+		entries   = fs.Bool("entries", false, "export diary entries (one request per day)")
+		path      = fs.String("o", "", "output `file`, defaults to stdout")
+	)
+
+	if err := parse(fs, args, a.stderr); err != nil {
+		return err
+	}
+
+	r, err := dateRange()
+	if err != nil {
+		return err
+	}
+
+	ks, err := kinds()
+	if err != nil {
+		return err
+	}
+
+	f, err := export.ParseFormat(*formatArg)
+	if err != nil {
+		return usageError{err: err}
+	}
+
+	opts := []export.Option{export.WithKinds(ks...)}
+	if *entries {
+		opts = append(opts, export.WithEntries())
+	}
+
+	u, err := a.user(ctx)
+	if err != nil {
+		return err
+	}
+
+	e := export.New(u, opts...)
+	if *path == "" {
+		return e.Export(ctx, a.stdout, f, r)
+	}
+
+	file, err := os.Create(*path)
+	if err != nil {
+		return err
+	}
+
+	if err := e.Export(ctx, file, f, r); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func (a *app) importCSV(ctx context.Context, args []string) error {
+	fs := newFlagSet("import")
+	var (
+		sourceArg  = fs.String("source", importer.CSV.String(), "`source` of the file: csv, myfitnesspal (mfp) or cronometer")
+		dryRun     = fs.Bool("dry-run", false, "report what would be imported, without importing")
+		comma      = fs.String("comma", ",", "field `delimiter`")
+		dateLayout = fs.String("date-layout", time.DateOnly, "`layout` of the date column")
+	)
+
+	if err := parseArgs(fs, args, a.stderr, 1); err != nil {
+		return err
+	}
+
+	src, err := importer.ParseSource(*sourceArg)
+	if err != nil {
+		return usageError{err: err}
+	}
+
+	delim := []rune(*comma)
+	if len(delim) != 1 {
+		return usageErrorf("invalid -comma %q: must be a single character", *comma)
+	}
+
+	var in io.Reader = a.stdin
+	if path := fs.Arg(0); path != "" && path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	opts := []importer.Option{
+		importer.WithComma(delim[0]),
+		importer.WithDateLayout(*dateLayout),
+	}
+	if *dryRun {
+		opts = append(opts, importer.DryRun())
+	}
+
+	u, err := a.user(ctx)
+	if err != nil {
+		return err
+	}
+
+	rep, err := importer.New(u, opts...).ImportFrom(ctx, src, in)
+	if err != nil {
+		return err
+	}
+
+	for _, re := range rep.Errors {
+		fmt.Fprintln(a.stderr, re)
+	}
+
+	if *dryRun {
+		fmt.Fprint(a.stdout, "Dry run: ")
+	}
+	fmt.Fprintln(a.stdout, rep)
+
+	return rep.Err()
+}
+
+func (a *app) metrics(ctx context.Context, args []string) error {
+	fs := newFlagSet("metrics")
+	var (
+		addr     = fs.String("addr", "localhost:9464", "listen `address` of /metrics")
+		kinds    = kindsFlag(fs, "comma-separated intake `kinds` to poll along with macros")
+		days     = fs.Int("days", defaultRangeDays, "polled `days`, up to today")
+		interval = fs.Duration("interval", 15*time.Minute, "`time` between polls")
+	)
+
+	if err := parse(fs, args, a.stderr); err != nil {
+		return err
+	}
+
+	ks, err := kinds()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *days < 1:
+		return usageErrorf("invalid -days %d: must be positive", *days)
+	case *interval <= 0:
+		return usageErrorf("invalid -interval %s: must be positive", *interval)
+	}
+
+	rq := metrics.NewRequester(http.DefaultClient)
+	api, err := yazio.New(append(slices.Clone(a.apiOpts), yazio.WithRequester(rq))...)
+	if err != nil {
+		return err
+	}
+
+	// fail fast on a missing or expired session
+	if _, err := a.store.Resume(ctx, api); err != nil {
+		return err
+	}
+
+	p := metrics.NewPoller(
+		func(ctx context.Context) (metrics.User, error) {
+			u, err := a.store.Resume(ctx, api)
+			if err != nil {
+				return nil, err
+			}
+			return u, nil
+		},
+		metrics.WithKinds(ks...),
+		metrics.WithDays(*days),
+		metrics.WithInterval(*interval),
+		metrics.WithErrorHandler(func(err error) {
+			fmt.Fprintf(a.stderr, "yazio metrics: polling: %v\n", err)
+		}),
+	)
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Handler(p, rq))
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go p.Run(ctx)
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	fmt.Fprintf(a.stdout, "Serving metrics on http://%s/metrics\n", ln.Addr())
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func (a *app) report(ctx context.Context, args []string) error {
+	fs := newFlagSet("report")
+	var (
+		dateRange = rangeFlags(fs)
+		kinds     = kindsFlag(fs, "comma-separated intake `kinds` to highlight")
+		formatArg = fs.String("format", report.Markdown.String(), "report `format`: markdown or html")
+		periodArg = fs.String("period", "", "break days down by `period`: week or month")
+		sex       = fs.String("sex", "", "`sex` (female or male) to compare kinds with reference intakes")
+		path      = fs.String("o", "", "output `file`, defaults to stdout")
+		goals     report.Goals
+	)
+	fs.Float64Var(&goals.Energy, "energy", 0, "daily energy goal (kcal)")
+	fs.Float64Var(&goals.Carb, "carb", 0, "daily carbohydrate goal (g)")
+	fs.Float64Var(&goals.Fat, "fat", 0, "daily fat goal (g)")
+	fs.Float64Var(&goals.Protein, "protein", 0, "daily protein goal (g)")
+
+	if err := parse(fs, args, a.stderr); err != nil {
+		return err
+	}
+
+	r, err := dateRange()
+	if err != nil {
+		return err
+	}
+
+	ks, err := kinds()
+	if err != nil {
+		return err
+	}
+
+	f, err := report.ParseFormat(*formatArg)
+	if err != nil {
+		return usageError{err: err}
+	}
+
+	opts := []report.Option{report.WithKinds(ks...), report.WithGoals(goals)}
+
+	switch *periodArg {
+	case "":
+	case "week":
+		opts = append(opts, report.WithPeriod(date.Weekly()))
+	case "month":
+		opts = append(opts, report.WithPeriod(date.Monthly()))
+	default:
+		return usageErrorf("invalid -period %q: want week or month", *periodArg)
+	}
+
+	u, err := a.user(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *sex != "" {
+		d, err := u.Data(ctx)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, report.WithProfile(reference.ProfileOf(d, reference.Sex(*sex))))
+	}
+
+	rep, err := report.New(u, opts...).Build(ctx, r)
+	if err != nil {
+		if errors.Is(err, reference.ErrUnknownSex) {
+			return usageError{err: err}
+		}
+		return err
+	}
+
+	if *path == "" {
+		return rep.Render(a.stdout, f)
+	}
+
+	file, err := os.Create(*path)
+	if err != nil {
+		return err
+	}
+
+	if err := rep.Render(file, f); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func (a *app) calendar(ctx context.Context, args []string) error {
+	fs := newFlagSet("calendar")
+	var (
+		dateRange = rangeFlags(fs)
+		name      = fs.String("name", "YAZIO", "calendar `name` shown by calendar apps")
+		duration  = fs.Duration("duration", 30*time.Minute, "how long meal events last after their last entry")
+		path      = fs.String("o", "", "output `file`, defaults to stdout")
+	)
+
+	if err := parse(fs, args, a.stderr); err != nil {
+		return err
+	}
+
+	r, err := dateRange()
+	if err != nil {
+		return err
+	}
+
+	if *duration < 0 {
+		return usageErrorf("invalid -duration %s: must not be negative", *duration)
+	}
+
+	u, err := a.user(ctx)
+	if err != nil {
+		return err
+	}
+
+	e := ics.New(u, ics.WithName(*name), ics.WithDuration(*duration))
+	if *path == "" {
+		return e.Export(ctx, a.stdout, r)
+	}
+
+	file, err := os.Create(*path)
+	if err != nil {
+		return err
+	}
+
+	if err := e.Export(ctx, file, r); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
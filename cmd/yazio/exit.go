@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/controlado/go-yazio/internal/session"
+	"github.com/controlado/go-yazio/pkg/domain/diary"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/yazio"
+)
+
+// Exit codes
+const (
+	exitOK                 = 0
+	exitFailure            = 1 // exitFailure is any error not listed below.
+	exitUsage              = 2
+	exitInvalidCredentials = 3
+	exitSessionExpired     = 4 // exitSessionExpired also covers missing sessions.
+	exitUnavailable        = 5
+	exitBadResponse        = 6
+	exitInvalidFood        = 7
+	exitConflict           = 8 // exitConflict is a food or diary entry that already exists.
+)
+
+// usageError marks errors caused by the command line.
+type usageError struct {
+	err      error
+	reported bool // reported by the flag package already
+}
+
+func (e usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e usageError) Unwrap() error {
+	return e.err
+}
+
+// exitCode maps err to the exit code of the command,
+// checking the most specific errors first.
+func exitCode(err error) int {
+	var ue usageError
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &ue):
+		return exitUsage
+	case errors.Is(err, session.ErrExpired),
+		errors.Is(err, session.ErrNoSession),
+		errors.Is(err, session.ErrCorruptSession),
+		errors.Is(err, yazio.ErrExpiredToken):
+		return exitSessionExpired
+	case errors.Is(err, yazio.ErrInvalidCredentials):
+		return exitInvalidCredentials
+	case errors.Is(err, food.ErrAlreadyExists),
+		errors.Is(err, diary.ErrAlreadyExists):
+		return exitConflict
+	case food.IsValidationError(err):
+		return exitInvalidFood
+	case errors.Is(err, yazio.ErrDecodingResponse):
+		return exitBadResponse
+	case errors.Is(err, yazio.ErrRequestingToYazio):
+		return exitUnavailable
+	default:
+		return exitFailure
+	}
+}
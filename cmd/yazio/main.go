@@ -0,0 +1,185 @@
+// Command yazio is a command-line client for the YAZIO API.
+//
+// Usage:
+//
+//	yazio [-base-url URL] [-session PATH] <command> [flags]
+//
+// The commands are:
+//
+//	login     log in and store the session
+//	logout    remove the stored session
+//	whoami    show the profile of the logged user
+//	macros    show daily macros of a date range
+//	intake    show daily intakes of nutrients of a date range
+//	add-food  register a new food
+//	log       log a food in today's diary
+//	export    export the diary history as CSV, JSON or NDJSON
+//	import    import diary history from CSV, MyFitnessPal or Cronometer
+//	metrics   serve nutrition and client metrics to Prometheus
+//	report    summarize a date range as Markdown or HTML
+//	calendar  export the meals of a date range as an iCalendar feed
+//
+// Sessions are stored in the user configuration directory, and
+// refreshed when expired. The environment variables YAZIO_BASE_URL
+// and YAZIO_SESSION override the defaults of the global flags, and
+// YAZIO_PASSWORD provides the password of login.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/controlado/go-yazio/internal/session"
+	"github.com/controlado/go-yazio/pkg/yazio"
+)
+
+const (
+	baseURLEnv  = "YAZIO_BASE_URL"
+	sessionEnv  = "YAZIO_SESSION"
+	passwordEnv = "YAZIO_PASSWORD"
+)
+
+type command struct {
+	summary string
+	run     func(a *app, ctx context.Context, args []string) error
+}
+
+var commands = map[string]command{
+	"login":    {"log in and store the session", (*app).login},
+	"logout":   {"remove the stored session", (*app).logout},
+	"whoami":   {"show the profile of the logged user", (*app).whoami},
+	"macros":   {"show daily macros of a date range", (*app).macros},
+	"intake":   {"show daily intakes of nutrients of a date range", (*app).intake},
+	"add-food": {"register a new food", (*app).addFood},
+	"log":      {"log a food in today's diary", (*app).logFood},
+	"export":   {"export the diary history as CSV, JSON or NDJSON", (*app).export},
+	"import":   {"import diary history from CSV, MyFitnessPal or Cronometer", (*app).importCSV},
+	"metrics":  {"serve nutrition and client metrics to Prometheus", (*app).metrics},
+	"report":   {"summarize a date range as Markdown or HTML", (*app).report},
+	"calendar": {"export the meals of a date range as an iCalendar feed", (*app).calendar},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line args, returning the exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("yazio", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { usage(stderr) }
+
+	var (
+		baseURL     = fs.String("base-url", os.Getenv(baseURLEnv), "YAZIO API base `url`")
+		sessionPath = fs.String("session", os.Getenv(sessionEnv), "session file `path`")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	if fs.NArg() == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	name := fs.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "yazio: unknown command %q\n", name)
+		usage(stderr)
+		return exitUsage
+	}
+
+	a, err := newApp(*baseURL, *sessionPath, stdin, stdout, stderr)
+	if err == nil {
+		err = cmd.run(a, ctx, fs.Args()[1:])
+	}
+
+	var ue usageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &ue) && ue.reported:
+	default:
+		fmt.Fprintf(stderr, "yazio %s: %v\n", name, err)
+	}
+
+	return exitCode(err)
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("Usage: yazio [-base-url url] [-session path] <command> [flags]\n\nCommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %-9s %s\n", name, commands[name].summary)
+	}
+	b.WriteString("\nRun 'yazio <command> -h' for the flags of a command.\n")
+
+	io.WriteString(w, b.String())
+}
+
+// app holds what the commands share.
+type app struct {
+	api     *yazio.API
+	apiOpts []yazio.Option // apiOpts are the options api was built with.
+	store   *session.Store
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+}
+
+func newApp(baseURL, sessionPath string, stdin io.Reader, stdout, stderr io.Writer) (*app, error) {
+	var opts []yazio.Option
+	if baseURL != "" {
+		opts = append(opts, yazio.WithBaseURL(baseURL))
+	}
+
+	api, err := yazio.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	store := session.NewStore(sessionPath)
+	if sessionPath == "" {
+		if store, err = session.DefaultStore(); err != nil {
+			return nil, err
+		}
+	}
+
+	a := &app{
+		api:     api,
+		apiOpts: opts,
+		store:   store,
+		stdin:   stdin,
+		stdout:  stdout,
+		stderr:  stderr,
+	}
+
+	return a, nil
+}
+
+// user resumes the stored session, refreshing
+// (and storing again) an expired token.
+func (a *app) user(ctx context.Context) (*yazio.User, error) {
+	return a.store.Resume(ctx, a.api)
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/session"
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/yazio"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
+)
+
+// cli runs commands against a fake YAZIO, sharing a session file.
+type cli struct {
+	t           *testing.T
+	baseURL     string
+	sessionPath string
+}
+
+func newCLI(t *testing.T, fake *yaziotest.Server) *cli {
+	return &cli{
+		t:           t,
+		baseURL:     fake.URL,
+		sessionPath: filepath.Join(t.TempDir(), "session.json"),
+	}
+}
+
+func (c *cli) run(stdin string, args ...string) (code int, stdout, stderr string) {
+	c.t.Helper()
+
+	var (
+		out, errOut bytes.Buffer
+		global      = []string{"-base-url", c.baseURL, "-session", c.sessionPath}
+	)
+
+	code = run(context.Background(), append(global, args...), strings.NewReader(stdin), &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func (c *cli) login() {
+	c.t.Helper()
+
+	code, _, stderr := c.run(yaziotest.DefaultPassword+"\n", "login", "-username", yaziotest.DefaultUsername)
+	if code != exitOK {
+		c.t.Fatalf("login exited with %d: %s", code, stderr)
+	}
+}
+
+func TestRun_Workflow(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fake  = yaziotest.New(t)
+		c     = newCLI(t, fake)
+		today = time.Now().Format(layoutDay)
+	)
+
+	c.login()
+
+	code, stdout, _ := c.run("", "whoami", "-format", "json")
+	assert.Equal(t, code, exitOK)
+
+	var profile map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &profile))
+	assert.Equal(t, profile["first_name"], "João")
+
+	code, stdout, stderr := c.run("", "add-food",
+		"-name", "Oats",
+		"-category", "cereals",
+		"-energy", "380", "-carb", "60", "-fat", "7", "-protein", "13",
+		"-nutrient", "nutrient.dietaryfiber=10",
+		"-serving", "bowl=50",
+	)
+	assert.Equal(t, code, exitOK)
+	assert.Equal(t, stderr, "")
+
+	foodID := strings.TrimSpace(stdout)
+	assert.Equal(t, len(fake.Products()), 1)
+	assert.Equal(t, fake.Products()[0].ID.String(), foodID)
+
+	code, _, stderr = c.run("", "log", "-food", foodID, "-meal", "breakfast", "-serving", "bowl", "-amount", "50")
+	assert.Equal(t, code, exitOK)
+	assert.Equal(t, stderr, "")
+	assert.Equal(t, len(fake.Consumed(yaziotest.DefaultUsername)), 1)
+
+	code, stdout, _ = c.run("", "macros", "-from", today, "-to", today, "-format", "csv")
+	assert.Equal(t, code, exitOK)
+
+	records, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, records, [][]string{
+		macrosHeader,
+		{today, "190", "30", "3.5", "6.5"},
+	})
+
+	code, stdout, _ = c.run("", "intake", "-kind", "nutrient.dietaryfiber", "-from", today, "-to", today)
+	assert.Equal(t, code, exitOK)
+	assert.Equal(t, strings.Contains(stdout, "nutrient.dietaryfiber_g"), true)
+	assert.Equal(t, strings.Contains(stdout, today+"  5"), true)
+
+	exported := filepath.Join(t.TempDir(), "export.json")
+	code, _, _ = c.run("", "export", "-from", today, "-to", today, "-kind", "nutrient.dietaryfiber", "-entries", "-format", "json", "-o", exported)
+	assert.Equal(t, code, exitOK)
+
+	raw, err := os.ReadFile(exported)
+	assert.NoError(t, err)
+
+	var days []struct {
+		Date   string `json:"date"`
+		Macros struct {
+			Energy float64 `json:"energy"`
+		} `json:"macros"`
+		Intakes map[string]float64 `json:"intakes"`
+		Entries []struct {
+			Meal string `json:"meal"`
+		} `json:"entries"`
+	}
+	assert.NoError(t, json.Unmarshal(raw, &days))
+	assert.Equal(t, len(days), 1)
+	assert.Equal(t, days[0].Macros.Energy, 190.0)
+	assert.Equal(t, days[0].Intakes["nutrient.dietaryfiber"], 5.0)
+	assert.Equal(t, days[0].Entries[0].Meal, "breakfast")
+
+	code, stdout, _ = c.run("", "report", "-from", today, "-to", today, "-energy", "200", "-kind", "nutrient.dietaryfiber", "-sex", "female")
+	assert.Equal(t, code, exitOK)
+	assert.Equal(t, strings.HasPrefix(stdout, "# Daily report\n"), true)
+	assert.Equal(t, strings.Contains(stdout, "| Average of goal   | 95% |"), true)
+	assert.Equal(t, strings.Contains(stdout, "| nutrient.dietaryfiber | 5 g |"), true)
+
+	code, stdout, _ = c.run("", "calendar", "-from", today, "-to", today, "-name", "Meals")
+	assert.Equal(t, code, exitOK)
+	assert.Equal(t, strings.HasPrefix(stdout, "BEGIN:VCALENDAR\r\n"), true)
+	assert.Equal(t, strings.Contains(stdout, "X-WR-CALNAME:Meals\r\n"), true)
+	assert.Equal(t, strings.Contains(stdout, "SUMMARY:Breakfast: 190 kcal\r\n"), true)
+	assert.Equal(t, strings.Contains(stdout, `DESCRIPTION:Oats: 1 bowl (50 g)\, 190 kcal`), true)
+
+	const sheet = "date,meal,product,amount,serving\n" +
+		"2025-04-10,lunch,Oats,50,bowl\n" +
+		"2025-04-10,brunch,Oats,50,bowl\n"
+
+	code, stdout, stderr = c.run(sheet, "import", "-dry-run")
+	assert.Equal(t, code, exitFailure)
+	assert.Equal(t, stdout, "Dry run: 2 rows: 0 imported, 0 skipped, 2 failed, 0 foods created\n")
+	assert.Equal(t, strings.Contains(stderr, "unknown"), true)
+
+	sheetPath := filepath.Join(t.TempDir(), "history.csv")
+	assert.NoError(t, os.WriteFile(sheetPath, []byte(strings.Replace(sheet, "Oats", foodID, 2)), 0o600))
+
+	code, stdout, _ = c.run("", "import", "-dry-run", sheetPath)
+	assert.Equal(t, code, exitFailure)
+	assert.Equal(t, stdout, "Dry run: 2 rows: 1 imported, 0 skipped, 1 failed, 0 foods created\n")
+	assert.Equal(t, len(fake.Consumed(yaziotest.DefaultUsername)), 1)
+
+	code, stdout, stderr = c.run("", "import", sheetPath)
+	assert.Equal(t, code, exitFailure)
+	assert.Equal(t, stdout, "2 rows: 1 imported, 0 skipped, 1 failed, 0 foods created\n")
+	assert.Equal(t, strings.Contains(stderr, "line 3"), true)
+	assert.Equal(t, len(fake.Consumed(yaziotest.DefaultUsername)), 2)
+
+	code, stdout, _ = c.run("", "logout")
+	assert.Equal(t, code, exitOK)
+	assert.Equal(t, stdout, "Logged out\n")
+
+	code, _, _ = c.run("", "whoami")
+	assert.Equal(t, code, exitSessionExpired)
+}
+
+func TestRun_RefreshesExpiredSession(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fake  = yaziotest.New(t)
+		c     = newCLI(t, fake)
+		store = session.NewStore(c.sessionPath)
+	)
+
+	c.login()
+
+	sess, err := store.Load()
+	assert.NoError(t, err)
+
+	fake.ExpireTokens()
+	sess.ExpiresAt = time.Now().Add(-time.Minute)
+	assert.NoError(t, store.Save(sess))
+
+	code, _, stderr := c.run("", "whoami")
+	assert.Equal(t, code, exitOK)
+	assert.Equal(t, stderr, "")
+
+	refreshed, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, refreshed.ExpiresAt.After(time.Now()), true)
+	assert.Equal(t, refreshed.Refresh != sess.Refresh, true)
+}
+
+func TestRun_Metrics(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fake = yaziotest.New(t)
+		c    = newCLI(t, fake)
+	)
+
+	c.login()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := ln.Addr().String()
+	assert.NoError(t, ln.Close())
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan int)
+		args        = []string{"-base-url", c.baseURL, "-session", c.sessionPath, "metrics", "-addr", addr, "-kind", "nutrient.water"}
+	)
+
+	go func() {
+		done <- run(ctx, args, strings.NewReader(""), io.Discard, io.Discard)
+	}()
+
+	var body string
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		resp, err := http.Get("http://" + addr + "/metrics")
+		if err != nil {
+			continue
+		}
+
+		raw, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.NoError(t, err)
+
+		if body = string(raw); strings.Contains(body, `yazio_polls_total{result="success"} 1`) {
+			break
+		}
+	}
+
+	assert.Equal(t, strings.Contains(body, `yazio_polls_total{result="success"} 1`), true)
+	assert.Equal(t, strings.Contains(body, "# TYPE yazio_daily_intake gauge"), true)
+	assert.Equal(t, strings.Contains(body, `yazio_client_requests_total{endpoint="/v18/user/consumed-items/nutrients-daily",method="GET",code="200"}`), true)
+
+	cancel()
+	assert.Equal(t, <-done, exitOK)
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	t.Parallel()
+
+	fake := yaziotest.New(t)
+
+	testBlocks := []struct {
+		name     string
+		loggedIn bool
+		stdin    string
+		args     []string
+		want     int
+	}{
+		{name: "no command", want: exitUsage},
+		{name: "help", args: []string{"-h"}, want: exitOK},
+		{name: "unknown command", args: []string{"eat"}, want: exitUsage},
+		{name: "unknown flag", args: []string{"macros", "-days", "3"}, want: exitUsage},
+		{name: "command help", args: []string{"macros", "-h"}, want: exitOK},
+		{name: "invalid date", loggedIn: true, args: []string{"macros", "-from", "12/04/2025"}, want: exitUsage},
+		{name: "unknown kind", loggedIn: true, args: []string{"intake", "-kind", "vitamin.z"}, want: exitUsage},
+		{name: "unknown export format", loggedIn: true, args: []string{"export", "-format", "xml"}, want: exitUsage},
+		{name: "unknown import source", loggedIn: true, args: []string{"import", "-source", "loseit"}, want: exitUsage},
+		{name: "several import files", loggedIn: true, args: []string{"import", "a.csv", "b.csv"}, want: exitUsage},
+		{name: "unknown report format", loggedIn: true, args: []string{"report", "-format", "pdf"}, want: exitUsage},
+		{name: "unknown report sex", loggedIn: true, args: []string{"report", "-kind", "vitamin.c", "-sex", "other"}, want: exitUsage},
+		{name: "invalid metrics days", loggedIn: true, args: []string{"metrics", "-days", "0"}, want: exitUsage},
+		{name: "negative calendar duration", loggedIn: true, args: []string{"calendar", "-duration", "-1m"}, want: exitUsage},
+		{name: "metrics without session", args: []string{"metrics", "-addr", "127.0.0.1:0"}, want: exitSessionExpired},
+		{name: "missing session", args: []string{"whoami"}, want: exitSessionExpired},
+		{
+			name:  "wrong password",
+			stdin: "wrong\n",
+			args:  []string{"login", "-username", yaziotest.DefaultUsername},
+			want:  exitInvalidCredentials,
+		},
+		{
+			name:     "invalid food",
+			loggedIn: true,
+			args:     []string{"add-food", "-name", "Air", "-energy", "900", "-carb", "1"},
+			want:     exitInvalidFood,
+		},
+		{
+			name:     "unknown category",
+			loggedIn: true,
+			args:     []string{"add-food", "-name", "Gizmo", "-category", "gadgets"},
+			want:     exitInvalidFood,
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newCLI(t, fake)
+			if tb.loggedIn {
+				c.login()
+			}
+
+			code, _, _ := c.run(tb.stdin, tb.args...)
+			assert.Equal(t, code, tb.want)
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		err  error
+		want int
+	}{
+		{nil, exitOK},
+		{errors.New("boom"), exitFailure},
+		{usageErrorf("bad flag"), exitUsage},
+		{yazio.ErrInvalidCredentials, exitInvalidCredentials},
+		{fmt.Errorf("%w: %w", session.ErrExpired, yazio.ErrInvalidCredentials), exitSessionExpired},
+		{yazio.ErrExpiredToken, exitSessionExpired},
+		{session.ErrNoSession, exitSessionExpired},
+		{fmt.Errorf("%w: %w", yazio.ErrRequestingToYazio, errors.New("dial")), exitUnavailable},
+		{fmt.Errorf("%w: %w", yazio.ErrDecodingResponse, errors.New("eof")), exitBadResponse},
+		{errors.Join(food.ErrNegativeNutrient, food.ErrDuplicateServing), exitInvalidFood},
+		{food.ErrAlreadyExists, exitConflict},
+		{&yazio.IntakesError{Failed: []yazio.KindError{{Err: yazio.ErrExpiredToken}}}, exitSessionExpired},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(fmt.Sprint(tb.err), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, exitCode(tb.err), tb.want)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	tableFormat = "table"
+	jsonFormat  = "json"
+	csvFormat   = "csv"
+)
+
+// format is a flag.Value restricted to the output formats.
+type format string
+
+func (f *format) String() string {
+	return string(*f)
+}
+
+func (f *format) Set(s string) error {
+	switch s {
+	case tableFormat, jsonFormat, csvFormat:
+		*f = format(s)
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (want %s, %s or %s)", s, tableFormat, jsonFormat, csvFormat)
+	}
+}
+
+func formatFlag(fs *flag.FlagSet, def format) *format {
+	f := def
+	fs.Var(&f, "format", "output `format`: table, json or csv")
+	return &f
+}
+
+// output is what a command prints: rows for table and CSV,
+// and value (usually the same data, typed) for JSON.
+type output struct {
+	header []string
+	rows   [][]string
+	value  any
+}
+
+func (o output) write(w io.Writer, f format) error {
+	switch f {
+	case jsonFormat:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(o.value)
+
+	case csvFormat:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(o.header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(o.rows); err != nil { // flushes
+			return err
+		}
+		return cw.Error()
+
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(o.header, "\t"))
+		for _, row := range o.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+}
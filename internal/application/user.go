@@ -2,8 +2,10 @@ package application
 
 import (
 	"context"
+	"time"
 
 	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/diary"
 	"github.com/controlado/go-yazio/pkg/domain/food"
 	"github.com/controlado/go-yazio/pkg/domain/intake"
 	"github.com/controlado/go-yazio/pkg/domain/meal"
@@ -16,6 +18,10 @@ type User interface {
 	Data(context.Context) (user.Data, error)
 	AddFood(context.Context, food.Food, visibility.Food) error
 	EntryFood(context.Context, meal.Time, food.ID, food.Serving) error
+	AddEntry(context.Context, diary.Entry) error
 	Macros(context.Context, date.Range) (intake.MacrosRange, error)
 	Intake(context.Context, intake.Kind, date.Range) (intake.SingleRange, error)
+	Intakes(context.Context, []intake.Kind, date.Range) (intake.Matrix, error)
+	Diary(context.Context, time.Time) ([]diary.Entry, error)
+	Food(context.Context, food.ID) (food.Food, error)
 }
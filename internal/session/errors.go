@@ -0,0 +1,9 @@
+package session
+
+import "errors"
+
+var (
+	ErrNoSession      = errors.New("no stored session, log in first")
+	ErrCorruptSession = errors.New("stored session is corrupt")
+	ErrExpired        = errors.New("session expired, log in again")
+)
@@ -0,0 +1,186 @@
+// Package session persists YAZIO logins between runs of the
+// commands, so users don't have to log in on every call.
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/application"
+	"github.com/controlado/go-yazio/pkg/yazio"
+)
+
+const (
+	dirName  = "yazio"
+	fileName = "session.json"
+
+	// filePerm keeps tokens readable by their owner only.
+	filePerm fs.FileMode = 0o600
+	dirPerm  fs.FileMode = 0o700
+)
+
+// Session is a stored YAZIO login.
+type Session struct {
+	Username  string    `json:"username"`
+	Access    string    `json:"access_token"`
+	Refresh   string    `json:"refresh_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// FromToken returns the [Session] of username holding tk.
+func FromToken(username string, tk application.Token) Session {
+	return Session{
+		Username:  username,
+		Access:    tk.Access(),
+		Refresh:   tk.Refresh(),
+		ExpiresAt: tk.ExpiresAt(),
+	}
+}
+
+// Token returns the token held by s, ready for [yazio.API.Resume].
+func (s Session) Token() *yazio.Token {
+	return yazio.NewToken(s.Access, s.Refresh, s.ExpiresAt)
+}
+
+// Store reads and writes a [Session] to a file.
+//
+// Instances of Store should be created using [NewStore] or [DefaultStore].
+type Store struct {
+	path string
+}
+
+// NewStore returns a [*Store] backed by the file at path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultStore returns a [*Store] backed by a file
+// in the user configuration directory.
+func DefaultStore() (*Store, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("locating config dir: %w", err)
+	}
+	return NewStore(filepath.Join(dir, dirName, fileName)), nil
+}
+
+// Path returns the file backing s.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load reads the stored session.
+//
+// On failure the error wraps either:
+//   - [ErrNoSession]
+//   - [ErrCorruptSession]
+//   - Other: generic (file system related)
+func (s *Store) Load() (Session, error) {
+	var sess Session
+
+	raw, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return sess, ErrNoSession
+		}
+		return sess, fmt.Errorf("reading session: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, &sess); err != nil {
+		return sess, fmt.Errorf("%w: %w", ErrCorruptSession, err)
+	}
+
+	if sess.Refresh == "" {
+		return sess, fmt.Errorf("%w: blank refresh token", ErrCorruptSession)
+	}
+
+	return sess, nil
+}
+
+// Save writes sess, replacing the stored one.
+//
+// The file is written atomically with owner-only permissions.
+func (s *Store) Save(sess Session) error {
+	raw, err := json.MarshalIndent(sess, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding session: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return fmt.Errorf("creating session dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, fileName+".*")
+	if err != nil {
+		return fmt.Errorf("creating session file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op after rename
+
+	if err := tmp.Chmod(filePerm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("restricting session file: %w", err)
+	}
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing session: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing session: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("replacing session: %w", err)
+	}
+
+	return nil
+}
+
+// Resume returns the user of the stored session, refreshing
+// (and storing again) an expired token.
+//
+// On failure the error wraps either:
+//   - [ErrNoSession]
+//   - [ErrCorruptSession]
+//   - [ErrExpired] if YAZIO rejects the refresh token
+//   - The errors of [yazio.API.Refresh]
+func (s *Store) Resume(ctx context.Context, api *yazio.API) (*yazio.User, error) {
+	sess, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	u := api.Resume(sess.Token())
+	if !u.Token().IsExpired() {
+		return u, nil
+	}
+
+	if err := api.Refresh(ctx, u); err != nil {
+		if errors.Is(err, yazio.ErrInvalidCredentials) {
+			return nil, fmt.Errorf("%w: %w", ErrExpired, err)
+		}
+		return nil, err
+	}
+
+	if err := s.Save(FromToken(sess.Username, u.Token())); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// Clear removes the stored session, if any.
+func (s *Store) Clear() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/yazio"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		path  = filepath.Join(t.TempDir(), "nested", fileName)
+		store = NewStore(path)
+		sess  = Session{
+			Username:  "joao@example.com",
+			Access:    "access",
+			Refresh:   "refresh",
+			ExpiresAt: time.Date(2025, 4, 12, 10, 0, 0, 0, time.UTC),
+		}
+	)
+
+	if _, err := store.Load(); !errors.Is(err, ErrNoSession) {
+		t.Fatalf("got %v, want %v", err, ErrNoSession)
+	}
+
+	assert.NoError(t, store.Save(sess))
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, info.Mode().Perm(), filePerm)
+
+	loaded, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, loaded, sess)
+
+	tk := loaded.Token()
+	assert.Equal(t, tk.Refresh(), "refresh")
+	assert.Equal(t, FromToken(sess.Username, tk), sess)
+
+	assert.NoError(t, store.Clear())
+	assert.NoError(t, store.Clear()) // idempotent
+
+	if _, err := store.Load(); !errors.Is(err, ErrNoSession) {
+		t.Fatalf("got %v, want %v", err, ErrNoSession)
+	}
+}
+
+func TestStore_LoadCorrupt(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name    string
+		content string
+	}{
+		{"not json", "{"},
+		{"blank refresh", `{"username":"joao","access_token":"a"}`},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), fileName)
+			assert.NoError(t, os.WriteFile(path, []byte(tb.content), filePerm))
+
+			if _, err := NewStore(path).Load(); !errors.Is(err, ErrCorruptSession) {
+				t.Fatalf("got %v, want %v", err, ErrCorruptSession)
+			}
+		})
+	}
+}
+
+func TestStore_Resume(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = context.Background()
+		fake  = yaziotest.New(t)
+		store = NewStore(filepath.Join(t.TempDir(), fileName))
+	)
+
+	api, err := yazio.New(yazio.WithBaseURL(fake.URL))
+	assert.NoError(t, err)
+
+	if _, err := store.Resume(ctx, api); !errors.Is(err, ErrNoSession) {
+		t.Fatalf("got %v, want %v", err, ErrNoSession)
+	}
+
+	u, err := api.Login(ctx, yazio.NewPasswordCred(yaziotest.DefaultUsername, yaziotest.DefaultPassword))
+	assert.NoError(t, err)
+
+	expired := FromToken(yaziotest.DefaultUsername, u.Token())
+	expired.ExpiresAt = time.Now().Add(-time.Hour)
+	assert.NoError(t, store.Save(expired))
+
+	resumed, err := store.Resume(ctx, api)
+	assert.NoError(t, err)
+
+	_, err = resumed.Data(ctx)
+	assert.NoError(t, err)
+
+	saved, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, saved.Username, yaziotest.DefaultUsername)
+	assert.Equal(t, saved.ExpiresAt.After(time.Now()), true)
+
+	saved.Refresh = "revoked"
+	saved.ExpiresAt = time.Now().Add(-time.Hour)
+	assert.NoError(t, store.Save(saved))
+
+	if _, err := store.Resume(ctx, api); !errors.Is(err, ErrExpired) {
+		t.Fatalf("got %v, want %v", err, ErrExpired)
+	}
+}
@@ -0,0 +1,39 @@
+// Package fakeuser logs tests into a [yaziotest.Server].
+package fakeuser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/yazio"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
+)
+
+// Login returns the default account of fake, logged in
+// through an API built with opts, failing t otherwise.
+//
+// Diary times are read in UTC, like the dates tests seed
+// fake with, unless opts set another location.
+func Login(t *testing.T, fake *yaziotest.Server, opts ...yazio.Option) *yazio.User {
+	t.Helper()
+
+	return LoginAs(t, fake, yaziotest.DefaultUsername, yaziotest.DefaultPassword, opts...)
+}
+
+// LoginAs is like [Login], for the account of username
+// (e.g. registered with yaziotest.WithAccount).
+func LoginAs(t *testing.T, fake *yaziotest.Server, username, password string, opts ...yazio.Option) *yazio.User {
+	t.Helper()
+
+	opts = append([]yazio.Option{yazio.WithBaseURL(fake.URL), yazio.WithLocation(time.UTC)}, opts...)
+
+	api, err := yazio.New(opts...)
+	assert.NoError(t, err)
+
+	u, err := api.Login(context.Background(), yazio.NewPasswordCred(username, password))
+	assert.NoError(t, err)
+
+	return u
+}
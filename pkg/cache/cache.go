@@ -0,0 +1,24 @@
+// Package cache provides storages for caching
+// serialized YAZIO API responses.
+package cache
+
+// Cache stores serialized values by key.
+//
+// Implementations must be safe for concurrent use. A failure to read
+// or persist a value is reported as a miss, since a cache is never
+// the source of truth.
+type Cache interface {
+	// Get returns the value stored under key, if
+	// present and not expired.
+	Get(key string) (value []byte, ok bool)
+
+	// Set stores value under key, replacing any previous value.
+	Set(key string, value []byte)
+
+	// Delete removes the value stored under key, if any.
+	Delete(key string)
+
+	// Keys returns the keys currently stored
+	// that start with prefix.
+	Keys(prefix string) []string
+}
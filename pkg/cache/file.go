@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	fileSuffix = ".cache"
+)
+
+// File is a [Cache] persisting each entry as a file inside a
+// directory, so cached responses survive between runs.
+//
+// Entries older than its TTL (by modification time) are
+// treated as missing and removed on access.
+//
+// Instances of File should be created using [NewFile].
+type File struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewFile creates a [*File] storing its entries in dir,
+// creating the directory if needed. A ttl of zero means
+// entries never expire.
+func NewFile(dir string, ttl time.Duration) (*File, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating cache dir %q: %w", dir, err)
+	}
+
+	f := &File{
+		dir: dir,
+		ttl: ttl,
+	}
+
+	return f, nil
+}
+
+// path maps key into a reversible, filesystem-safe name.
+func (f *File) path(key string) string {
+	name := base64.RawURLEncoding.EncodeToString([]byte(key))
+	return filepath.Join(f.dir, name+fileSuffix)
+}
+
+func (f *File) expired(info os.FileInfo) bool {
+	return f.ttl > 0 && time.Since(info.ModTime()) > f.ttl
+}
+
+func (f *File) Get(key string) ([]byte, bool) {
+	path := f.path(key)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+
+	if f.expired(info) {
+		_ = os.Remove(path)
+		return nil, false
+	}
+
+	value, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	return value, true
+}
+
+func (f *File) Set(key string, value []byte) {
+	tmp, err := os.CreateTemp(f.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name()) // no-op after rename
+
+	if _, err := tmp.Write(value); err != nil {
+		_ = tmp.Close()
+		return
+	}
+
+	if err := tmp.Close(); err != nil {
+		return
+	}
+
+	// atomic: readers never see a partial entry
+	_ = os.Rename(tmp.Name(), f.path(key))
+}
+
+func (f *File) Delete(key string) {
+	_ = os.Remove(f.path(key))
+}
+
+func (f *File) Keys(prefix string) []string {
+	dirEntries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil
+	}
+
+	var keys []string
+	for _, de := range dirEntries {
+		name, ok := strings.CutSuffix(de.Name(), fileSuffix)
+		if !ok || de.IsDir() {
+			continue
+		}
+
+		rawKey, err := base64.RawURLEncoding.DecodeString(name)
+		if err != nil {
+			continue
+		}
+
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+
+		if f.expired(info) {
+			_ = os.Remove(filepath.Join(f.dir, de.Name()))
+			continue
+		}
+
+		if key := string(rawKey); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
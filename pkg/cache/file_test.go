@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+)
+
+func TestFile(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFile(t.TempDir(), time.Hour)
+	assert.NoError(t, err)
+
+	const (
+		key = "9f86d08188/intake/nutrient.water/2025-04-12/2025-04-13"
+	)
+
+	_, ok := f.Get(key)
+	assert.Equal(t, ok, false)
+
+	f.Set(key, []byte(`{"2025-04-12":2000}`))
+
+	got, ok := f.Get(key)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, string(got), `{"2025-04-12":2000}`)
+	assert.EqualSlicesItems(t, f.Keys("9f86d08188/"), []string{key})
+
+	f.Delete(key)
+	_, ok = f.Get(key)
+	assert.Equal(t, ok, false)
+}
+
+func TestFile_TTL(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFile(t.TempDir(), time.Minute)
+	assert.NoError(t, err)
+
+	f.Set("stale", []byte("value"))
+
+	past := time.Now().Add(-time.Hour)
+	err = os.Chtimes(f.path("stale"), past, past)
+	assert.NoError(t, err)
+
+	_, ok := f.Get("stale")
+	assert.Equal(t, ok, false)
+	assert.Equal(t, len(f.Keys("")), 0)
+}
@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memory is an in-memory [Cache] that evicts the least recently used
+// entry once it's full, and expires entries older than its TTL.
+//
+// Instances of Memory should be created using [NewMemory].
+type Memory struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List               // front: most recently used
+	entries  map[string]*list.Element // values: *memoryEntry
+	now      func() time.Time
+}
+
+type memoryEntry struct {
+	key      string
+	value    []byte
+	storedAt time.Time
+}
+
+// NewMemory creates a [*Memory] holding up to capacity entries,
+// each one valid for ttl.
+//
+// A capacity below 1 means unbounded, and a ttl of zero
+// means entries never expire.
+func NewMemory(capacity int, ttl time.Duration) *Memory {
+	return &Memory{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+func (m *Memory) expired(e *memoryEntry) bool {
+	return m.ttl > 0 && m.now().Sub(e.storedAt) > m.ttl
+}
+
+func (m *Memory) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*memoryEntry)
+	if m.expired(entry) {
+		m.remove(elem)
+		return nil, false
+	}
+
+	m.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (m *Memory) Set(key string, value []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.entries[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.value = value
+		entry.storedAt = m.now()
+		m.order.MoveToFront(elem)
+		return
+	}
+
+	entry := &memoryEntry{key: key, value: value, storedAt: m.now()}
+	m.entries[key] = m.order.PushFront(entry)
+
+	if m.capacity > 0 && m.order.Len() > m.capacity {
+		m.remove(m.order.Back())
+	}
+}
+
+func (m *Memory) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.entries[key]; ok {
+		m.remove(elem)
+	}
+}
+
+func (m *Memory) Keys(prefix string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var keys []string
+	for key, elem := range m.entries {
+		if m.expired(elem.Value.(*memoryEntry)) {
+			m.remove(elem)
+			continue
+		}
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// Len returns how many entries m holds,
+// including the ones not evicted yet.
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// remove must be called holding m.mu.
+func (m *Memory) remove(elem *list.Element) {
+	entry := m.order.Remove(elem).(*memoryEntry)
+	delete(m.entries, entry.key)
+}
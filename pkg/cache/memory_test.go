@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+)
+
+func TestMemory_Eviction(t *testing.T) {
+	t.Parallel()
+
+	m := NewMemory(2, 0)
+	m.Set("a", []byte("1"))
+	m.Set("b", []byte("2"))
+
+	_, ok := m.Get("a") // "b" becomes the least recently used
+	assert.Equal(t, ok, true)
+
+	m.Set("c", []byte("3"))
+	assert.Equal(t, m.Len(), 2)
+
+	_, ok = m.Get("b")
+	assert.Equal(t, ok, false)
+
+	got, ok := m.Get("a")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, string(got), "1")
+}
+
+func TestMemory_TTL(t *testing.T) {
+	t.Parallel()
+
+	var (
+		timeNow = time.Now()
+		m       = NewMemory(0, time.Minute)
+	)
+	m.now = func() time.Time { return timeNow }
+
+	m.Set("user/macros/2025-04-12/2025-04-13", []byte("[]"))
+	m.Set("user/data", []byte("{}"))
+
+	_, ok := m.Get("user/data")
+	assert.Equal(t, ok, true)
+
+	timeNow = timeNow.Add(2 * time.Minute)
+
+	_, ok = m.Get("user/data")
+	assert.Equal(t, ok, false)
+	assert.Equal(t, len(m.Keys("user/")), 0)
+}
+
+func TestMemory_Keys(t *testing.T) {
+	t.Parallel()
+
+	m := NewMemory(0, 0)
+	m.Set("a/macros/1", nil)
+	m.Set("a/macros/2", nil)
+	m.Set("a/data", nil)
+	m.Set("b/macros/1", nil)
+
+	assert.EqualSlicesItems(t, m.Keys("a/macros/"), []string{"a/macros/1", "a/macros/2"})
+
+	m.Delete("a/macros/1")
+	assert.EqualSlicesItems(t, m.Keys("a/"), []string{"a/macros/2", "a/data"})
+}
@@ -0,0 +1,143 @@
+package date
+
+import (
+	"fmt"
+	"time"
+)
+
+type periodKind int
+
+const (
+	weekly periodKind = iota
+	monthly
+	everyDays
+)
+
+// Period groups instants into calendar buckets,
+// like ISO weeks or calendar months.
+//
+// Instances of Period should be created using
+// [Weekly], [Monthly] or [EveryDays].
+type Period struct {
+	kind      periodKind
+	days      int
+	anchor    time.Time
+	weekStart time.Weekday
+	loc       *time.Location
+}
+
+type PeriodOption func(p *Period)
+
+// WithWeekStart makes weekly buckets start on d
+// instead of Monday.
+func WithWeekStart(d time.Weekday) PeriodOption {
+	return func(p *Period) {
+		p.weekStart = d
+	}
+}
+
+// InLocation converts instants to loc before reading their
+// calendar day, and expresses bucket boundaries in loc.
+//
+// By default the location of each instant is kept, which is
+// what date-only values (like the days returned by YAZIO,
+// at UTC midnight) need.
+func InLocation(loc *time.Location) PeriodOption {
+	return func(p *Period) {
+		p.loc = loc
+	}
+}
+
+// Weekly creates a [Period] of weeks starting on Monday,
+// matching ISO 8601 weeks unless changed by [WithWeekStart].
+func Weekly(opts ...PeriodOption) Period {
+	return newPeriod(Period{kind: weekly, weekStart: time.Monday}, opts)
+}
+
+// Monthly creates a [Period] of calendar months.
+func Monthly(opts ...PeriodOption) Period {
+	return newPeriod(Period{kind: monthly}, opts)
+}
+
+// EveryDays creates a [Period] of days-long buckets, the first
+// one starting on the calendar day of anchor. Days below 1 are
+// treated as 1.
+func EveryDays(days int, anchor time.Time, opts ...PeriodOption) Period {
+	return newPeriod(Period{kind: everyDays, days: max(days, 1), anchor: anchor}, opts)
+}
+
+func newPeriod(p Period, opts []PeriodOption) Period {
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return p
+}
+
+// day returns the calendar day of t, according to p.
+func (p Period) day(t time.Time) time.Time {
+	if p.loc != nil {
+		t = t.In(p.loc)
+	}
+	return calendarDay(t)
+}
+
+// Bucket returns the bucket of t: a [Range] from the midnight of
+// its first day to the midnight of its last day.
+func (p Period) Bucket(t time.Time) Range {
+	var (
+		day   = p.day(t)
+		start time.Time
+		end   time.Time
+	)
+
+	switch p.kind {
+	case monthly:
+		start = day.AddDate(0, 0, 1-day.Day())
+		end = start.AddDate(0, 1, -1)
+	case everyDays:
+		var (
+			anchor  = p.day(p.anchor)
+			elapsed = Range{Start: anchor, End: day}
+			offset  = elapsed.Days() - 1
+		)
+		if day.Before(anchor) {
+			elapsed = Range{Start: day, End: anchor}
+			offset = -(elapsed.Days() - 1)
+		}
+
+		bucketIndex := offset / p.days
+		if offset < 0 && offset%p.days != 0 {
+			bucketIndex-- // floor division
+		}
+
+		start = anchor.AddDate(0, 0, bucketIndex*p.days)
+		end = start.AddDate(0, 0, p.days-1)
+	default: // weekly
+		back := (int(day.Weekday()) - int(p.weekStart) + 7) % 7
+		start = day.AddDate(0, 0, -back)
+		end = start.AddDate(0, 0, 6)
+	}
+
+	return Range{Start: start, End: end}
+}
+
+// Label returns a short name for the bucket of t:
+//   - ISO weeks (Monday start): "2025-W15"
+//   - Other weeks: "week of 2025-04-06"
+//   - Months: "2025-04"
+//   - Custom periods: "2025-04-01/2025-04-07"
+func (p Period) Label(t time.Time) string {
+	b := p.Bucket(t)
+
+	switch {
+	case p.kind == monthly:
+		return b.Start.Format("2006-01")
+	case p.kind == weekly && p.weekStart == time.Monday:
+		year, week := b.Start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case p.kind == weekly:
+		return "week of " + b.Start.Format(time.DateOnly)
+	default:
+		return b.Start.Format(time.DateOnly) + "/" + b.End.Format(time.DateOnly)
+	}
+}
@@ -0,0 +1,95 @@
+package date
+
+import (
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+)
+
+func TestPeriod_Bucket(t *testing.T) {
+	t.Parallel()
+
+	var (
+		saoPaulo = time.FixedZone("BRT", -3*60*60)
+		day      = func(m time.Month, d int) time.Time {
+			return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC)
+		}
+	)
+
+	testBlocks := []struct {
+		name      string
+		p         Period
+		t         time.Time
+		wantRange Range
+		wantLabel string
+	}{
+		{
+			name:      "iso week",
+			p:         Weekly(),
+			t:         day(4, 13), // sunday
+			wantRange: Range{day(4, 7), day(4, 13)},
+			wantLabel: "2025-W15",
+		},
+		{
+			name:      "week starting on sunday",
+			p:         Weekly(WithWeekStart(time.Sunday)),
+			t:         day(4, 13),
+			wantRange: Range{day(4, 13), day(4, 19)},
+			wantLabel: "week of 2025-04-13",
+		},
+		{
+			name:      "iso week crossing years",
+			p:         Weekly(),
+			t:         day(1, 1),
+			wantRange: Range{time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), day(1, 5)},
+			wantLabel: "2025-W01",
+		},
+		{
+			name:      "month",
+			p:         Monthly(),
+			t:         day(2, 17),
+			wantRange: Range{day(2, 1), day(2, 28)},
+			wantLabel: "2025-02",
+		},
+		{
+			name: "month in another location",
+			p:    Monthly(InLocation(saoPaulo)),
+			t:    time.Date(2025, 3, 1, 1, 0, 0, 0, time.UTC), // still february in BRT
+			wantRange: Range{
+				time.Date(2025, 2, 1, 0, 0, 0, 0, saoPaulo),
+				time.Date(2025, 2, 28, 0, 0, 0, 0, saoPaulo),
+			},
+			wantLabel: "2025-02",
+		},
+		{
+			name:      "every 10 days",
+			p:         EveryDays(10, day(4, 1)),
+			t:         day(4, 25),
+			wantRange: Range{day(4, 21), day(4, 30)},
+			wantLabel: "2025-04-21/2025-04-30",
+		},
+		{
+			name:      "every 10 days before the anchor",
+			p:         EveryDays(10, day(4, 1)),
+			t:         day(3, 22),
+			wantRange: Range{day(3, 22), day(3, 31)},
+			wantLabel: "2025-03-22/2025-03-31",
+		},
+		{
+			name:      "every 10 days right before the anchor",
+			p:         EveryDays(10, day(4, 1)),
+			t:         day(3, 21),
+			wantRange: Range{day(3, 12), day(3, 21)},
+			wantLabel: "2025-03-12/2025-03-21",
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tb.p.Bucket(tb.t), tb.wantRange)
+			assert.Equal(t, tb.p.Label(tb.t), tb.wantLabel)
+		})
+	}
+}
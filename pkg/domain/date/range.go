@@ -2,6 +2,7 @@ package date
 
 import (
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -23,3 +24,74 @@ func (r *Range) String() string {
 		int(duration),
 	)
 }
+
+// Days returns how many calendar days r spans,
+// counting both Start and End.
+//
+// It's zero when End is before Start.
+func (r *Range) Days() int {
+	var (
+		start = calendarDay(r.Start)
+		end   = calendarDay(r.End)
+	)
+
+	if end.Before(start) {
+		return 0
+	}
+
+	// rounded: DST transitions make days 23h or 25h long
+	return int(math.Round(end.Sub(start).Hours()/24)) + 1
+}
+
+// Dates returns the midnight (in the location of r.Start)
+// of every calendar day within r, in chronological order.
+func (r *Range) Dates() []time.Time {
+	var (
+		days  = r.Days()
+		start = calendarDay(r.Start)
+		out   = make([]time.Time, days)
+	)
+
+	for i := range days {
+		out[i] = start.AddDate(0, 0, i)
+	}
+
+	return out
+}
+
+// Split breaks r into consecutive, non-overlapping windows of up
+// to days calendar days each, in chronological order.
+//
+// The first window keeps r.Start and the last one keeps r.End.
+// If days is below 1 (or r fits in a single window), the
+// result holds r itself.
+func (r *Range) Split(days int) []Range {
+	if days < 1 || r.Days() <= days {
+		return []Range{*r}
+	}
+
+	var (
+		windows []Range
+		lastDay = calendarDay(r.End)
+	)
+
+	for start := r.Start; !calendarDay(start).After(lastDay); {
+		end := calendarDay(start).AddDate(0, 0, days-1)
+		if !end.Before(lastDay) {
+			windows = append(windows, Range{Start: start, End: r.End})
+			break
+		}
+
+		windows = append(windows, Range{Start: start, End: end})
+		start = end.AddDate(0, 0, 1)
+	}
+
+	return windows
+}
+
+// calendarDay returns the midnight of t (in its location),
+// which works regardless of the wall clock of t.
+func calendarDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -33,3 +33,117 @@ func TestRange_String(t *testing.T) {
 		})
 	}
 }
+
+func TestRange_Days(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name string
+		dr   *Range
+		want int
+	}{
+		{
+			name: "same day",
+			dr: &Range{
+				Start: time.Date(2025, 4, 12, 8, 0, 0, 0, time.UTC),
+				End:   time.Date(2025, 4, 12, 22, 0, 0, 0, time.UTC),
+			},
+			want: 1,
+		},
+		{
+			name: "crossing midnight",
+			dr: &Range{
+				Start: time.Date(2025, 4, 12, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2025, 4, 13, 1, 0, 0, 0, time.UTC),
+			},
+			want: 2,
+		},
+		{
+			name: "inverted",
+			dr: &Range{
+				Start: time.Date(2025, 4, 13, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2025, 4, 12, 0, 0, 0, 0, time.UTC),
+			},
+			want: 0,
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+			got := tb.dr.Days()
+			assert.Equal(t, got, tb.want)
+		})
+	}
+}
+
+func TestRange_Split(t *testing.T) {
+	t.Parallel()
+
+	var (
+		start = time.Date(2025, 1, 30, 10, 0, 0, 0, time.UTC)
+		end   = time.Date(2025, 2, 7, 18, 0, 0, 0, time.UTC)
+		day   = func(m time.Month, d int) time.Time {
+			return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC)
+		}
+	)
+
+	testBlocks := []struct {
+		name string
+		days int
+		want []Range
+	}{
+		{
+			name: "disabled",
+			days: 0,
+			want: []Range{{start, end}},
+		},
+		{
+			name: "fits in a window",
+			days: 9,
+			want: []Range{{start, end}},
+		},
+		{
+			name: "windows of 4 days",
+			days: 4,
+			want: []Range{
+				{start, day(2, 2)},
+				{day(2, 3), day(2, 6)},
+				{day(2, 7), end},
+			},
+		},
+		{
+			name: "exact windows",
+			days: 3,
+			want: []Range{
+				{start, day(2, 1)},
+				{day(2, 2), day(2, 4)},
+				{day(2, 5), end},
+			},
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+			r := Range{Start: start, End: end}
+			got := r.Split(tb.days)
+			assert.DeepEqual(t, got, tb.want)
+		})
+	}
+}
+
+func TestRange_Dates(t *testing.T) {
+	t.Parallel()
+
+	r := Range{
+		Start: time.Date(2024, 2, 28, 18, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC),
+	}
+
+	assert.DeepEqual(t, r.Dates(), []time.Time{
+		time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+}
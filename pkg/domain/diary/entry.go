@@ -0,0 +1,35 @@
+package diary
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/meal"
+	"github.com/google/uuid"
+)
+
+// Entry is a food logged in the diary of a YAZIO user.
+type Entry struct {
+	ID       uuid.UUID    // ID is the unique identifier of the entry.
+	Date     time.Time    // Date is when the food was consumed.
+	Meal     meal.Time    // Meal is the meal the food was logged to.
+	FoodID   food.ID      // FoodID identifies the logged food (product).
+	Serving  food.Serving // Serving is the serving logged.
+	Quantity float64      // Quantity is how many servings were consumed.
+}
+
+// BaseAmount returns how many base units of
+// the food (e.g. grams) e represents.
+func (e Entry) BaseAmount() float64 {
+	return e.Serving.BaseAmount(e.Quantity)
+}
+
+func (e Entry) String() string {
+	return fmt.Sprintf("Entry(%s, %s, %gx %s)",
+		e.Date.Format(time.DateTime),
+		e.Meal,
+		e.Quantity,
+		e.Serving.Kind,
+	)
+}
@@ -0,0 +1,24 @@
+package diary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/meal"
+)
+
+func TestEntry(t *testing.T) {
+	t.Parallel()
+
+	e := Entry{
+		Date:     time.Date(2025, 4, 12, 8, 30, 0, 0, time.UTC),
+		Meal:     meal.Breakfast,
+		Serving:  food.Serving{Kind: food.Slice, Amount: 30},
+		Quantity: 2,
+	}
+
+	assert.Equal(t, e.BaseAmount(), 60.0)
+	assert.Equal(t, e.String(), "Entry(2025-04-12 08:30:00, breakfast, 2x slice)")
+}
@@ -0,0 +1,7 @@
+package diary
+
+import "errors"
+
+var (
+	ErrAlreadyExists = errors.New("given diary entry already exists")
+)
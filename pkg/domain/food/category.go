@@ -1,16 +1,154 @@
 package food
 
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
 const (
-	Meat              Category = "meat"
-	Miscellaneous     Category = "miscellaneous"
-	Chocolate         Category = "chocolate"
+	AlcoholicDrink    Category = "drinksalcoholic"
 	NonAlcoholicDrink Category = "drinksnonalcoholic"
+	BabyFood          Category = "babyfood"
+	Bread             Category = "bread"
+	Cake              Category = "cakes"
+	Cereal            Category = "cereals"
 	Cheese            Category = "cheese"
+	Chocolate         Category = "chocolate"
+	Dairy             Category = "dairy"
+	Dessert           Category = "desserts"
+	Dish              Category = "dishes"
+	Egg               Category = "eggs"
+	FastFood          Category = "fastfood"
+	Fat               Category = "fats"
+	Fish              Category = "fish"
+	Fruit             Category = "fruit"
+	Grain             Category = "grains"
+	IceCream          Category = "icecream"
+	Legume            Category = "legumes"
+	Meat              Category = "meat"
+	MeatSubstitute    Category = "meatsubstitutes"
+	Miscellaneous     Category = "miscellaneous"
+	Nut               Category = "nuts"
+	Pasta             Category = "pasta"
+	Potato            Category = "potatoes"
+	Poultry           Category = "poultry"
+	Rice              Category = "rice"
+	Sauce             Category = "sauces"
+	Sausage           Category = "sausage"
+	Snack             Category = "snacks"
+	Soup              Category = "soups"
+	Spice             Category = "spices"
+	Spread            Category = "spreads"
+	Supplement        Category = "supplements"
+	Sweet             Category = "sweets"
+	Vegetable         Category = "vegetables"
+	Yogurt            Category = "yogurt"
 )
 
 // Category represents the classification of a food item.
+//
+// Values outside of the catalogue (see [Categories]) are rejected
+// by [ParseCategory] and [Food.Validate]. YAZIO may still return
+// them, so [LookupCategory] and the text encoding keep them.
 type Category string
 
+var categoryNames = map[Category]names{
+	AlcoholicDrink:    {"Alcoholic drinks", "Alkoholische Getränke", "Bebidas alcoólicas"},
+	NonAlcoholicDrink: {"Non-alcoholic drinks", "Alkoholfreie Getränke", "Bebidas não alcoólicas"},
+	BabyFood:          {"Baby food", "Babynahrung", "Comida de bebê"},
+	Bread:             {"Bread", "Brot", "Pães"},
+	Cake:              {"Cakes & pastries", "Kuchen & Gebäck", "Bolos e confeitaria"},
+	Cereal:            {"Cereals", "Müsli & Cerealien", "Cereais"},
+	Cheese:            {"Cheese", "Käse", "Queijos"},
+	Chocolate:         {"Chocolate", "Schokolade", "Chocolate"},
+	Dairy:             {"Dairy products", "Milchprodukte", "Laticínios"},
+	Dessert:           {"Desserts", "Desserts", "Sobremesas"},
+	Dish:              {"Dishes", "Gerichte", "Pratos"},
+	Egg:               {"Eggs", "Eier", "Ovos"},
+	FastFood:          {"Fast food", "Fast Food", "Fast food"},
+	Fat:               {"Fats & oils", "Fette & Öle", "Gorduras e óleos"},
+	Fish:              {"Fish & seafood", "Fisch & Meeresfrüchte", "Peixes e frutos do mar"},
+	Fruit:             {"Fruit", "Obst", "Frutas"},
+	Grain:             {"Grains", "Getreide", "Grãos"},
+	IceCream:          {"Ice cream", "Eis", "Sorvetes"},
+	Legume:            {"Legumes", "Hülsenfrüchte", "Leguminosas"},
+	Meat:              {"Meat", "Fleisch", "Carnes"},
+	MeatSubstitute:    {"Meat substitutes", "Fleischersatz", "Substitutos de carne"},
+	Miscellaneous:     {"Miscellaneous", "Sonstiges", "Diversos"},
+	Nut:               {"Nuts & seeds", "Nüsse & Samen", "Castanhas e sementes"},
+	Pasta:             {"Pasta", "Nudeln", "Massas"},
+	Potato:            {"Potatoes", "Kartoffeln", "Batatas"},
+	Poultry:           {"Poultry", "Geflügel", "Aves"},
+	Rice:              {"Rice", "Reis", "Arroz"},
+	Sauce:             {"Sauces & dressings", "Soßen & Dressings", "Molhos"},
+	Sausage:           {"Sausages & cold cuts", "Wurst & Aufschnitt", "Embutidos e frios"},
+	Snack:             {"Snacks", "Snacks", "Petiscos"},
+	Soup:              {"Soups", "Suppen", "Sopas"},
+	Spice:             {"Spices & herbs", "Gewürze & Kräuter", "Temperos e ervas"},
+	Spread:            {"Spreads", "Aufstriche", "Pastas e cremes"},
+	Supplement:        {"Supplements", "Nahrungsergänzung", "Suplementos"},
+	Sweet:             {"Sweets", "Süßigkeiten", "Doces"},
+	Vegetable:         {"Vegetables", "Gemüse", "Legumes e verduras"},
+	Yogurt:            {"Yogurt", "Joghurt", "Iogurtes"},
+}
+
+// Categories returns every known [Category], sorted by ID.
+func Categories() []Category {
+	return slices.Sorted(maps.Keys(categoryNames))
+}
+
+// ParseCategory resolves the [Category] identified by s,
+// ignoring case and surrounding spaces.
+//
+// On failure the error wraps either:
+//   - [ErrUnknownCategory]
+func ParseCategory(s string) (Category, error) {
+	c, ok := LookupCategory(s)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownCategory, s)
+	}
+	return c, nil
+}
+
+// LookupCategory is like [ParseCategory], but keeps categories
+// outside of the catalogue as given, reporting whether c is known.
+func LookupCategory(s string) (c Category, ok bool) {
+	c = Category(strings.ToLower(strings.TrimSpace(s)))
+	if !c.known() {
+		return Category(s), false
+	}
+	return c, true
+}
+
 func (c Category) String() string {
 	return string(c)
 }
+
+// Name returns the display name of c in lang (English when lang
+// isn't supported). Unknown categories are named by their ID.
+func (c Category) Name(lang Language) string {
+	n, ok := categoryNames[c]
+	if !ok {
+		return c.String()
+	}
+	return n.in(lang)
+}
+
+// MarshalText encodes c as its ID, as is.
+func (c Category) MarshalText() ([]byte, error) {
+	return []byte(c), nil
+}
+
+// UnmarshalText decodes a category with [LookupCategory], so
+// whatever [Category.MarshalText] encodes decodes back.
+func (c *Category) UnmarshalText(text []byte) error {
+	*c, _ = LookupCategory(string(text))
+	return nil
+}
+
+func (c Category) known() bool {
+	_, ok := categoryNames[c]
+	return ok
+}
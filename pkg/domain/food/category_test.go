@@ -1,6 +1,9 @@
 package food
 
 import (
+	"encoding/json"
+	"errors"
+	"slices"
 	"testing"
 
 	"github.com/controlado/go-yazio/internal/testutil/assert"
@@ -29,3 +32,120 @@ func TestCategory_String(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCategory(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name    string
+		input   string
+		want    Category
+		wantErr bool
+	}{
+		{name: "exact", input: "drinksnonalcoholic", want: NonAlcoholicDrink},
+		{name: "case and spaces", input: "  Vegetables ", want: Vegetable},
+		{name: "unknown", input: "gadgets", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseCategory(tb.input)
+			if tb.wantErr {
+				if !errors.Is(err, ErrUnknownCategory) {
+					t.Fatalf("got %v, want %v", err, ErrUnknownCategory)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, got, tb.want)
+		})
+	}
+}
+
+func TestCategory_Name(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name string
+		c    Category
+		lang Language
+		want string
+	}{
+		{"english", Cheese, English, "Cheese"},
+		{"german", Cheese, German, "Käse"},
+		{"regional portuguese", Cheese, "pt-BR", "Queijos"},
+		{"unsupported language", Cheese, "fr", "Cheese"},
+		{"unknown category", "gadgets", German, "gadgets"},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tb.c.Name(tb.lang), tb.want)
+		})
+	}
+}
+
+func TestCategory_Text(t *testing.T) {
+	t.Parallel()
+
+	var decoded struct {
+		Category Category `json:"category"`
+	}
+
+	err := json.Unmarshal([]byte(`{"category":"Meat"}`), &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded.Category, Meat)
+
+	encoded, err := json.Marshal(map[string]Category{"category": Fish})
+	assert.NoError(t, err)
+	assert.Equal(t, string(encoded), `{"category":"fish"}`)
+
+	encoded, err = json.Marshal(map[string]Category{"category": "gadgets"}) // as returned by YAZIO
+	assert.NoError(t, err)
+	assert.Equal(t, string(encoded), `{"category":"gadgets"}`)
+
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, decoded.Category, Category("gadgets"))
+}
+
+func TestLookupCategory(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		input  string
+		want   Category
+		wantOK bool
+	}{
+		{input: " Meat ", want: Meat, wantOK: true},
+		{input: "Gadgets", want: "Gadgets"},
+		{input: "", want: ""},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := LookupCategory(tb.input)
+			assert.Equal(t, got, tb.want)
+			assert.Equal(t, ok, tb.wantOK)
+		})
+	}
+}
+
+func TestCategories(t *testing.T) {
+	t.Parallel()
+
+	all := Categories()
+	assert.Equal(t, slices.IsSorted(all), true)
+
+	for _, c := range all {
+		if c.Name(English) == "" || c.Name(German) == "" || c.Name(Portuguese) == "" {
+			t.Errorf("category %q is missing a display name", c)
+		}
+	}
+}
@@ -5,5 +5,40 @@ import "errors"
 var (
 	ErrInvalidName      = errors.New("given food name is invalid")
 	ErrAlreadyExists    = errors.New("given food already exists")
+	ErrNotFound         = errors.New("given food doesn't exist")
 	ErrMissingNutrients = errors.New("given food is missing some required nutrients")
+	ErrUnknownServing   = errors.New("given serving is not defined by the food")
+	ErrInvalidQuantity  = errors.New("given serving quantity is invalid")
+
+	// Validation
+	ErrNegativeNutrient     = errors.New("given food has a negative nutrient value")
+	ErrNutrientExceedsTotal = errors.New("given food has a sub-nutrient exceeding its parent")
+	ErrImplausibleEnergy    = errors.New("given food energy doesn't match its macros")
+	ErrMacrosExceedWeight   = errors.New("given food macros exceed its base amount")
+	ErrDuplicateServing     = errors.New("given food has duplicate servings")
+	ErrInvalidServing       = errors.New("given food has a serving with invalid amount")
+	ErrUnknownCategory      = errors.New("given food category is unknown")
+	ErrUnknownServingKind   = errors.New("given serving kind is unknown")
 )
+
+// IsValidationError reports whether err wraps one of the errors
+// [New] and [Food.Validate] reject an invalid food with.
+func IsValidationError(err error) bool {
+	for _, target := range []error{
+		ErrInvalidName,
+		ErrMissingNutrients,
+		ErrNegativeNutrient,
+		ErrNutrientExceedsTotal,
+		ErrImplausibleEnergy,
+		ErrMacrosExceedWeight,
+		ErrDuplicateServing,
+		ErrInvalidServing,
+		ErrUnknownCategory,
+		ErrUnknownServingKind,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
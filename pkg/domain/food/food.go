@@ -82,6 +82,75 @@ func New(name string, cat Category, nut Nutrients, opts ...Option) (f Food, err
 	return f, nil
 }
 
+// Serving returns the [Serving] of kind k defined by f, if any.
+func (f Food) Serving(k ServingKind) (Serving, bool) {
+	for _, s := range f.Servings {
+		if s.Kind == k {
+			return s, true
+		}
+	}
+	return Serving{}, false
+}
+
+// Weight returns the weight of quantity servings of kind k,
+// in the [BaseUnit] of f (e.g. how many grams in 2 slices).
+//
+// On failure the error wraps either:
+//   - [ErrUnknownServing]
+//   - [ErrInvalidQuantity]
+func (f Food) Weight(k ServingKind, quantity float64) (float64, error) {
+	s, ok := f.Serving(k)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q in %q", ErrUnknownServing, k, f.Name)
+	}
+
+	if quantity <= 0 {
+		return 0, fmt.Errorf("%w: %g", ErrInvalidQuantity, quantity)
+	}
+
+	return s.BaseAmount(quantity), nil
+}
+
+// NutrientsPer returns the nutrients of amount base units of f
+// (e.g. 250 for 250g), scaling the per 100 values of [Nutrients].
+func (f Food) NutrientsPer(amount float64) Nutrients {
+	out := make(Nutrients, len(f.Nutrients))
+	for k, v := range f.Nutrients {
+		out[k] = v * amount / 100
+	}
+	return out
+}
+
+// NutrientsFor returns the nutrients of quantity servings of f.
+//
+// With quantity one, it previews what logging serving through
+// yazio.User.EntryFood adds to the diary.
+//
+// On failure the error wraps either:
+//   - [ErrInvalidQuantity] if quantity or the serving amount isn't positive
+func (f Food) NutrientsFor(serving Serving, quantity float64) (Nutrients, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("%w: %g", ErrInvalidQuantity, quantity)
+	}
+
+	if serving.Amount <= 0 {
+		return nil, fmt.Errorf("%w: %q serving amount %g", ErrInvalidQuantity, serving.Kind, serving.Amount)
+	}
+
+	return f.NutrientsPer(serving.BaseAmount(quantity)), nil
+}
+
+// ValueIn returns the amount of k in n expressed in the unit to
+// (e.g. [intake.Sodium] in [unit.Milligram]). A kind missing
+// from n is valued zero.
+//
+// On failure the error wraps either:
+//   - [unit.ErrUnknownUnit]
+//   - [unit.ErrIncompatibleUnits]
+func (n Nutrients) ValueIn(k intake.Kind, to unit.Base) (float64, error) {
+	return unit.Convert(n[k], k.BaseUnit(), to)
+}
+
 func (f Food) String() string {
 	return fmt.Sprintf("Food(%q, %s, %s)",
 		f.Name,
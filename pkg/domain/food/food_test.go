@@ -1,6 +1,7 @@
 package food
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -130,3 +131,117 @@ func TestFood_String(t *testing.T) {
 		})
 	}
 }
+
+func TestNutrients_ValueIn(t *testing.T) {
+	t.Parallel()
+
+	n := Nutrients{
+		intake.Energy: 250,
+		intake.Sodium: 400_000,
+	}
+
+	energy, err := n.ValueIn(intake.Energy, unit.Kilojoule)
+	assert.NoError(t, err)
+	assert.Equal(t, energy, 1046.0)
+
+	sodium, err := n.ValueIn(intake.Sodium, unit.Gram)
+	assert.NoError(t, err)
+	assert.Equal(t, sodium, 0.4)
+
+	missing, err := n.ValueIn(intake.Protein, unit.Ounce)
+	assert.NoError(t, err)
+	assert.Equal(t, missing, 0.0)
+
+	if _, err := n.ValueIn(intake.Energy, unit.Gram); !errors.Is(err, unit.ErrIncompatibleUnits) {
+		t.Fatalf("got %v, want %v", err, unit.ErrIncompatibleUnits)
+	}
+}
+
+func TestFood_NutrientsFor(t *testing.T) {
+	t.Parallel()
+
+	var (
+		slice = Serving{Kind: Slice, Amount: 30}
+		bread = Food{
+			Name:     "Bread",
+			BaseUnit: unit.Gram,
+			Nutrients: Nutrients{
+				intake.Energy:  250,
+				intake.Protein: 9,
+			},
+			Servings: []Serving{slice},
+		}
+	)
+
+	testBlocks := []struct {
+		name     string
+		serving  Serving
+		quantity float64
+		want     Nutrients
+		wantErr  error
+	}{
+		{
+			name:     "two slices",
+			serving:  slice,
+			quantity: 2,
+			want:     Nutrients{intake.Energy: 150, intake.Protein: 5.4},
+		},
+		{
+			name:     "half portion",
+			serving:  defaultServing,
+			quantity: 0.5,
+			want:     Nutrients{intake.Energy: 125, intake.Protein: 4.5},
+		},
+		{
+			name:     "zero quantity",
+			serving:  slice,
+			quantity: 0,
+			wantErr:  ErrInvalidQuantity,
+		},
+		{
+			name:     "zero amount",
+			serving:  Serving{Kind: Slice},
+			quantity: 1,
+			wantErr:  ErrInvalidQuantity,
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := bread.NutrientsFor(tb.serving, tb.quantity)
+			if tb.wantErr != nil {
+				if !errors.Is(err, tb.wantErr) {
+					t.Fatalf("got %v, want %v", err, tb.wantErr)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.DeepEqual(t, got, tb.want)
+		})
+	}
+}
+
+func TestFood_Weight(t *testing.T) {
+	t.Parallel()
+
+	juice := Food{
+		Name:     "Juice",
+		BaseUnit: unit.Milliliter,
+		Servings: []Serving{{Kind: Glass, Amount: 250}},
+	}
+
+	got, err := juice.Weight(Glass, 1.5)
+	assert.NoError(t, err)
+	assert.Equal(t, got, 375.0)
+
+	if _, err := juice.Weight(Bottle, 1); !errors.Is(err, ErrUnknownServing) {
+		t.Fatalf("got %v, want %v", err, ErrUnknownServing)
+	}
+
+	if _, err := juice.Weight(Glass, -1); !errors.Is(err, ErrInvalidQuantity) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidQuantity)
+	}
+}
@@ -0,0 +1,37 @@
+package food
+
+import "strings"
+
+const (
+	English    Language = "en"
+	German     Language = "de"
+	Portuguese Language = "pt"
+)
+
+// Language selects the display names of categories and serving
+// kinds. Regional tags (e.g. "pt-BR") use their base language.
+type Language string
+
+// base returns the lowercase primary subtag of l.
+func (l Language) base() Language {
+	tag, _, _ := strings.Cut(strings.ToLower(string(l)), "-")
+	tag, _, _ = strings.Cut(tag, "_")
+	return Language(tag)
+}
+
+// names holds a display name per supported [Language].
+type names struct {
+	en, de, pt string
+}
+
+// in returns the name in lang, falling back to English.
+func (n names) in(lang Language) string {
+	switch lang.base() {
+	case German:
+		return n.de
+	case Portuguese:
+		return n.pt
+	default:
+		return n.en
+	}
+}
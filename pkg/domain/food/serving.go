@@ -1,30 +1,187 @@
 package food
 
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
 const (
 	Bar        ServingKind = "bar"
-	Teaspoon   ServingKind = "teaspoon"
-	Tablespoon ServingKind = "tablespoon"
-	Glass      ServingKind = "glass"
-	Slice      ServingKind = "slice"
 	Bottle     ServingKind = "bottle"
+	Bowl       ServingKind = "bowl"
 	Can        ServingKind = "can"
-	Piece      ServingKind = "piece"
-	Tablet     ServingKind = "tablet"
-	Portion    ServingKind = "portion"
+	Capsule    ServingKind = "capsule"
+	Clove      ServingKind = "clove"
+	Cube       ServingKind = "cube"
 	Cup        ServingKind = "cup"
-	Pack       ServingKind = "package"
+	Drop       ServingKind = "drop"
 	Each       ServingKind = "each"
+	Fillet     ServingKind = "fillet"
+	Glass      ServingKind = "glass"
+	Handful    ServingKind = "handful"
+	Jar        ServingKind = "jar"
+	Leaf       ServingKind = "leaf"
+	Mug        ServingKind = "mug"
+	Pack       ServingKind = "package"
+	Piece      ServingKind = "piece"
+	Pinch      ServingKind = "pinch"
+	Plate      ServingKind = "plate"
+	Portion    ServingKind = "portion"
+	Scoop      ServingKind = "scoop"
+	Shot       ServingKind = "shot"
+	Slice      ServingKind = "slice"
+	Stick      ServingKind = "stick"
+	Tablespoon ServingKind = "tablespoon"
+	Tablet     ServingKind = "tablet"
+	Teaspoon   ServingKind = "teaspoon"
 )
 
 // Serving describes a specific quantity
 // or measure of a food item.
 type Serving struct {
 	Kind   ServingKind
-	Amount float64
+	Amount float64 // Amount is the weight of one serving, in the food [unit.Base].
 }
 
+// BaseAmount returns the weight of quantity servings,
+// in the food [unit.Base] (e.g. grams).
+func (s Serving) BaseAmount(quantity float64) float64 {
+	return s.Amount * quantity
+}
+
+// ServingKind names a measure of food (e.g. [Slice]).
+//
+// Values outside of the catalogue (see [ServingKinds]) are rejected
+// by [ParseServingKind] and [Food.Validate]. YAZIO may still return
+// them (or none, for entries logged by base amount), so
+// [LookupServingKind] and the text encoding keep them.
 type ServingKind string
 
+var servingNames = map[ServingKind]names{
+	Bar:        {"Bar", "Riegel", "Barra"},
+	Bottle:     {"Bottle", "Flasche", "Garrafa"},
+	Bowl:       {"Bowl", "Schüssel", "Tigela"},
+	Can:        {"Can", "Dose", "Lata"},
+	Capsule:    {"Capsule", "Kapsel", "Cápsula"},
+	Clove:      {"Clove", "Zehe", "Dente"},
+	Cube:       {"Cube", "Würfel", "Cubo"},
+	Cup:        {"Cup", "Tasse", "Xícara"},
+	Drop:       {"Drop", "Tropfen", "Gota"},
+	Each:       {"Each", "Stück", "Unidade"},
+	Fillet:     {"Fillet", "Filet", "Filé"},
+	Glass:      {"Glass", "Glas", "Copo"},
+	Handful:    {"Handful", "Handvoll", "Punhado"},
+	Jar:        {"Jar", "Glas (Behälter)", "Pote"},
+	Leaf:       {"Leaf", "Blatt", "Folha"},
+	Mug:        {"Mug", "Becher", "Caneca"},
+	Pack:       {"Package", "Packung", "Pacote"},
+	Piece:      {"Piece", "Stück", "Pedaço"},
+	Pinch:      {"Pinch", "Prise", "Pitada"},
+	Plate:      {"Plate", "Teller", "Prato"},
+	Portion:    {"Portion", "Portion", "Porção"},
+	Scoop:      {"Scoop", "Messlöffel", "Medida"},
+	Shot:       {"Shot", "Schnapsglas", "Dose (shot)"},
+	Slice:      {"Slice", "Scheibe", "Fatia"},
+	Stick:      {"Stick", "Stange", "Palito"},
+	Tablespoon: {"Tablespoon", "Esslöffel", "Colher de sopa"},
+	Tablet:     {"Tablet", "Tablette", "Comprimido"},
+	Teaspoon:   {"Teaspoon", "Teelöffel", "Colher de chá"},
+}
+
+// ServingKinds returns every known [ServingKind], sorted by ID.
+func ServingKinds() []ServingKind {
+	return slices.Sorted(maps.Keys(servingNames))
+}
+
+// ParseServingKind resolves the [ServingKind] identified
+// by s, ignoring case and surrounding spaces.
+//
+// On failure the error wraps either:
+//   - [ErrUnknownServingKind]
+func ParseServingKind(s string) (ServingKind, error) {
+	sk, ok := LookupServingKind(s)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownServingKind, s)
+	}
+	return sk, nil
+}
+
+// LookupServingKind is like [ParseServingKind], but keeps kinds
+// outside of the catalogue as given, reporting whether sk is known.
+func LookupServingKind(s string) (sk ServingKind, ok bool) {
+	sk = ServingKind(strings.ToLower(strings.TrimSpace(s)))
+	if !sk.known() {
+		return ServingKind(s), false
+	}
+	return sk, true
+}
+
+// servingWords map words of serving descriptions the
+// catalogue doesn't name onto [ServingKind].
+var servingWords = map[string]ServingKind{
+	"pack":    Pack,
+	"packet":  Pack,
+	"sachet":  Pack,
+	"pc":      Piece,
+	"pcs":     Piece,
+	"biscuit": Piece,
+	"cookie":  Piece,
+	"tbsp":    Tablespoon,
+	"tsp":     Teaspoon,
+	"leaves":  Leaf,
+}
+
+// GuessServingKind returns the kind named by the first word of text
+// that names one (e.g. "2 slices (30 g)" is a [Slice]), accepting
+// plurals and common abbreviations. It defaults to [Portion].
+func GuessServingKind(text string) ServingKind {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return r < 'a' || r > 'z'
+	})
+
+	for _, word := range words {
+		for _, w := range []string{word, strings.TrimSuffix(word, "s"), strings.TrimSuffix(word, "es")} {
+			if sk := ServingKind(w); sk.known() {
+				return sk
+			}
+			if sk, ok := servingWords[w]; ok {
+				return sk
+			}
+		}
+	}
+
+	return Portion
+}
+
 func (sk ServingKind) String() string {
 	return string(sk)
 }
+
+// Name returns the display name of sk in lang (English when lang
+// isn't supported). Unknown kinds are named by their ID.
+func (sk ServingKind) Name(lang Language) string {
+	n, ok := servingNames[sk]
+	if !ok {
+		return sk.String()
+	}
+	return n.in(lang)
+}
+
+// MarshalText encodes sk as its ID, as is.
+func (sk ServingKind) MarshalText() ([]byte, error) {
+	return []byte(sk), nil
+}
+
+// UnmarshalText decodes a serving kind with [LookupServingKind],
+// so whatever [ServingKind.MarshalText] encodes decodes back.
+func (sk *ServingKind) UnmarshalText(text []byte) error {
+	*sk, _ = LookupServingKind(string(text))
+	return nil
+}
+
+func (sk ServingKind) known() bool {
+	_, ok := servingNames[sk]
+	return ok
+}
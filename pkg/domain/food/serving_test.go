@@ -0,0 +1,134 @@
+package food
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+)
+
+func TestParseServingKind(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name    string
+		input   string
+		want    ServingKind
+		wantErr bool
+	}{
+		{name: "exact", input: "package", want: Pack},
+		{name: "case and spaces", input: " Tablespoon", want: Tablespoon},
+		{name: "unknown", input: "spoonful", wantErr: true},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseServingKind(tb.input)
+			if tb.wantErr {
+				if !errors.Is(err, ErrUnknownServingKind) {
+					t.Fatalf("got %v, want %v", err, ErrUnknownServingKind)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, got, tb.want)
+		})
+	}
+}
+
+func TestServingKind_Name(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, Slice.Name(English), "Slice")
+	assert.Equal(t, Slice.Name("de_DE"), "Scheibe")
+	assert.Equal(t, Teaspoon.Name(Portuguese), "Colher de chá")
+	assert.Equal(t, ServingKind("spoonful").Name(German), "spoonful")
+}
+
+func TestServingKind_Text(t *testing.T) {
+	t.Parallel()
+
+	var decoded []ServingKind
+	err := json.Unmarshal([]byte(`["glass","CUP"]`), &decoded)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, decoded, []ServingKind{Glass, Cup})
+
+	text, err := Bowl.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, string(text), "bowl")
+
+	kinds := []ServingKind{"", "spoonful", Bowl} // as returned by YAZIO
+	encoded, err := json.Marshal(kinds)
+	assert.NoError(t, err)
+	assert.Equal(t, string(encoded), `["","spoonful","bowl"]`)
+
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.DeepEqual(t, decoded, kinds)
+}
+
+func TestLookupServingKind(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		input  string
+		want   ServingKind
+		wantOK bool
+	}{
+		{input: " Slice ", want: Slice, wantOK: true},
+		{input: "Spoonful", want: "Spoonful"},
+		{input: "", want: ""},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := LookupServingKind(tb.input)
+			assert.Equal(t, got, tb.want)
+			assert.Equal(t, ok, tb.wantOK)
+		})
+	}
+}
+
+func TestServingKinds(t *testing.T) {
+	t.Parallel()
+
+	all := ServingKinds()
+	assert.Equal(t, slices.IsSorted(all), true)
+
+	for _, sk := range all {
+		if sk.Name(English) == "" || sk.Name(German) == "" || sk.Name(Portuguese) == "" {
+			t.Errorf("serving kind %q is missing a display name", sk)
+		}
+	}
+}
+
+func TestGuessServingKind(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		input string
+		want  ServingKind
+	}{
+		{input: "2 slices (30 g)", want: Slice},
+		{input: "1 Bottle", want: Bottle},
+		{input: "1 tbsp", want: Tablespoon},
+		{input: "3 biscuits", want: Piece},
+		{input: "cup, chopped", want: Cup},
+		{input: "2 fillets", want: Fillet},
+		{input: "30 g", want: Portion},
+		{input: "", want: Portion},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.input, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, GuessServingKind(tb.input), tb.want)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package food
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+)
+
+const (
+	// energyTolerance is the relative deviation accepted between the
+	// logged energy and the one computed from macros, since labels
+	// round values and fiber or polyols carry their own factors.
+	energyTolerance = 0.25
+
+	// energyMinDeviation (kcal) keeps low energy foods, where
+	// rounding dominates, from being flagged.
+	energyMinDeviation = 20
+
+	// weightTolerance (g) absorbs label rounding of
+	// foods made almost entirely of a single macro.
+	weightTolerance = 1
+)
+
+// requiredNutrients are the nutrients YAZIO requires to register a food.
+var requiredNutrients = []intake.Kind{
+	intake.Energy, intake.Fat,
+	intake.Protein, intake.Carb,
+}
+
+// subNutrients maps a nutrient to the ones that are part of it.
+var subNutrients = []struct {
+	name   string
+	parent intake.Kind
+	parts  []intake.Kind
+}{
+	{"sugar", intake.Carb, []intake.Kind{intake.Sugar}},
+	{"added sugar", intake.Sugar, []intake.Kind{intake.AddedSugar}},
+	{"fat subtypes", intake.Fat, []intake.Kind{
+		intake.Saturated,
+		intake.Monounsaturated,
+		intake.Polyunsaturated,
+		intake.TransFat,
+	}},
+}
+
+// Validate checks f for mistakes that YAZIO accepts, or
+// rejects with little detail, before it's registered.
+//
+// Every problem found is reported, joined with [errors.Join],
+// so each one can be checked with [errors.Is]. It returns nil
+// when f is valid. The joined errors wrap either:
+//   - [ErrInvalidName]
+//   - [ErrUnknownCategory]
+//   - [ErrMissingNutrients]
+//   - [ErrNegativeNutrient]
+//   - [ErrNutrientExceedsTotal] e.g. sugar over carb, saturated over fat
+//   - [ErrImplausibleEnergy] if energy deviates from the one computed from macros
+//   - [ErrMacrosExceedWeight] if macros sum over 100g per 100g
+//   - [ErrUnknownServingKind]
+//   - [ErrInvalidServing] if a serving amount isn't positive
+//   - [ErrDuplicateServing]
+func (f Food) Validate() error {
+	var errs []error
+
+	if len(f.Name) < nameMinLength {
+		errs = append(errs, fmt.Errorf("%w: %q should have at least 3 chars", ErrInvalidName, f.Name))
+	}
+
+	if !f.Category.known() {
+		errs = append(errs, fmt.Errorf("%w: %q", ErrUnknownCategory, f.Category))
+	}
+
+	errs = append(errs, f.Nutrients.validate(f.BaseUnit)...)
+	errs = append(errs, validateServings(f.Servings)...)
+
+	return errors.Join(errs...)
+}
+
+func (n Nutrients) validate(base unit.Base) []error {
+	var (
+		errs     []error
+		complete = true
+	)
+
+	for _, k := range requiredNutrients {
+		if _, ok := n[k]; !ok {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrMissingNutrients, k))
+			complete = false
+		}
+	}
+
+	for _, k := range intake.AllKinds() { // stable order
+		if v, ok := n[k]; ok && v < 0 {
+			errs = append(errs, fmt.Errorf("%w: %s is %g", ErrNegativeNutrient, k, v))
+		}
+	}
+
+	for _, sub := range subNutrients {
+		parent, ok := n[sub.parent]
+		if !ok {
+			continue
+		}
+
+		var total float64
+		for _, k := range sub.parts {
+			total += n[k]
+		}
+
+		if total > parent {
+			errs = append(errs, fmt.Errorf("%w: %s (%g) is over %s (%g)",
+				ErrNutrientExceedsTotal,
+				sub.name,
+				total,
+				sub.parent,
+				parent,
+			))
+		}
+	}
+
+	macros := intake.Macros{
+		Energy:  n[intake.Energy],
+		Carb:    n[intake.Carb],
+		Fat:     n[intake.Fat],
+		Protein: n[intake.Protein],
+	}
+
+	var (
+		computed  = macros.ComputedEnergy() + n[intake.Alcohol]*intake.AlcoholDensity*intake.KcalPerGramAlcohol
+		deviation = math.Abs(macros.Energy - computed)
+	)
+
+	if complete && deviation > energyMinDeviation && deviation > computed*energyTolerance {
+		errs = append(errs, fmt.Errorf("%w: %gkcal logged, %.0fkcal from macros",
+			ErrImplausibleEnergy,
+			macros.Energy,
+			computed,
+		))
+	}
+
+	if weight := macros.Carb + macros.Fat + macros.Protein; base == unit.Gram && weight > 100+weightTolerance {
+		errs = append(errs, fmt.Errorf("%w: %gg of macros per 100g", ErrMacrosExceedWeight, weight))
+	}
+
+	return errs
+}
+
+func validateServings(servings []Serving) []error {
+	var (
+		errs []error
+		seen = make(map[ServingKind]bool, len(servings))
+	)
+
+	for _, s := range servings {
+		if !s.Kind.known() {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrUnknownServingKind, s.Kind))
+		}
+
+		if s.Amount <= 0 {
+			errs = append(errs, fmt.Errorf("%w: %q amount %g", ErrInvalidServing, s.Kind, s.Amount))
+		}
+
+		if seen[s.Kind] {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrDuplicateServing, s.Kind))
+		}
+		seen[s.Kind] = true
+	}
+
+	return errs
+}
@@ -0,0 +1,162 @@
+package food
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+)
+
+func TestFood_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := func() Food {
+		return Food{
+			Name:     "Chocolate milk",
+			BaseUnit: unit.Gram,
+			Category: Chocolate,
+			Nutrients: Nutrients{
+				intake.Energy:     83,
+				intake.Carb:       10.5,
+				intake.Sugar:      10,
+				intake.AddedSugar: 5,
+				intake.Fat:        3.4,
+				intake.Saturated:  2,
+				intake.Protein:    3.2,
+			},
+			Servings: []Serving{
+				{Kind: Glass, Amount: 250},
+				{Kind: Bottle, Amount: 500},
+			},
+		}
+	}
+
+	testBlocks := []struct {
+		name     string
+		modify   func(f *Food)
+		wantErrs []error
+	}{
+		{
+			name:   "valid",
+			modify: func(*Food) {},
+		},
+		{
+			name: "low energy rounding",
+			modify: func(f *Food) {
+				f.Nutrients = Nutrients{intake.Energy: 0, intake.Carb: 0.5, intake.Fat: 0.5, intake.Protein: 0.5}
+			},
+		},
+		{
+			name:     "invalid name and category",
+			modify:   func(f *Food) { f.Name, f.Category = "Mi", "gadgets" },
+			wantErrs: []error{ErrInvalidName, ErrUnknownCategory},
+		},
+		{
+			name: "missing nutrient skips energy check",
+			modify: func(f *Food) {
+				delete(f.Nutrients, intake.Protein)
+				f.Nutrients[intake.Energy] = 500
+			},
+			wantErrs: []error{ErrMissingNutrients},
+		},
+		{
+			name:     "negative value",
+			modify:   func(f *Food) { f.Nutrients[intake.Sodium] = -1 },
+			wantErrs: []error{ErrNegativeNutrient},
+		},
+		{
+			name:     "sugar over carb",
+			modify:   func(f *Food) { f.Nutrients[intake.Sugar] = 11 },
+			wantErrs: []error{ErrNutrientExceedsTotal},
+		},
+		{
+			name: "fat subtypes over fat",
+			modify: func(f *Food) {
+				f.Nutrients[intake.Monounsaturated] = 1
+				f.Nutrients[intake.Polyunsaturated] = 0.5
+			},
+			wantErrs: []error{ErrNutrientExceedsTotal},
+		},
+		{
+			name:     "implausible energy",
+			modify:   func(f *Food) { f.Nutrients[intake.Energy] = 300 },
+			wantErrs: []error{ErrImplausibleEnergy},
+		},
+		{
+			name: "macros over 100g",
+			modify: func(f *Food) {
+				f.Nutrients = Nutrients{intake.Energy: 820, intake.Carb: 60, intake.Fat: 60, intake.Protein: 10}
+			},
+			wantErrs: []error{ErrMacrosExceedWeight},
+		},
+		{
+			name: "macros over 100ml",
+			modify: func(f *Food) {
+				f.BaseUnit = unit.Milliliter
+				f.Nutrients = Nutrients{intake.Energy: 820, intake.Carb: 60, intake.Fat: 60, intake.Protein: 10}
+			},
+		},
+		{
+			name: "servings",
+			modify: func(f *Food) {
+				f.Servings = append(f.Servings,
+					Serving{Kind: Glass, Amount: 200},
+					Serving{Kind: "spoonful", Amount: 0},
+				)
+			},
+			wantErrs: []error{ErrDuplicateServing, ErrUnknownServingKind, ErrInvalidServing},
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := valid()
+			tb.modify(&f)
+
+			err := f.Validate()
+			if len(tb.wantErrs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			for _, want := range tb.wantErrs {
+				if !errors.Is(err, want) {
+					t.Errorf("got %v, want %v", err, want)
+				}
+			}
+
+			joined, ok := err.(interface{ Unwrap() []error })
+			assert.Equal(t, ok, true)
+			assert.Equal(t, len(joined.Unwrap()), len(tb.wantErrs))
+		})
+	}
+}
+
+func TestIsValidationError(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "validation", err: fmt.Errorf("adding food: %w", ErrImplausibleEnergy), want: true},
+		{name: "joined", err: errors.Join(ErrNegativeNutrient, ErrDuplicateServing), want: true},
+		{name: "from New", err: ErrInvalidName, want: true},
+		{name: "conflict", err: ErrAlreadyExists},
+		{name: "other", err: errors.New("boom")},
+		{name: "nil"},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, IsValidationError(tb.err), tb.want)
+		})
+	}
+}
@@ -0,0 +1,7 @@
+package intake
+
+import "errors"
+
+var (
+	ErrUnknownKind = errors.New("given intake kind is unknown")
+)
@@ -1,9 +1,32 @@
 package intake
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/controlado/go-yazio/pkg/domain/unit"
 )
 
+const (
+	EnergyCategory   Category = "energy"
+	NutrientCategory Category = "nutrient"
+	VitaminCategory  Category = "vitamin"
+	MineralCategory  Category = "mineral"
+)
+
+// Category groups the kinds of intake, and it's
+// the prefix of their IDs (e.g. "mineral.zinc").
+type Category string
+
+func (c Category) String() string {
+	return string(c)
+}
+
+// Kind identifies a nutrient tracked by YAZIO.
+//
+// The zero value is not a valid kind; use the package
+// variables, or resolve them with [KindByID].
 type Kind struct {
 	id       string
 	baseUnit unit.Base
@@ -17,6 +40,106 @@ func (k Kind) Unit() string {
 	return k.baseUnit.String()
 }
 
+// BaseUnit returns the unit YAZIO measures k with.
+func (k Kind) BaseUnit() unit.Base {
+	return k.baseUnit
+}
+
+// FromUnit converts v, measured in from, into the base unit of k.
+//
+// Besides compatible units, [Alcohol] and [Water] are converted
+// from mass, by their density.
+//
+// On failure the error wraps either:
+//   - [unit.ErrUnknownUnit]
+//   - [unit.ErrIncompatibleUnits]
+func (k Kind) FromUnit(v float64, from unit.Base) (float64, error) {
+	if from.Dimension() == unit.Mass && k.baseUnit.Dimension() == unit.Volume {
+		grams, err := unit.Convert(v, from, unit.Gram)
+		if err != nil {
+			return 0, err
+		}
+
+		switch k {
+		case Alcohol:
+			return unit.Convert(grams/AlcoholDensity, unit.Milliliter, k.baseUnit)
+		case Water:
+			return unit.Convert(grams, unit.Milliliter, k.baseUnit) // 1 g/ml
+		}
+	}
+
+	return unit.Convert(v, from, k.baseUnit)
+}
+
+func (k Kind) String() string {
+	return k.id
+}
+
+// Category returns the [Category] of k.
+func (k Kind) Category() Category {
+	prefix, _, _ := strings.Cut(k.id, ".")
+	return Category(prefix)
+}
+
+// MarshalText encodes k as its ID, which also makes
+// k usable as a JSON object key.
+func (k Kind) MarshalText() ([]byte, error) {
+	if k.id == "" {
+		return nil, fmt.Errorf("%w: zero-value kind", ErrUnknownKind)
+	}
+	return []byte(k.id), nil
+}
+
+// UnmarshalText decodes a kind from its ID.
+func (k *Kind) UnmarshalText(text []byte) error {
+	parsed, err := KindByID(string(text))
+	if err != nil {
+		return err
+	}
+
+	*k = parsed
+	return nil
+}
+
+// KindByID resolves the [Kind] identified by id (e.g. "mineral.zinc").
+//
+// On failure the error wraps either:
+//   - [ErrUnknownKind]
+func KindByID(id string) (Kind, error) {
+	k, ok := kindsByID[id]
+	if !ok {
+		return k, fmt.Errorf("%w: %q", ErrUnknownKind, id)
+	}
+	return k, nil
+}
+
+// AllKinds returns every known [Kind], grouped
+// by category in a stable order.
+func AllKinds() []Kind {
+	return slices.Clone(allKinds)
+}
+
+// KindsIn returns the known kinds of category c.
+func KindsIn(c Category) []Kind {
+	var out []Kind
+	for _, k := range allKinds {
+		if k.Category() == c {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// Categories returns every [Category] of the known kinds.
+func Categories() []Category {
+	return []Category{
+		EnergyCategory,
+		NutrientCategory,
+		VitaminCategory,
+		MineralCategory,
+	}
+}
+
 var (
 	Energy            = Kind{"energy.energy", unit.Kilocalorie}
 	Fat               = Kind{"nutrient.fat", unit.Gram}
@@ -73,3 +196,32 @@ var (
 	Water             = Kind{"nutrient.water", unit.Milliliter}
 	Alcohol           = Kind{"nutrient.alcohol", unit.Milliliter}
 )
+
+var (
+	allKinds = []Kind{
+		Energy,
+
+		Fat, Saturated, Monounsaturated, Polyunsaturated, TransFat,
+		Cholesterol, Sodium, Salt, Carb, Fiber, Sugar, AddedSugar,
+		Protein, Water, Alcohol,
+
+		VitaminA, VitaminB1, VitaminB2, VitaminB3, VitaminB5, VitaminB6,
+		VitaminB7, VitaminB11, VitaminB12, VitaminC, VitaminD, VitaminE,
+		VitaminK,
+
+		Calcium, Iron, Potassium, MineralArsenic, MineralBoron,
+		MineralBiotin, MineralCholine, MineralChlorine, MineralChrome,
+		MineralCobalt, MineralCopper, MineralFluoride, MineralFluorine,
+		MineralIodine, MineralMagnesium, MineralManganese,
+		MineralMolybdenum, MineralPhosphorus, MineralRubidium,
+		MineralSelenium, MineralSilicon, MineralSulfur, MineralTin,
+		MineralVanadium, MineralZinc,
+	}
+	kindsByID = func() map[string]Kind {
+		out := make(map[string]Kind, len(allKinds))
+		for _, k := range allKinds {
+			out[k.id] = k
+		}
+		return out
+	}()
+)
@@ -0,0 +1,146 @@
+package intake
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+)
+
+func TestKindByID(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name    string
+		id      string
+		want    Kind
+		wantErr bool
+	}{
+		{name: "mineral", id: "mineral.zinc", want: MineralZinc},
+		{name: "energy", id: "energy.energy", want: Energy},
+		{name: "vitamin", id: "vitamin.b12", want: VitaminB12},
+		{name: "unknown", id: "mineral.kryptonite", wantErr: true},
+		{name: "blank", id: "", wantErr: true},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := KindByID(tb.id)
+			if tb.wantErr && !errors.Is(err, ErrUnknownKind) {
+				t.Fatalf("\nwant ErrUnknownKind\ngot %v", err)
+			}
+			assert.WantErr(t, tb.wantErr, err)
+			assert.Equal(t, got, tb.want)
+		})
+	}
+}
+
+func TestAllKinds(t *testing.T) {
+	t.Parallel()
+
+	var (
+		all  = AllKinds()
+		seen = make(map[string]bool, len(all))
+	)
+
+	for _, k := range all {
+		if seen[k.ID()] {
+			t.Fatalf("duplicated kind %s", k)
+		}
+		seen[k.ID()] = true
+
+		got, err := KindByID(k.ID())
+		assert.NoError(t, err)
+		assert.Equal(t, got, k)
+	}
+
+	var grouped int
+	for _, c := range Categories() {
+		for _, k := range KindsIn(c) {
+			assert.Equal(t, k.Category(), c)
+			grouped++
+		}
+	}
+	assert.Equal(t, grouped, len(all))
+
+	all[0] = Kind{} // callers get a copy
+	assert.Equal(t, AllKinds()[0], Energy)
+}
+
+func TestKind_Text(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Tracked []Kind           `json:"tracked"`
+		Goals   map[Kind]float64 `json:"goals"`
+	}
+
+	const (
+		raw = `{"tracked":["mineral.zinc","nutrient.water"],"goals":{"vitamin.c":90}}`
+	)
+
+	var got config
+	err := json.Unmarshal([]byte(raw), &got)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, got, config{
+		Tracked: []Kind{MineralZinc, Water},
+		Goals:   map[Kind]float64{VitaminC: 90},
+	})
+
+	encoded, err := json.Marshal(got)
+	assert.NoError(t, err)
+	assert.Equal(t, string(encoded), raw)
+
+	err = json.Unmarshal([]byte(`{"tracked":["nutrient.unobtanium"]}`), &got)
+	if !errors.Is(err, ErrUnknownKind) {
+		t.Fatalf("\nwant ErrUnknownKind\ngot %v", err)
+	}
+
+	_, err = json.Marshal(Kind{})
+	if !errors.Is(err, ErrUnknownKind) {
+		t.Fatalf("\nwant ErrUnknownKind\ngot %v", err)
+	}
+
+	assert.Equal(t, MineralZinc.String(), "mineral.zinc")
+}
+
+func TestKind_FromUnit(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name    string
+		kind    Kind
+		value   float64
+		from    unit.Base
+		want    float64
+		wantErr error
+	}{
+		{name: "same unit", kind: Fat, value: 12, from: unit.Gram, want: 12},
+		{name: "mg to mcg", kind: Sodium, value: 480, from: unit.Milligram, want: 480000},
+		{name: "kJ to kcal", kind: Energy, value: 4184, from: unit.Kilojoule, want: 1000},
+		{name: "water by mass", kind: Water, value: 250, from: unit.Gram, want: 250},
+		{name: "alcohol by mass", kind: Alcohol, value: 7.89, from: unit.Gram, want: 10},
+		{name: "incompatible", kind: VitaminD, value: 400, from: unit.Milliliter, wantErr: unit.ErrIncompatibleUnits},
+		{name: "unknown unit", kind: VitaminD, value: 400, from: "IU", wantErr: unit.ErrUnknownUnit},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tb.kind.FromUnit(tb.value, tb.from)
+			if tb.wantErr != nil {
+				if !errors.Is(err, tb.wantErr) {
+					t.Fatalf("got %v, want %v", err, tb.wantErr)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assertNear(t, got, tb.want)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package intake
+
+import (
+	"slices"
+	"time"
+)
+
+// Matrix is a day-by-kind table of single intakes.
+//
+// Rows are sorted chronologically, and each one holds
+// the values logged that day keyed by [Kind]. A kind
+// missing from a row means no data for that day.
+type Matrix struct {
+	Kinds []Kind      // Kinds are the matrix columns, in the requested order.
+	Rows  []MatrixRow // Rows are the matrix days, in chronological order.
+}
+
+// MatrixRow holds the intakes of a single day.
+type MatrixRow struct {
+	Date   time.Time
+	Values map[Kind]float64
+}
+
+type dayKey struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func keyOf(t time.Time) dayKey {
+	y, m, d := t.Date()
+	return dayKey{y, m, d}
+}
+
+// NewMatrix builds a [Matrix] with the columns kinds, filled with the
+// values of series. Kinds found in series but not in kinds are
+// appended as extra columns.
+func NewMatrix(kinds []Kind, series ...SingleRange) Matrix {
+	var (
+		m    = Matrix{Kinds: slices.Clone(kinds)}
+		rows = make(map[dayKey]int)
+	)
+
+	for _, sr := range series {
+		for _, s := range sr {
+			if !slices.Contains(m.Kinds, s.Kind) {
+				m.Kinds = append(m.Kinds, s.Kind)
+			}
+
+			key := keyOf(s.Date)
+			i, ok := rows[key]
+			if !ok {
+				i = len(m.Rows)
+				rows[key] = i
+				m.Rows = append(m.Rows, MatrixRow{
+					Date:   s.Date,
+					Values: make(map[Kind]float64),
+				})
+			}
+
+			if s.HasData() { // gaps keep the day, without a value
+				m.Rows[i].Values[s.Kind] = s.Value
+			}
+		}
+	}
+
+	slices.SortFunc(m.Rows, func(a, b MatrixRow) int {
+		return a.Date.Compare(b.Date)
+	})
+
+	return m
+}
+
+// Value returns the intake of k logged on day, if any.
+func (m Matrix) Value(day time.Time, k Kind) (float64, bool) {
+	key := keyOf(day)
+
+	for _, row := range m.Rows {
+		if keyOf(row.Date) == key {
+			value, ok := row.Values[k]
+			return value, ok
+		}
+	}
+
+	return 0, false
+}
+
+// Series returns the column k as a [SingleRange],
+// in chronological order.
+func (m Matrix) Series(k Kind) SingleRange {
+	var sr SingleRange
+
+	for _, row := range m.Rows {
+		if value, ok := row.Values[k]; ok {
+			sr = append(sr, Single{Kind: k, Date: row.Date, Value: value})
+		}
+	}
+
+	return sr
+}
+
+// Averages returns the [SingleAverage] of each column.
+//
+// Kinds without any data have a zero-value average.
+func (m Matrix) Averages() map[Kind]SingleAverage {
+	out := make(map[Kind]SingleAverage, len(m.Kinds))
+
+	for _, k := range m.Kinds {
+		out[k] = m.Series(k).Average()
+	}
+
+	return out
+}
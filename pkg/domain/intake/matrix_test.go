@@ -0,0 +1,50 @@
+package intake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+)
+
+func TestNewMatrix(t *testing.T) {
+	t.Parallel()
+
+	var (
+		firstDay  = time.Date(2025, 4, 12, 0, 0, 0, 0, time.UTC)
+		secondDay = firstDay.AddDate(0, 0, 1)
+
+		water = SingleRange{
+			{Kind: Water, Date: secondDay, Value: 2000},
+			{Kind: Water, Date: firstDay, Value: 1500},
+		}
+		zinc = SingleRange{
+			{Kind: MineralZinc, Date: firstDay, Gap: true}, // no value in the row
+			{Kind: MineralZinc, Date: secondDay, Value: 8},
+		}
+	)
+
+	m := NewMatrix([]Kind{Water, MineralZinc, VitaminC}, water, zinc)
+
+	assert.EqualSlicesItems(t, m.Kinds, []Kind{Water, MineralZinc, VitaminC})
+	assert.Equal(t, len(m.Rows), 2)
+	assert.Equal(t, m.Rows[0].Date, firstDay)
+	assert.Equal(t, m.Rows[1].Date, secondDay)
+
+	value, ok := m.Value(secondDay.Add(15*time.Hour), MineralZinc)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, value, 8.0)
+
+	_, ok = m.Value(firstDay, MineralZinc)
+	assert.Equal(t, ok, false)
+
+	assert.DeepEqual(t, m.Series(Water), SingleRange{
+		{Kind: Water, Date: firstDay, Value: 1500},
+		{Kind: Water, Date: secondDay, Value: 2000},
+	})
+
+	averages := m.Averages()
+	assert.Equal(t, averages[Water], SingleAverage{Water, 2, 1750})
+	assert.Equal(t, averages[MineralZinc], SingleAverage{MineralZinc, 1, 8})
+	assert.Equal(t, averages[VitaminC], SingleAverage{})
+}
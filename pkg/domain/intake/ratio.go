@@ -0,0 +1,172 @@
+package intake
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// Atwater general factors, in kcal per gram.
+const (
+	KcalPerGramCarb    = 4.0
+	KcalPerGramFat     = 9.0
+	KcalPerGramProtein = 4.0
+	KcalPerGramAlcohol = 7.0
+)
+
+// AlcoholDensity is the density of ethanol in g/ml, to convert
+// [Alcohol] intakes (in ml) into grams.
+const AlcoholDensity = 0.789
+
+// Distribution holds the share (in percent) of the energy
+// computed from macros that comes from each one of them.
+type Distribution struct {
+	Carb    float64
+	Fat     float64
+	Protein float64
+	Alcohol float64
+}
+
+func newDistribution(carb, fat, protein, alcohol float64) (d Distribution) {
+	var (
+		carbKcal    = carb * KcalPerGramCarb
+		fatKcal     = fat * KcalPerGramFat
+		proteinKcal = protein * KcalPerGramProtein
+		alcoholKcal = alcohol * KcalPerGramAlcohol
+		totalKcal   = carbKcal + fatKcal + proteinKcal + alcoholKcal
+	)
+
+	if totalKcal <= 0 {
+		return d
+	}
+
+	return Distribution{
+		Carb:    carbKcal / totalKcal * 100,
+		Fat:     fatKcal / totalKcal * 100,
+		Protein: proteinKcal / totalKcal * 100,
+		Alcohol: alcoholKcal / totalKcal * 100,
+	}
+}
+
+func (d Distribution) String() string {
+	s := fmt.Sprintf("Carb: %.1f%%, Fat: %.1f%%, Protein: %.1f%%", d.Carb, d.Fat, d.Protein)
+	if d.Alcohol > 0 {
+		s += fmt.Sprintf(", Alcohol: %.1f%%", d.Alcohol)
+	}
+	return s
+}
+
+// ComputedEnergy returns the energy (kcal) of m
+// computed from its macros, using Atwater factors.
+func (m Macros) ComputedEnergy() float64 {
+	return m.Carb*KcalPerGramCarb + m.Fat*KcalPerGramFat + m.Protein*KcalPerGramProtein
+}
+
+// Distribution returns the share of energy from each macro of m.
+func (m Macros) Distribution() Distribution {
+	return newDistribution(m.Carb, m.Fat, m.Protein, 0)
+}
+
+// DistributionWithAlcohol returns the share of energy from each
+// macro of m, plus alcoholGrams grams of alcohol (not part of
+// [Macros]; see [AlcoholDensity] to convert [Alcohol] intakes).
+func (m Macros) DistributionWithAlcohol(alcoholGrams float64) Distribution {
+	return newDistribution(m.Carb, m.Fat, m.Protein, alcoholGrams)
+}
+
+// ComputedEnergy returns the average energy (kcal)
+// computed from the average macros.
+func (ma MacrosAverage) ComputedEnergy() float64 {
+	return ma.Carb*KcalPerGramCarb + ma.Fat*KcalPerGramFat + ma.Protein*KcalPerGramProtein
+}
+
+// Distribution returns the share of energy from each average macro.
+func (ma MacrosAverage) Distribution() Distribution {
+	return newDistribution(ma.Carb, ma.Fat, ma.Protein, 0)
+}
+
+// DistributionWithAlcohol is like [Macros.DistributionWithAlcohol],
+// with alcoholGrams being the average daily alcohol.
+func (ma MacrosAverage) DistributionWithAlcohol(alcoholGrams float64) Distribution {
+	return newDistribution(ma.Carb, ma.Fat, ma.Protein, alcoholGrams)
+}
+
+// DailyDistribution is the [Distribution] of a single day.
+type DailyDistribution struct {
+	Date time.Time
+	Distribution
+}
+
+// Distributions returns the share of energy from
+// each macro, day by day in chronological order.
+func (mr MacrosRange) Distributions() []DailyDistribution {
+	out := make([]DailyDistribution, 0, len(mr))
+	for _, m := range mr {
+		out = append(out, DailyDistribution{Date: m.Date, Distribution: m.Distribution()})
+	}
+
+	slices.SortStableFunc(out, func(a, b DailyDistribution) int {
+		return a.Date.Compare(b.Date)
+	})
+	return out
+}
+
+// EnergyDeviation compares the logged energy of a day
+// with the energy computed from its macros.
+type EnergyDeviation struct {
+	Date     time.Time
+	Logged   float64 // Logged is the energy (kcal) reported by YAZIO.
+	Computed float64 // Computed is the energy (kcal) computed from macros.
+	Relative float64 // Relative is (Logged - Computed) / Computed.
+}
+
+func (ed EnergyDeviation) String() string {
+	return fmt.Sprintf("%s: logged %.1fkcal, computed %.1fkcal (%+.1f%%)",
+		ed.Date.Format(time.DateOnly),
+		ed.Logged,
+		ed.Computed,
+		ed.Relative*100,
+	)
+}
+
+// EnergyDeviation compares the logged energy of m
+// with the energy computed from its macros.
+//
+// Relative is infinite when energy is logged
+// without any macros.
+func (m Macros) EnergyDeviation() EnergyDeviation {
+	ed := EnergyDeviation{
+		Date:     m.Date,
+		Logged:   m.Energy,
+		Computed: m.ComputedEnergy(),
+	}
+
+	switch {
+	case ed.Computed > 0:
+		ed.Relative = (ed.Logged - ed.Computed) / ed.Computed
+	case ed.Logged > 0:
+		ed.Relative = math.Inf(1)
+	}
+
+	return ed
+}
+
+// InconsistentDays returns the days of mr whose logged energy
+// deviates from the energy computed from macros by more than
+// tolerance (e.g. 0.15 for 15%), which usually points to
+// products with bad nutrient data.
+//
+// Alcohol and fiber also add energy not computed from macros,
+// so leave some room for them in tolerance.
+func (mr MacrosRange) InconsistentDays(tolerance float64) []EnergyDeviation {
+	var out []EnergyDeviation
+
+	for _, m := range mr {
+		if ed := m.EnergyDeviation(); math.Abs(ed.Relative) > tolerance {
+			out = append(out, ed)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,68 @@
+package intake
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+)
+
+func TestMacros_Distribution(t *testing.T) {
+	t.Parallel()
+
+	m := Macros{Date: time.Now(), Energy: 2000, Carb: 250, Fat: 40, Protein: 160}
+	// 1000 + 360 + 640 = 2000kcal
+
+	assertNear(t, m.ComputedEnergy(), 2000)
+
+	d := m.Distribution()
+	assertNear(t, d.Carb, 50)
+	assertNear(t, d.Fat, 18)
+	assertNear(t, d.Protein, 32)
+	assert.Equal(t, d.Alcohol, 0.0)
+	assert.Equal(t, d.String(), "Carb: 50.0%, Fat: 18.0%, Protein: 32.0%")
+
+	d = m.DistributionWithAlcohol(1000.0 / 7) // +1000kcal
+	assertNear(t, d.Carb, 100.0/3)
+	assertNear(t, d.Alcohol, 100.0/3)
+
+	avg := MacrosRange{m}.Average()
+	assert.Equal(t, avg.Distribution(), m.Distribution())
+	assertNear(t, avg.ComputedEnergy(), 2000)
+
+	assert.Equal(t, Macros{}.Distribution(), Distribution{})
+}
+
+func TestMacrosRange_InconsistentDays(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day = func(d int) time.Time {
+			return time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC)
+		}
+		mr = MacrosRange{
+			{day(1), 2000, 250, 40, 160}, // exact
+			{day(2), 2100, 250, 40, 160}, // +5%
+			{day(3), 3000, 250, 40, 160}, // +50%: bad product
+			{day(4), 500, 0, 0, 0},       // energy only
+			{day(5), 0, 0, 0, 0},         // nothing logged
+		}
+	)
+
+	got := mr.InconsistentDays(0.10)
+	assert.Equal(t, len(got), 2)
+	assert.Equal(t, got[0].Date, day(3))
+	assertNear(t, got[0].Relative, 0.5)
+	assert.Equal(t, got[0].String(), "2025-04-03: logged 3000.0kcal, computed 2000.0kcal (+50.0%)")
+	assert.Equal(t, got[1].Date, day(4))
+	assert.Equal(t, math.IsInf(got[1].Relative, 1), true)
+
+	ds := MacrosRange{mr[2], mr[0], mr[4]}.Distributions()
+	assert.Equal(t, len(ds), 3)
+	assert.Equal(t, ds[0].Date, day(1))
+	assert.Equal(t, ds[1].Date, day(3))
+	assert.Equal(t, ds[2].Date, day(5))
+	assert.Equal(t, ds[0].Distribution, mr[0].Distribution())
+	assert.Equal(t, ds[2].Distribution, Distribution{})
+}
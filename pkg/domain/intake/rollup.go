@@ -0,0 +1,114 @@
+package intake
+
+import (
+	"slices"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/date"
+)
+
+// MacrosBucket holds the days of a [MacrosRange]
+// falling into the same bucket of a [date.Period].
+type MacrosBucket struct {
+	Label   string        // Label names the bucket, see [date.Period.Label].
+	Range   date.Range    // Range spans the bucket days.
+	Days    MacrosRange   // Days are the bucket days, sorted by date.
+	Total   Macros        // Total sums the bucket days (dated at Range.Start).
+	Average MacrosAverage // Average is the mean of the bucket days.
+}
+
+// GroupBy buckets the days of mr by p,
+// in chronological order.
+func (mr MacrosRange) GroupBy(p date.Period) []MacrosBucket {
+	var buckets []MacrosBucket
+
+	for _, group := range groupBy(mr, p, func(m Macros) time.Time { return m.Date }) {
+		b := MacrosBucket{
+			Label:   p.Label(group[0].Date),
+			Range:   p.Bucket(group[0].Date),
+			Days:    group,
+			Average: group.Average(),
+		}
+
+		b.Total.Date = b.Range.Start
+		for _, m := range group {
+			b.Total.Energy += m.Energy
+			b.Total.Carb += m.Carb
+			b.Total.Fat += m.Fat
+			b.Total.Protein += m.Protein
+		}
+
+		buckets = append(buckets, b)
+	}
+
+	return buckets
+}
+
+// SingleBucket holds the days of a [SingleRange]
+// falling into the same bucket of a [date.Period].
+type SingleBucket struct {
+	Label   string        // Label names the bucket, see [date.Period.Label].
+	Range   date.Range    // Range spans the bucket days.
+	Days    SingleRange   // Days are the bucket days, sorted by date.
+	Total   float64       // Total sums the bucket days holding data.
+	Average SingleAverage // Average is the mean of the bucket days holding data.
+}
+
+// GroupBy buckets the days of sr by p,
+// in chronological order.
+func (sr SingleRange) GroupBy(p date.Period) []SingleBucket {
+	var buckets []SingleBucket
+
+	for _, group := range groupBy(sr, p, func(s Single) time.Time { return s.Date }) {
+		b := SingleBucket{
+			Label:   p.Label(group[0].Date),
+			Range:   p.Bucket(group[0].Date),
+			Days:    group,
+			Average: group.Average(),
+		}
+
+		for _, s := range group {
+			if s.HasData() {
+				b.Total += s.Value
+			}
+		}
+
+		buckets = append(buckets, b)
+	}
+
+	return buckets
+}
+
+// groupBy splits days by bucket of p, both the
+// groups and their days sorted chronologically.
+func groupBy[S ~[]E, E any](days S, p date.Period, dateOf func(E) time.Time) []S {
+	var (
+		groups []S
+		index  = make(map[date.Range]int)
+	)
+
+	for _, e := range days {
+		b := p.Bucket(dateOf(e))
+
+		i, ok := index[b]
+		if !ok {
+			i = len(groups)
+			index[b] = i
+			groups = append(groups, nil)
+		}
+
+		groups[i] = append(groups[i], e)
+	}
+
+	for _, g := range groups {
+		slices.SortStableFunc(g, func(a, b E) int {
+			return dateOf(a).Compare(dateOf(b))
+		})
+	}
+
+	slices.SortFunc(groups, func(a, b S) int {
+		return dateOf(a[0]).Compare(dateOf(b[0]))
+	})
+
+	return groups
+}
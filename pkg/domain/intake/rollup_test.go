@@ -0,0 +1,67 @@
+package intake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/domain/date"
+)
+
+func TestMacrosRange_GroupBy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day = func(m time.Month, d int) time.Time {
+			return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC)
+		}
+		mr = MacrosRange{
+			{day(4, 14), 2000, 200, 80, 120}, // W16
+			{day(4, 12), 1800, 180, 60, 100}, // W15
+			{day(4, 13), 2200, 220, 100, 140},
+		}
+	)
+
+	got := mr.GroupBy(date.Weekly())
+	assert.Equal(t, len(got), 2)
+
+	assert.Equal(t, got[0].Label, "2025-W15")
+	assert.Equal(t, got[0].Range, date.Range{Start: day(4, 7), End: day(4, 13)})
+	assert.Equal(t, len(got[0].Days), 2)
+	assert.Equal(t, got[0].Days[0].Date, day(4, 12))
+	assert.Equal(t, got[0].Total, Macros{day(4, 7), 4000, 400, 160, 240})
+	assert.Equal(t, got[0].Average, MacrosAverage{2, 2000, 200, 80, 120})
+
+	assert.Equal(t, got[1].Label, "2025-W16")
+	assert.Equal(t, got[1].Total.Energy, 2000.0)
+
+	monthly := mr.GroupBy(date.Monthly())
+	assert.Equal(t, len(monthly), 1)
+	assert.Equal(t, monthly[0].Average.DaysLength, 3)
+}
+
+func TestSingleRange_GroupBy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day = func(m time.Month, d int) time.Time {
+			return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC)
+		}
+		sr = SingleRange{
+			{Kind: Water, Date: day(3, 31), Value: 1000},
+			{Kind: Water, Date: day(4, 1), Gap: true},
+			{Kind: Water, Date: day(4, 2), Value: 3000},
+		}
+	)
+
+	got := sr.GroupBy(date.Monthly())
+	assert.Equal(t, len(got), 2)
+	assert.Equal(t, got[0].Label, "2025-03")
+	assert.Equal(t, got[0].Total, 1000.0)
+	assert.Equal(t, got[1].Label, "2025-04")
+	assert.Equal(t, len(got[1].Days), 2)
+	assert.Equal(t, got[1].Total, 3000.0)
+	assert.Equal(t, got[1].Average, SingleAverage{Water, 1, 3000})
+
+	assert.Equal(t, len(SingleRange{}.GroupBy(date.Weekly())), 0)
+}
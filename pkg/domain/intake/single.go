@@ -2,33 +2,104 @@ package intake
 
 import (
 	"fmt"
+	"slices"
 	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+)
+
+// FillMode defines how [SingleRange.Fill]
+// represents days without data.
+type FillMode int
+
+const (
+	FillNone   FillMode = iota // FillNone leaves missing days out.
+	FillZero                   // FillZero inserts days valued zero.
+	FillNoData                 // FillNoData inserts days marked as gaps (see [Single.Gap]).
 )
 
 type Single struct {
 	Kind  Kind
 	Date  time.Time
 	Value float64
+	Gap   bool // Gap marks a day filled with [FillNoData]: its zero Value wasn't logged.
+}
+
+// HasData reports whether s holds a logged value,
+// instead of being a gap.
+func (s Single) HasData() bool {
+	return !s.Gap
+}
+
+// In returns the value of s expressed in the unit to
+// (e.g. [VitaminC] in [unit.Microgram]).
+//
+// On failure the error wraps either:
+//   - [unit.ErrUnknownUnit]
+//   - [unit.ErrIncompatibleUnits]
+func (s Single) In(to unit.Base) (float64, error) {
+	return unit.Convert(s.Value, s.Kind.baseUnit, to)
 }
 
 type SingleRange []Single
 
+// Sort sorts sr in place by date, oldest first.
+func (sr SingleRange) Sort() {
+	slices.SortStableFunc(sr, func(a, b Single) int {
+		return a.Date.Compare(b.Date)
+	})
+}
+
+// Fill returns a copy of sr, sorted by date, where every day of r
+// without a value is added as an intake of k, according to mode.
+//
+// k is required (instead of taken from sr) since sr may be empty.
+func (sr SingleRange) Fill(k Kind, r date.Range, mode FillMode) SingleRange {
+	out := slices.Clone(sr)
+
+	if mode == FillNone {
+		out.Sort()
+		return out
+	}
+
+	logged := make(map[dayKey]bool, len(sr))
+	for _, s := range sr {
+		logged[keyOf(s.Date)] = true
+	}
+
+	for _, day := range r.Dates() {
+		if !logged[keyOf(day)] {
+			out = append(out, Single{Kind: k, Date: day, Gap: mode == FillNoData})
+		}
+	}
+
+	out.Sort()
+	return out
+}
+
+// Average returns the mean value of the days in sr
+// holding data; gaps are ignored.
 func (sr SingleRange) Average() SingleAverage {
 	var (
 		kindSample  Kind
 		totalValues float64
-		rangeLength = len(sr)
+		rangeLength int
 	)
 
-	if rangeLength == 0 {
-		return SingleAverage{}
-	}
-
-	for i, intake := range sr {
-		if i == 0 {
+	for _, intake := range sr {
+		if !intake.HasData() {
+			continue
+		}
+		if rangeLength == 0 {
 			kindSample = intake.Kind
 		}
 		totalValues += intake.Value
+		rangeLength++
+	}
+
+	if rangeLength == 0 {
+		return SingleAverage{}
 	}
 
 	return SingleAverage{
@@ -1,10 +1,13 @@
 package intake
 
 import (
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
 )
 
 func TestSingleRange_Average(t *testing.T) {
@@ -20,10 +23,10 @@ func TestSingleRange_Average(t *testing.T) {
 			{
 				name: "avarage should be 4.25",
 				sr: SingleRange{
-					{Water, defaultDate, 2},
-					{Water, defaultDate, 3},
-					{Water, defaultDate, 5},
-					{Water, defaultDate, 7},
+					{Kind: Water, Date: defaultDate, Value: 2},
+					{Kind: Water, Date: defaultDate, Value: 3},
+					{Kind: Water, Date: defaultDate, Value: 5},
+					{Kind: Water, Date: defaultDate, Value: 7},
 				},
 				want: SingleAverage{Water, 4, 4.25},
 			},
@@ -79,3 +82,130 @@ func TestSingleAverage_String(t *testing.T) {
 		})
 	}
 }
+
+func TestSingleRange_Fill(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day = func(d int) time.Time {
+			return time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC)
+		}
+		r  = date.Range{Start: day(10), End: day(13)}
+		sr = SingleRange{
+			{Kind: Water, Date: day(12), Value: 0}, // real zero
+			{Kind: Water, Date: day(11), Value: 1500},
+		}
+	)
+
+	testBlocks := []struct {
+		name      string
+		mode      FillMode
+		wantDays  []time.Time
+		wantData  []bool
+		wantValue []float64
+	}{
+		{
+			name:      "none: only sorted",
+			mode:      FillNone,
+			wantDays:  []time.Time{day(11), day(12)},
+			wantData:  []bool{true, true},
+			wantValue: []float64{1500, 0},
+		},
+		{
+			name:      "zero",
+			mode:      FillZero,
+			wantDays:  []time.Time{day(10), day(11), day(12), day(13)},
+			wantData:  []bool{true, true, true, true},
+			wantValue: []float64{0, 1500, 0, 0},
+		},
+		{
+			name:      "no data",
+			mode:      FillNoData,
+			wantDays:  []time.Time{day(10), day(11), day(12), day(13)},
+			wantData:  []bool{false, true, true, false},
+			wantValue: []float64{0, 1500, 0, 0},
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := sr.Fill(Water, r, tb.mode)
+			assert.Equal(t, len(got), len(tb.wantDays))
+
+			for i, s := range got {
+				assert.Equal(t, s.Kind, Water)
+				assert.Equal(t, s.Date, tb.wantDays[i])
+				assert.Equal(t, s.HasData(), tb.wantData[i])
+				assert.Equal(t, s.Value, tb.wantValue[i])
+			}
+		})
+	}
+}
+
+func TestSingleRange_AverageNoData(t *testing.T) {
+	t.Parallel()
+
+	sr := SingleRange{
+		{Kind: Water, Date: time.Now(), Value: 1000},
+		{Kind: Water, Date: time.Now(), Gap: true},
+		{Kind: Water, Date: time.Now(), Value: 2000},
+	}
+
+	assert.Equal(t, sr.Average(), SingleAverage{Water, 2, 1500})
+}
+
+func TestSingle_In(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name    string
+		s       Single
+		to      unit.Base
+		want    float64
+		wantErr error
+	}{
+		{
+			name: "vitamin c in micrograms",
+			s:    Single{Kind: VitaminC, Value: 90},
+			to:   unit.Microgram,
+			want: 90_000,
+		},
+		{
+			name: "sodium in milligrams",
+			s:    Single{Kind: Sodium, Value: 1_500_000},
+			to:   unit.Milligram,
+			want: 1500,
+		},
+		{
+			name: "water in liters",
+			s:    Single{Kind: Water, Value: 2500},
+			to:   unit.Liter,
+			want: 2.5,
+		},
+		{
+			name:    "water in grams",
+			s:       Single{Kind: Water, Value: 2500},
+			to:      unit.Gram,
+			wantErr: unit.ErrIncompatibleUnits,
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tb.s.In(tb.to)
+			if tb.wantErr != nil {
+				if !errors.Is(err, tb.wantErr) {
+					t.Fatalf("got %v, want %v", err, tb.wantErr)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, got, tb.want)
+		})
+	}
+}
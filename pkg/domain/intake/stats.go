@@ -0,0 +1,278 @@
+package intake
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/date"
+)
+
+// Stats summarizes the daily values of a series.
+//
+// Days without data (gaps or missing days) are
+// not part of the summary, unless [IncludeUnlogged] is used.
+type Stats struct {
+	LoggedDays   int     // LoggedDays is how many days hold data.
+	CalendarDays int     // CalendarDays is how many days the series spans.
+	Count        int     // Count is how many values were summarized.
+	Sum          float64 // Sum is the total of the values.
+	Min          float64 // Min is the smallest value.
+	Max          float64 // Max is the largest value.
+	Mean         float64 // Mean is the arithmetic average of the values.
+	Median       float64 // Median is the 50th percentile of the values.
+	StdDev       float64 // StdDev is the population standard deviation.
+
+	sorted []float64
+}
+
+// Percentile returns the p-th percentile (0 to 100) of the summarized
+// values, linearly interpolated between the closest ranks.
+func (s Stats) Percentile(p float64) float64 {
+	n := len(s.sorted)
+	if n == 0 {
+		return 0
+	}
+
+	rank := math.Max(0, math.Min(p, 100)) / 100 * float64(n-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	weight := rank - float64(lower)
+
+	return s.sorted[lower]*(1-weight) + s.sorted[upper]*weight
+}
+
+// Coverage returns the share (0 to 1) of
+// calendar days that hold data.
+func (s Stats) Coverage() float64 {
+	if s.CalendarDays == 0 {
+		return 0
+	}
+	return float64(s.LoggedDays) / float64(s.CalendarDays)
+}
+
+type statsConfig struct {
+	excludeZero     bool
+	includeUnlogged bool
+	calendar        *date.Range
+}
+
+type StatsOption func(c *statsConfig)
+
+// ExcludeZero leaves days valued zero out of the summary.
+//
+// For [MacrosRange] a day is zero when all of its macros are.
+func ExcludeZero() StatsOption {
+	return func(c *statsConfig) {
+		c.excludeZero = true
+	}
+}
+
+// IncludeUnlogged summarizes the calendar days
+// without data as if they were valued zero.
+func IncludeUnlogged() StatsOption {
+	return func(c *statsConfig) {
+		c.includeUnlogged = true
+	}
+}
+
+// Over makes the calendar days the days of r, instead of
+// the days between the first and the last value.
+// Values of days outside r are left out.
+func Over(r date.Range) StatsOption {
+	return func(c *statsConfig) {
+		c.calendar = &r
+	}
+}
+
+func newStatsConfig(opts []StatsOption) statsConfig {
+	var c statsConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// within reports whether the day of t is part of the
+// calendar range, if one was configured.
+func (c statsConfig) within(t time.Time) bool {
+	if c.calendar == nil {
+		return true
+	}
+
+	var (
+		day   = civilDay(t)
+		start = civilDay(c.calendar.Start)
+		end   = civilDay(c.calendar.End)
+	)
+
+	return !day.Before(start) && !day.After(end)
+}
+
+// civilDay returns the calendar day of t (in its own
+// location) as midnight UTC, so days compare as dates.
+func civilDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// calendarDays returns the days spanned by dates,
+// unless a calendar range was configured.
+func (c statsConfig) calendarDays(dates []time.Time) int {
+	if c.calendar != nil {
+		return c.calendar.Days()
+	}
+
+	if len(dates) == 0 {
+		return 0
+	}
+
+	r := date.Range{
+		Start: slices.MinFunc(dates, time.Time.Compare),
+		End:   slices.MaxFunc(dates, time.Time.Compare),
+	}
+	return r.Days()
+}
+
+// summarize computes [Stats] over values (from logged days only).
+//
+// Days excluded as zero don't count as logged, so
+// [IncludeUnlogged] fills them back as zeros.
+func (c statsConfig) summarize(values []float64, calendarDays int) Stats {
+	if c.excludeZero {
+		values = slices.DeleteFunc(values, func(v float64) bool { return v == 0 })
+	}
+
+	s := Stats{
+		LoggedDays:   len(values),
+		CalendarDays: calendarDays,
+	}
+
+	if c.includeUnlogged {
+		for range calendarDays - s.LoggedDays {
+			values = append(values, 0)
+		}
+	}
+
+	if len(values) == 0 {
+		return s
+	}
+
+	s.sorted = slices.Sorted(slices.Values(values))
+	s.Count = len(s.sorted)
+	s.Min = s.sorted[0]
+	s.Max = s.sorted[s.Count-1]
+
+	for _, v := range s.sorted {
+		s.Sum += v
+	}
+	s.Mean = s.Sum / float64(s.Count)
+	s.Median = s.Percentile(50)
+
+	var squares float64
+	for _, v := range s.sorted {
+		squares += (v - s.Mean) * (v - s.Mean)
+	}
+	s.StdDev = math.Sqrt(squares / float64(s.Count))
+
+	return s
+}
+
+// Stats summarizes the values of sr.
+func (sr SingleRange) Stats(opts ...StatsOption) Stats {
+	var (
+		c      = newStatsConfig(opts)
+		dates  = make([]time.Time, 0, len(sr))
+		values = make([]float64, 0, len(sr))
+	)
+
+	for _, s := range sr {
+		if !c.within(s.Date) {
+			continue
+		}
+
+		dates = append(dates, s.Date)
+		if s.HasData() {
+			values = append(values, s.Value)
+		}
+	}
+
+	return c.summarize(values, c.calendarDays(dates))
+}
+
+// MacrosStats summarizes each macro of a [MacrosRange].
+type MacrosStats struct {
+	Energy  Stats
+	Carb    Stats
+	Fat     Stats
+	Protein Stats
+}
+
+// Series returns the values of the macro k ([Energy], [Carb],
+// [Fat] or [Protein]) as a [SingleRange]. Other kinds are
+// not part of [Macros] and result in nil.
+func (mr MacrosRange) Series(k Kind) SingleRange {
+	var value func(Macros) float64
+
+	switch k {
+	case Energy:
+		value = func(m Macros) float64 { return m.Energy }
+	case Carb:
+		value = func(m Macros) float64 { return m.Carb }
+	case Fat:
+		value = func(m Macros) float64 { return m.Fat }
+	case Protein:
+		value = func(m Macros) float64 { return m.Protein }
+	default:
+		return nil
+	}
+
+	sr := make(SingleRange, len(mr))
+	for i, m := range mr {
+		sr[i] = Single{Kind: k, Date: m.Date, Value: value(m)}
+	}
+
+	return sr
+}
+
+// Stats summarizes each macro of mr.
+func (mr MacrosRange) Stats(opts ...StatsOption) MacrosStats {
+	var (
+		c     = newStatsConfig(opts)
+		days  = make(MacrosRange, 0, len(mr))
+		dates = make([]time.Time, 0, len(mr))
+	)
+
+	for _, m := range mr {
+		if c.within(m.Date) {
+			days = append(days, m)
+			dates = append(dates, m.Date)
+		}
+	}
+
+	if c.excludeZero { // by day, not by macro
+		days = slices.DeleteFunc(days, func(m Macros) bool {
+			return m.Energy == 0 && m.Carb == 0 && m.Fat == 0 && m.Protein == 0
+		})
+		c.excludeZero = false
+	}
+
+	var (
+		calendarDays = c.calendarDays(dates)
+		summarize    = func(k Kind) Stats {
+			sr := days.Series(k)
+			values := make([]float64, len(sr))
+			for i, s := range sr {
+				values[i] = s.Value
+			}
+			return c.summarize(values, calendarDays)
+		}
+	)
+
+	return MacrosStats{
+		Energy:  summarize(Energy),
+		Carb:    summarize(Carb),
+		Fat:     summarize(Fat),
+		Protein: summarize(Protein),
+	}
+}
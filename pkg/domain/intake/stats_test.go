@@ -0,0 +1,166 @@
+package intake
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/domain/date"
+)
+
+func assertNear(t *testing.T, got, want float64) {
+	t.Helper()
+
+	if math.Abs(got-want) > 1e-9 {
+		t.Fatalf("\ngot %v\nwant %v", got, want)
+	}
+}
+
+func TestSingleRange_Stats(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day = func(d int) time.Time {
+			return time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC)
+		}
+		sr = SingleRange{
+			{Kind: Water, Date: day(1), Value: 1000},
+			{Kind: Water, Date: day(2), Value: 0},
+			{Kind: Water, Date: day(3), Gap: true},
+			{Kind: Water, Date: day(4), Value: 3000},
+			{Kind: Water, Date: day(5), Value: 2000},
+		}
+	)
+
+	testBlocks := []struct {
+		name       string
+		opts       []StatsOption
+		wantLogged int
+		wantDays   int
+		wantCount  int
+		wantMean   float64
+		wantMedian float64
+		wantMin    float64
+	}{
+		{
+			name:       "default",
+			wantLogged: 4, wantDays: 5, wantCount: 4,
+			wantMean: 1500, wantMedian: 1500, wantMin: 0,
+		},
+		{
+			name:       "exclude zero",
+			opts:       []StatsOption{ExcludeZero()},
+			wantLogged: 3, wantDays: 5, wantCount: 3,
+			wantMean: 2000, wantMedian: 2000, wantMin: 1000,
+		},
+		{
+			name:       "include unlogged over a wider range",
+			opts:       []StatsOption{IncludeUnlogged(), Over(date.Range{Start: day(1), End: day(10)})},
+			wantLogged: 4, wantDays: 10, wantCount: 10,
+			wantMean: 600, wantMedian: 0, wantMin: 0,
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := sr.Stats(tb.opts...)
+			assert.Equal(t, got.LoggedDays, tb.wantLogged)
+			assert.Equal(t, got.CalendarDays, tb.wantDays)
+			assert.Equal(t, got.Count, tb.wantCount)
+			assert.Equal(t, got.Sum, 6000.0)
+			assert.Equal(t, got.Max, 3000.0)
+			assert.Equal(t, got.Min, tb.wantMin)
+			assertNear(t, got.Mean, tb.wantMean)
+			assertNear(t, got.Median, tb.wantMedian)
+		})
+	}
+}
+
+func TestStats_Percentile(t *testing.T) {
+	t.Parallel()
+
+	today := time.Now()
+	s := SingleRange{
+		{Kind: Energy, Date: today, Value: 10},
+		{Kind: Energy, Date: today.AddDate(0, 0, 1), Value: 20},
+		{Kind: Energy, Date: today.AddDate(0, 0, 2), Value: 30},
+		{Kind: Energy, Date: today.AddDate(0, 0, 3), Value: 40},
+	}.Stats()
+
+	assertNear(t, s.Percentile(0), 10)
+	assertNear(t, s.Percentile(25), 17.5)
+	assertNear(t, s.Percentile(50), 25)
+	assertNear(t, s.Percentile(100), 40)
+	assertNear(t, s.Percentile(150), 40)
+	assertNear(t, s.StdDev, math.Sqrt(125))
+	assertNear(t, s.Coverage(), 1)
+
+	assert.Equal(t, Stats{}.Percentile(50), 0.0)
+}
+
+func TestMacrosRange_Stats(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day = func(d int) time.Time {
+			return time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC)
+		}
+		mr = MacrosRange{
+			{day(1), 2000, 200, 80, 120},
+			{day(2), 0, 0, 0, 0},
+			{day(4), 1800, 180, 0, 100}, // zero fat is still a logged day
+		}
+	)
+
+	got := mr.Stats()
+	assert.Equal(t, got.Energy.CalendarDays, 4)
+	assert.Equal(t, got.Energy.LoggedDays, 3)
+	assertNear(t, got.Energy.Mean, 3800.0/3)
+
+	got = mr.Stats(ExcludeZero())
+	assert.Equal(t, got.Energy.LoggedDays, 2)
+	assert.Equal(t, got.Energy.Count, 2)
+	assertNear(t, got.Energy.Mean, 1900)
+	assert.Equal(t, got.Fat.Count, 2)
+	assertNear(t, got.Fat.Min, 0)
+	assertNear(t, got.Protein.Median, 110)
+
+	assert.Equal(t, len(mr.Series(Water)), 0)
+}
+
+func TestStats_Over(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day = func(d int) time.Time {
+			return time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC)
+		}
+		r  = date.Range{Start: day(2), End: day(3)}
+		sr = SingleRange{
+			{Kind: Water, Date: day(1), Value: 1000},
+			{Kind: Water, Date: day(2), Value: 2000},
+			{Kind: Water, Date: day(3).Add(20 * time.Hour), Value: 3000},
+			{Kind: Water, Date: day(4), Value: 4000},
+		}
+		mr = MacrosRange{
+			{day(1), 2000, 200, 80, 120},
+			{day(2), 1800, 180, 60, 100},
+			{day(5), 2200, 220, 90, 130},
+		}
+	)
+
+	s := sr.Stats(Over(r))
+	assert.Equal(t, s.LoggedDays, 2)
+	assert.Equal(t, s.CalendarDays, 2)
+	assert.Equal(t, s.Sum, 5000.0)
+	assertNear(t, s.Coverage(), 1)
+
+	ms := mr.Stats(Over(r))
+	assert.Equal(t, ms.Energy.LoggedDays, 1)
+	assert.Equal(t, ms.Energy.CalendarDays, 2)
+	assert.Equal(t, ms.Energy.Sum, 1800.0)
+	assertNear(t, ms.Energy.Coverage(), 0.5)
+}
@@ -0,0 +1,217 @@
+package intake
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/controlado/go-yazio/pkg/domain/date"
+)
+
+// dataPoints returns the days of sr holding
+// data, sorted by date.
+func (sr SingleRange) dataPoints() SingleRange {
+	points := slices.DeleteFunc(slices.Clone(sr), func(s Single) bool {
+		return !s.HasData()
+	})
+	points.Sort()
+	return points
+}
+
+// SMA returns the simple moving average of sr: each day averages
+// its value and the previous window-1 days holding data.
+//
+// The first window-1 days (without a full window) are left out,
+// as well as days without data. Windows below 1 are treated as 1.
+func (sr SingleRange) SMA(window int) SingleRange {
+	var (
+		out    SingleRange
+		sum    float64
+		points = sr.dataPoints()
+	)
+
+	window = max(window, 1)
+
+	for i, s := range points {
+		sum += s.Value
+		if i >= window {
+			sum -= points[i-window].Value
+		}
+		if i >= window-1 {
+			out = append(out, Single{Kind: s.Kind, Date: s.Date, Value: sum / float64(window)})
+		}
+	}
+
+	return out
+}
+
+// EMA returns the exponential moving average of sr, weighting
+// recent days with alpha = 2 / (span + 1) and seeded by the first
+// day holding data. Spans below 1 are treated as 1.
+func (sr SingleRange) EMA(span int) SingleRange {
+	var (
+		out    SingleRange
+		ema    float64
+		alpha  = 2 / (float64(max(span, 1)) + 1)
+		points = sr.dataPoints()
+	)
+
+	for i, s := range points {
+		if i == 0 {
+			ema = s.Value
+		} else {
+			ema = alpha*s.Value + (1-alpha)*ema
+		}
+		out = append(out, Single{Kind: s.Kind, Date: s.Date, Value: ema})
+	}
+
+	return out
+}
+
+// Trend is the least-squares line fitted
+// to the daily values of a series.
+type Trend struct {
+	Kind      Kind
+	Days      int     // Days is how many days holding data were fitted.
+	Slope     float64 // Slope is the change per day.
+	Intercept float64 // Intercept is the fitted value on the first day.
+	R2        float64 // R2 is the coefficient of determination (0 to 1).
+}
+
+// PerWeek returns the change per week.
+func (t Trend) PerWeek() float64 {
+	return t.Slope * 7
+}
+
+// Direction returns "up", "down" or "stable".
+func (t Trend) Direction() string {
+	switch perWeek := t.PerWeek(); {
+	case perWeek > 1e-9:
+		return "up"
+	case perWeek < -1e-9:
+		return "down"
+	default:
+		return "stable"
+	}
+}
+
+func (t Trend) String() string {
+	if t.Days < 2 {
+		return "Not enough data to calculate the trend"
+	}
+
+	if t.Direction() == "stable" {
+		return fmt.Sprintf("%s/day stable (%d days)", t.Kind.baseUnit, t.Days)
+	}
+
+	return fmt.Sprintf("%s/day trending %s by %.1f%s per week (%d days, R² %.2f)",
+		t.Kind.baseUnit,
+		t.Direction(),
+		math.Abs(t.PerWeek()),
+		t.Kind.baseUnit,
+		t.Days,
+		t.R2,
+	)
+}
+
+// Trend fits a line to the days of sr holding data, using
+// the days elapsed since the first one as x.
+func (sr SingleRange) Trend() Trend {
+	points := sr.dataPoints()
+
+	t := Trend{Days: len(points)}
+	if t.Days == 0 {
+		return t
+	}
+	t.Kind = points[0].Kind
+
+	xs := make([]float64, len(points))
+	for i, s := range points {
+		elapsed := date.Range{Start: points[0].Date, End: s.Date}
+		xs[i] = float64(elapsed.Days() - 1)
+	}
+
+	var meanX, meanY float64
+	for i, s := range points {
+		meanX += xs[i]
+		meanY += s.Value
+	}
+	meanX /= float64(t.Days)
+	meanY /= float64(t.Days)
+
+	var covXY, varX, varY float64
+	for i, s := range points {
+		dx, dy := xs[i]-meanX, s.Value-meanY
+		covXY += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+
+	if varX == 0 { // single day
+		t.Intercept = meanY
+		return t
+	}
+
+	t.Slope = covXY / varX
+	t.Intercept = meanY - t.Slope*meanX
+
+	if varY > 0 {
+		t.R2 = covXY * covXY / (varX * varY)
+	}
+
+	return t
+}
+
+// MacrosTrend holds the [Trend] of each macro.
+type MacrosTrend struct {
+	Energy  Trend
+	Carb    Trend
+	Fat     Trend
+	Protein Trend
+}
+
+// SMA returns the simple moving average of each macro of mr,
+// see [SingleRange.SMA].
+func (mr MacrosRange) SMA(window int) MacrosRange {
+	return mr.smooth(func(sr SingleRange) SingleRange { return sr.SMA(window) })
+}
+
+// EMA returns the exponential moving average of each macro of mr,
+// see [SingleRange.EMA].
+func (mr MacrosRange) EMA(span int) MacrosRange {
+	return mr.smooth(func(sr SingleRange) SingleRange { return sr.EMA(span) })
+}
+
+// Trend fits a line to each macro of mr, see [SingleRange.Trend].
+func (mr MacrosRange) Trend() MacrosTrend {
+	return MacrosTrend{
+		Energy:  mr.Series(Energy).Trend(),
+		Carb:    mr.Series(Carb).Trend(),
+		Fat:     mr.Series(Fat).Trend(),
+		Protein: mr.Series(Protein).Trend(),
+	}
+}
+
+// smooth applies fn to each macro series, which all
+// share the same days, and rebuilds the range.
+func (mr MacrosRange) smooth(fn func(SingleRange) SingleRange) MacrosRange {
+	var (
+		energy  = fn(mr.Series(Energy))
+		carb    = fn(mr.Series(Carb))
+		fat     = fn(mr.Series(Fat))
+		protein = fn(mr.Series(Protein))
+		out     = make(MacrosRange, len(energy))
+	)
+
+	for i := range energy {
+		out[i] = Macros{
+			Date:    energy[i].Date,
+			Energy:  energy[i].Value,
+			Carb:    carb[i].Value,
+			Fat:     fat[i].Value,
+			Protein: protein[i].Value,
+		}
+	}
+
+	return out
+}
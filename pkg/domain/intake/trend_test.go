@@ -0,0 +1,143 @@
+package intake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+)
+
+func TestSingleRange_SMA(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day = func(d int) time.Time {
+			return time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC)
+		}
+		sr = SingleRange{
+			{Kind: Energy, Date: day(4), Value: 4},
+			{Kind: Energy, Date: day(1), Value: 1},
+			{Kind: Energy, Date: day(2), Value: 2},
+			{Kind: Energy, Date: day(3), Gap: true},
+			{Kind: Energy, Date: day(5), Value: 6},
+		}
+	)
+
+	got := sr.SMA(2)
+	assert.DeepEqual(t, got, SingleRange{
+		{Kind: Energy, Date: day(2), Value: 1.5},
+		{Kind: Energy, Date: day(4), Value: 3},
+		{Kind: Energy, Date: day(5), Value: 5},
+	})
+
+	assert.Equal(t, len(sr.SMA(10)), 0)
+	assert.Equal(t, len(sr.SMA(0)), 4)
+}
+
+func TestSingleRange_EMA(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day = func(d int) time.Time {
+			return time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC)
+		}
+		sr = SingleRange{
+			{Kind: Energy, Date: day(1), Value: 10},
+			{Kind: Energy, Date: day(2), Value: 20},
+			{Kind: Energy, Date: day(3), Value: 20},
+		}
+	)
+
+	got := sr.EMA(3) // alpha: 0.5
+	assert.DeepEqual(t, got, SingleRange{
+		{Kind: Energy, Date: day(1), Value: 10},
+		{Kind: Energy, Date: day(2), Value: 15},
+		{Kind: Energy, Date: day(3), Value: 17.5},
+	})
+}
+
+func TestSingleRange_Trend(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day = func(d int) time.Time {
+			return time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC)
+		}
+	)
+
+	testBlocks := []struct {
+		name       string
+		sr         SingleRange
+		wantSlope  float64
+		wantString string
+	}{
+		{
+			name: "trending up",
+			sr: SingleRange{
+				{Kind: Energy, Date: day(1), Value: 2000},
+				{Kind: Energy, Date: day(3), Value: 2020},
+				{Kind: Energy, Date: day(8), Value: 2070},
+			},
+			wantSlope:  10,
+			wantString: "kcal/day trending up by 70.0kcal per week (3 days, R² 1.00)",
+		},
+		{
+			name: "trending down",
+			sr: SingleRange{
+				{Kind: Water, Date: day(1), Value: 3000},
+				{Kind: Water, Date: day(2), Value: 2900},
+			},
+			wantSlope:  -100,
+			wantString: "ml/day trending down by 700.0ml per week (2 days, R² 1.00)",
+		},
+		{
+			name: "stable",
+			sr: SingleRange{
+				{Kind: Water, Date: day(1), Value: 3000},
+				{Kind: Water, Date: day(2), Value: 3000},
+			},
+			wantSlope:  0,
+			wantString: "ml/day stable (2 days)",
+		},
+		{
+			name:       "not enough data",
+			sr:         SingleRange{{Kind: Water, Date: day(1), Value: 3000}},
+			wantString: "Not enough data to calculate the trend",
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+			got := tb.sr.Trend()
+			assertNear(t, got.Slope, tb.wantSlope)
+			assert.Equal(t, got.String(), tb.wantString)
+		})
+	}
+}
+
+func TestMacrosRange_Smoothing(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day = func(d int) time.Time {
+			return time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC)
+		}
+		mr = MacrosRange{
+			{day(1), 2000, 200, 60, 100},
+			{day(2), 2200, 220, 80, 120},
+			{day(3), 2400, 240, 100, 140},
+		}
+	)
+
+	assert.DeepEqual(t, mr.SMA(2), MacrosRange{
+		{day(2), 2100, 210, 70, 110},
+		{day(3), 2300, 230, 90, 130},
+	})
+	assert.Equal(t, len(mr.EMA(2)), 3)
+
+	trend := mr.Trend()
+	assertNear(t, trend.Energy.Slope, 200)
+	assertNear(t, trend.Fat.PerWeek(), 140)
+	assert.Equal(t, trend.Protein.Kind, Protein)
+}
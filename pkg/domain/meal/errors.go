@@ -0,0 +1,7 @@
+package meal
+
+import "errors"
+
+var (
+	ErrUnknownTime = errors.New("given meal time is unknown")
+)
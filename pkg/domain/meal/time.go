@@ -1,5 +1,10 @@
 package meal
 
+import (
+	"fmt"
+	"strings"
+)
+
 const (
 	Breakfast Time = "breakfast"
 	Lunch     Time = "lunch"
@@ -13,3 +18,23 @@ type Time string
 func (t Time) String() string {
 	return string(t)
 }
+
+// Times returns every meal [Time], in the order of a day.
+func Times() []Time {
+	return []Time{Breakfast, Lunch, Dinner, Snack}
+}
+
+// Parse resolves the meal [Time] named s,
+// ignoring case and surrounding spaces.
+//
+// On failure the error wraps either:
+//   - [ErrUnknownTime]
+func Parse(s string) (Time, error) {
+	t := Time(strings.ToLower(strings.TrimSpace(s)))
+	switch t {
+	case Breakfast, Lunch, Dinner, Snack:
+		return t, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownTime, s)
+	}
+}
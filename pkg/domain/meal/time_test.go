@@ -1,6 +1,7 @@
 package meal
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/controlado/go-yazio/internal/testutil/assert"
@@ -28,3 +29,36 @@ func TestMeal_String(t *testing.T) {
 		})
 	}
 }
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name    string
+		input   string
+		want    Time
+		wantErr bool
+	}{
+		{name: "exact", input: "lunch", want: Lunch},
+		{name: "case and spaces", input: " Breakfast ", want: Breakfast},
+		{name: "plural", input: "snacks", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tb.input)
+			if tb.wantErr {
+				if !errors.Is(err, ErrUnknownTime) {
+					t.Fatalf("got %v, want %v", err, ErrUnknownTime)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, got, tb.want)
+		})
+	}
+}
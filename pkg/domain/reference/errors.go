@@ -0,0 +1,8 @@
+package reference
+
+import "errors"
+
+var (
+	ErrUnknownSex     = errors.New("given sex is unknown")
+	ErrUnsupportedAge = errors.New("no reference values for the given age")
+)
@@ -0,0 +1,100 @@
+package reference
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/user"
+)
+
+const (
+	Female Sex = "female"
+	Male   Sex = "male"
+)
+
+// MinAge is the youngest age (in years) covered
+// by the embedded reference values.
+const MinAge = 14
+
+// Sex selects the reference values, which differ
+// between females and males.
+type Sex string
+
+func (s Sex) String() string {
+	return string(s)
+}
+
+func (s Sex) valid() bool {
+	return s == Female || s == Male
+}
+
+// Profile holds what the reference values depend on.
+type Profile struct {
+	Sex   Sex
+	Birth time.Time
+}
+
+// ProfileOf returns the [Profile] of a YAZIO user.
+//
+// YAZIO doesn't expose the sex of the user
+// through [user.Data], so it must be given.
+func ProfileOf(d user.Data, s Sex) Profile {
+	return Profile{Sex: s, Birth: d.Birth}
+}
+
+// Age returns the age of p, in completed years, at t.
+func (p Profile) Age(at time.Time) int {
+	var (
+		by, bm, bd = p.Birth.Date()
+		ty, tm, td = at.In(p.Birth.Location()).Date()
+		age        = ty - by
+	)
+
+	if tm < bm || (tm == bm && td < bd) {
+		age--
+	}
+
+	return age
+}
+
+// Value is the reference daily intake of a [intake.Kind],
+// in the base unit of the kind.
+type Value struct {
+	Kind        intake.Kind
+	Recommended float64 // Recommended is the RDA, or the AI when no RDA exists.
+	Upper       float64 // Upper is the tolerable upper intake level, zero when not set.
+}
+
+// HasUpper reports whether v sets an upper intake level.
+func (v Value) HasUpper() bool {
+	return v.Upper > 0
+}
+
+// Values maps each covered kind to its reference [Value].
+type Values map[intake.Kind]Value
+
+// For returns the reference values that apply to p at t.
+//
+// On failure the error wraps either:
+//   - [ErrUnknownSex]
+//   - [ErrUnsupportedAge]
+func For(p Profile, at time.Time) (Values, error) {
+	if !p.Sex.valid() {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSex, p.Sex)
+	}
+
+	age := p.Age(at)
+	if age < MinAge {
+		return nil, fmt.Errorf("%w: %d years", ErrUnsupportedAge, age)
+	}
+
+	out := make(Values)
+	for _, r := range table {
+		if r.sex == p.Sex && r.covers(age) {
+			out[r.value.Kind] = r.value
+		}
+	}
+
+	return out, nil
+}
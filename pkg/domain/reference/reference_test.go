@@ -0,0 +1,209 @@
+package reference
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/user"
+)
+
+func TestProfile_Age(t *testing.T) {
+	t.Parallel()
+
+	p := Profile{Birth: time.Date(1995, 8, 26, 0, 0, 0, 0, time.UTC)}
+
+	testBlocks := []struct {
+		name string
+		at   time.Time
+		want int
+	}{
+		{"day before birthday", time.Date(2025, 8, 25, 23, 0, 0, 0, time.UTC), 29},
+		{"on birthday", time.Date(2025, 8, 26, 0, 0, 0, 0, time.UTC), 30},
+		{"later in the year", time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), 30},
+		{"earlier month", time.Date(2025, 2, 27, 0, 0, 0, 0, time.UTC), 29},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, p.Age(tb.at), tb.want)
+		})
+	}
+}
+
+func TestFor(t *testing.T) {
+	t.Parallel()
+
+	var (
+		at    = time.Date(2025, 4, 12, 0, 0, 0, 0, time.UTC)
+		birth = func(age int) time.Time { return at.AddDate(-age, 0, -1) }
+	)
+
+	testBlocks := []struct {
+		name        string
+		profile     Profile
+		kind        intake.Kind
+		recommended float64
+		upper       float64
+		wantErr     error
+	}{
+		{
+			name:        "adult female iron",
+			profile:     Profile{Female, birth(30)},
+			kind:        intake.Iron,
+			recommended: 18,
+			upper:       45,
+		},
+		{
+			name:        "older female iron",
+			profile:     Profile{Female, birth(55)},
+			kind:        intake.Iron,
+			recommended: 8,
+			upper:       45,
+		},
+		{
+			name:        "teen male vitamin c",
+			profile:     Profile{Male, birth(16)},
+			kind:        intake.VitaminC,
+			recommended: 75,
+			upper:       1800,
+		},
+		{
+			name:        "elderly vitamin d",
+			profile:     Profile{Male, birth(80)},
+			kind:        intake.VitaminD,
+			recommended: 20,
+			upper:       100,
+		},
+		{
+			name:        "no upper level",
+			profile:     Profile{Male, birth(40)},
+			kind:        intake.Potassium,
+			recommended: 3400,
+		},
+		{
+			name:        "supplement-only upper level of niacin",
+			profile:     Profile{Female, birth(30)},
+			kind:        intake.VitaminB3,
+			recommended: 14,
+		},
+		{
+			name:        "supplement-only upper level of folate",
+			profile:     Profile{Male, birth(40)},
+			kind:        intake.VitaminB11,
+			recommended: 400,
+		},
+		{
+			name:    "child",
+			profile: Profile{Female, birth(10)},
+			wantErr: ErrUnsupportedAge,
+		},
+		{
+			name:    "unknown sex",
+			profile: Profile{"other", birth(30)},
+			wantErr: ErrUnknownSex,
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			values, err := For(tb.profile, at)
+			if tb.wantErr != nil {
+				if !errors.Is(err, tb.wantErr) {
+					t.Fatalf("got %v, want %v", err, tb.wantErr)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			v, ok := values[tb.kind]
+			assert.Equal(t, ok, true)
+			assert.Equal(t, v.Kind, tb.kind)
+			assert.Equal(t, v.Recommended, tb.recommended)
+			assert.Equal(t, v.Upper, tb.upper)
+			assert.Equal(t, v.HasUpper(), tb.upper > 0)
+		})
+	}
+}
+
+func TestFor_CoversEveryAge(t *testing.T) {
+	t.Parallel()
+
+	var (
+		at   = time.Date(2025, 4, 12, 0, 0, 0, 0, time.UTC)
+		want int
+	)
+
+	for age := MinAge; age <= 100; age++ {
+		for _, s := range []Sex{Female, Male} {
+			values, err := For(Profile{s, at.AddDate(-age, 0, 0)}, at)
+			assert.NoError(t, err)
+
+			if want == 0 {
+				want = len(values)
+			}
+			assert.Equal(t, len(values), want)
+		}
+	}
+}
+
+func TestProfileOf(t *testing.T) {
+	t.Parallel()
+
+	var (
+		birth = time.Date(1995, 8, 26, 0, 0, 0, 0, time.UTC)
+		d     = user.Data{FirstName: "João", Birth: birth}
+	)
+
+	assert.Equal(t, ProfileOf(d, Male), Profile{Male, birth})
+}
+
+func TestParseTable(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{
+			name:  "valid",
+			input: "kind,sex,min_age,max_age,recommended,upper\nvitamin.c,male,19,0,90,2000\n",
+		},
+		{
+			name:    "unknown kind",
+			input:   "kind,sex,min_age,max_age,recommended,upper\nvitamin.z,male,19,0,90,\n",
+			wantErr: intake.ErrUnknownKind,
+		},
+		{
+			name:    "unknown sex",
+			input:   "kind,sex,min_age,max_age,recommended,upper\nvitamin.c,other,19,0,90,\n",
+			wantErr: ErrUnknownSex,
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			rows, err := parseTable(strings.NewReader(tb.input))
+			if tb.wantErr != nil {
+				if !errors.Is(err, tb.wantErr) {
+					t.Fatalf("got %v, want %v", err, tb.wantErr)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, len(rows), 1)
+			assert.Equal(t, rows[0].covers(99), true)
+			assert.Equal(t, rows[0].covers(18), false)
+		})
+	}
+}
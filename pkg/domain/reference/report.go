@@ -0,0 +1,171 @@
+package reference
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+)
+
+const (
+	Unlogged  Status = iota // Unlogged means the series holds no data.
+	Deficient               // Deficient means the average is below the deficiency threshold.
+	Adequate                // Adequate means the average meets the threshold, within the upper level.
+	Excessive               // Excessive means the average exceeds the upper intake level.
+)
+
+// defaultDeficientBelow is the share of the recommended
+// intake under which an average is deficient.
+const defaultDeficientBelow = 0.7
+
+// Status classifies an average against its reference [Value].
+type Status int
+
+func (s Status) String() string {
+	switch s {
+	case Deficient:
+		return "deficient"
+	case Adequate:
+		return "adequate"
+	case Excessive:
+		return "excessive"
+	default:
+		return "unlogged"
+	}
+}
+
+// Item compares the average of a series with its reference value.
+type Item struct {
+	Reference Value
+	Days      int     // Days is how many days hold data.
+	Average   float64 // Average is the daily average, in the base unit of the kind.
+	Percent   float64 // Percent is Average as a percentage of the recommended intake.
+	Status    Status
+}
+
+func (i Item) String() string {
+	k := i.Reference.Kind
+	if i.Status == Unlogged {
+		return fmt.Sprintf("%s: no data", k)
+	}
+
+	return fmt.Sprintf("%s: %.1f%s/day, %.0f%% of %g%s (%s)",
+		k,
+		i.Average,
+		k.Unit(),
+		i.Percent,
+		i.Reference.Recommended,
+		k.Unit(),
+		i.Status,
+	)
+}
+
+// Report compares intake averages with the
+// reference values that apply to a [Profile].
+type Report struct {
+	Profile   Profile
+	Age       int           // Age is the age of the profile when the report was made.
+	Items     []Item        // Items are in the order of the given series.
+	Uncovered []intake.Kind // Uncovered are the given kinds without reference values.
+}
+
+type reportConfig struct {
+	deficientBelow float64
+}
+
+type ReportOption func(c *reportConfig)
+
+// DeficientBelow sets the share (0 to 1) of the recommended intake
+// under which an average is [Deficient]. It defaults to 0.7.
+func DeficientBelow(share float64) ReportOption {
+	return func(c *reportConfig) {
+		if share > 0 {
+			c.deficientBelow = share
+		}
+	}
+}
+
+// NewReport compares the average of each series with the
+// reference values that apply to p at t (see [For]).
+//
+// Empty series are skipped, since their kind is unknown.
+// A [intake.Matrix] can be reported through its Series.
+//
+// On failure the error wraps either:
+//   - [ErrUnknownSex]
+//   - [ErrUnsupportedAge]
+func NewReport(p Profile, at time.Time, series []intake.SingleRange, opts ...ReportOption) (Report, error) {
+	values, err := For(p, at)
+	if err != nil {
+		return Report{}, err
+	}
+
+	c := reportConfig{deficientBelow: defaultDeficientBelow}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	r := Report{Profile: p, Age: p.Age(at)}
+
+	for _, sr := range series {
+		if len(sr) == 0 {
+			continue
+		}
+
+		k := sr[0].Kind
+		v, ok := values[k]
+		if !ok {
+			r.Uncovered = append(r.Uncovered, k)
+			continue
+		}
+
+		r.Items = append(r.Items, c.compare(v, sr.Average()))
+	}
+
+	return r, nil
+}
+
+func (c reportConfig) compare(v Value, avg intake.SingleAverage) Item {
+	item := Item{
+		Reference: v,
+		Days:      avg.DaysLength,
+		Average:   avg.Average,
+	}
+
+	if item.Days == 0 {
+		return item
+	}
+
+	item.Percent = item.Average / v.Recommended * 100
+
+	switch {
+	case v.HasUpper() && item.Average > v.Upper:
+		item.Status = Excessive
+	case item.Percent < c.deficientBelow*100:
+		item.Status = Deficient
+	default:
+		item.Status = Adequate
+	}
+
+	return item
+}
+
+// Deficiencies returns the items of r that are [Deficient].
+func (r Report) Deficiencies() []Item {
+	return r.filter(Deficient)
+}
+
+// Exceedances returns the items of r that are [Excessive].
+func (r Report) Exceedances() []Item {
+	return r.filter(Excessive)
+}
+
+func (r Report) filter(s Status) []Item {
+	var out []Item
+	for _, item := range r.Items {
+		if item.Status == s {
+			out = append(out, item)
+		}
+	}
+	return out
+}
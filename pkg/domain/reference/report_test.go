@@ -0,0 +1,81 @@
+package reference
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+)
+
+func TestNewReport(t *testing.T) {
+	t.Parallel()
+
+	var (
+		at       = time.Date(2025, 4, 12, 0, 0, 0, 0, time.UTC)
+		firstDay = at.AddDate(0, 0, -1)
+		profile  = Profile{Female, time.Date(1995, 8, 26, 0, 0, 0, 0, time.UTC)}
+
+		series = []intake.SingleRange{
+			{ // 45mg/day: 60% of 75mg
+				{Kind: intake.VitaminC, Date: firstDay, Value: 40},
+				{Kind: intake.VitaminC, Date: at, Value: 50},
+			},
+			{ // 90mg/day: over 45mg
+				{Kind: intake.Iron, Date: firstDay, Value: 90},
+			},
+			{ // 1000mg/day: 100%
+				{Kind: intake.Calcium, Date: firstDay, Value: 1000},
+				{Kind: intake.Calcium, Date: at, Gap: true},
+			},
+			{
+				{Kind: intake.VitaminD, Date: firstDay, Gap: true},
+			},
+			{
+				{Kind: intake.Water, Date: firstDay, Value: 2000},
+			},
+			nil,
+		}
+	)
+
+	r, err := NewReport(profile, at, series)
+	assert.NoError(t, err)
+	assert.Equal(t, r.Age, 29)
+	assert.DeepEqual(t, r.Uncovered, []intake.Kind{intake.Water})
+	assert.Equal(t, len(r.Items), 4)
+
+	testBlocks := []struct {
+		kind    intake.Kind
+		status  Status
+		percent float64
+		days    int
+	}{
+		{intake.VitaminC, Deficient, 60, 2},
+		{intake.Iron, Excessive, 500, 1},
+		{intake.Calcium, Adequate, 100, 1},
+		{intake.VitaminD, Unlogged, 0, 0},
+	}
+
+	for i, tb := range testBlocks {
+		item := r.Items[i]
+		assert.Equal(t, item.Reference.Kind, tb.kind)
+		assert.Equal(t, item.Status, tb.status)
+		assert.Equal(t, item.Percent, tb.percent)
+		assert.Equal(t, item.Days, tb.days)
+	}
+
+	assert.Equal(t, len(r.Deficiencies()), 1)
+	assert.Equal(t, len(r.Exceedances()), 1)
+	assert.Equal(t, r.Items[0].String(), "vitamin.c: 45.0mg/day, 60% of 75mg (deficient)")
+	assert.Equal(t, r.Items[3].String(), "vitamin.d: no data")
+
+	lenient, err := NewReport(profile, at, series, DeficientBelow(0.5))
+	assert.NoError(t, err)
+	assert.Equal(t, lenient.Items[0].Status, Adequate)
+
+	_, err = NewReport(Profile{Female, at.AddDate(-5, 0, 0)}, at, series)
+	if !errors.Is(err, ErrUnsupportedAge) {
+		t.Fatalf("got %v, want %v", err, ErrUnsupportedAge)
+	}
+}
@@ -0,0 +1,98 @@
+package reference
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+)
+
+// values.csv holds the Dietary Reference Intakes published
+// by the US National Academies, by sex and age band.
+//
+//go:embed values.csv
+var valuesCSV string
+
+var table = mustParseTable(valuesCSV)
+
+type row struct {
+	sex    Sex
+	minAge int
+	maxAge int // zero: no upper bound
+	value  Value
+}
+
+func (r row) covers(age int) bool {
+	return age >= r.minAge && (r.maxAge == 0 || age <= r.maxAge)
+}
+
+func mustParseTable(s string) []row {
+	rows, err := parseTable(strings.NewReader(s))
+	if err != nil {
+		panic(fmt.Sprintf("reference: parsing embedded values: %v", err))
+	}
+	return rows
+}
+
+// parseTable reads rows in the format:
+//
+//	kind,sex,min_age,max_age,recommended,upper
+func parseTable(r io.Reader) ([]row, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = 6
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []row
+	for i, rec := range records {
+		if i == 0 { // header
+			continue
+		}
+
+		parsed, err := parseRow(rec)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", i, err)
+		}
+		out = append(out, parsed)
+	}
+
+	return out, nil
+}
+
+func parseRow(rec []string) (row, error) {
+	var (
+		r   = row{sex: Sex(rec[1])}
+		err error
+	)
+
+	if r.value.Kind, err = intake.KindByID(rec[0]); err != nil {
+		return row{}, err
+	}
+	if !r.sex.valid() {
+		return row{}, fmt.Errorf("%w: %q", ErrUnknownSex, rec[1])
+	}
+	if r.minAge, err = strconv.Atoi(rec[2]); err != nil {
+		return row{}, err
+	}
+	if r.maxAge, err = strconv.Atoi(rec[3]); err != nil {
+		return row{}, err
+	}
+	if r.value.Recommended, err = strconv.ParseFloat(rec[4], 64); err != nil {
+		return row{}, err
+	}
+	if rec[5] != "" {
+		if r.value.Upper, err = strconv.ParseFloat(rec[5], 64); err != nil {
+			return row{}, err
+		}
+	}
+
+	return r, nil
+}
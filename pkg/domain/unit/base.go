@@ -2,10 +2,15 @@ package unit
 
 const (
 	Kilocalorie Base = "kcal"
+	Kilojoule   Base = "kJ"
 	Milliliter  Base = "ml"
+	Liter       Base = "l"
+	FluidOunce  Base = "fl oz" // FluidOunce is the US fluid ounce.
 	Gram        Base = "g"
 	Milligram   Base = "mg"
 	Microgram   Base = "mcg"
+	Ounce       Base = "oz"
+	Pound       Base = "lb"
 )
 
 // Base represents the food unit of measurement.
@@ -39,6 +39,16 @@ func TestBase_String(t *testing.T) {
 			b:    Microgram,
 			want: "mcg",
 		},
+		{
+			name: "kilojoule",
+			b:    Kilojoule,
+			want: "kJ",
+		},
+		{
+			name: "fluid ounce",
+			b:    FluidOunce,
+			want: "fl oz",
+		},
 	}
 
 	for _, tb := range testBlocks {
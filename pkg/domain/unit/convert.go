@@ -0,0 +1,84 @@
+package unit
+
+import "fmt"
+
+const (
+	UnknownDimension Dimension = ""
+	Mass             Dimension = "mass"
+	Volume           Dimension = "volume"
+	Energy           Dimension = "energy"
+)
+
+// Dimension is the physical quantity measured by a [Base].
+//
+// Only units of the same dimension can be converted.
+type Dimension string
+
+func (d Dimension) String() string {
+	return string(d)
+}
+
+type measure struct {
+	dimension Dimension
+	factor    float64 // factor converts to the reference unit of dimension.
+}
+
+// Reference units are the smallest of each dimension (micrograms,
+// milliliters and joules), so that metric factors are exact.
+var measures = map[Base]measure{
+	Microgram:   {Mass, 1},
+	Milligram:   {Mass, 1e3},
+	Gram:        {Mass, 1e6},
+	Ounce:       {Mass, 28_349_523.125},
+	Pound:       {Mass, 453_592_370},
+	Milliliter:  {Volume, 1},
+	Liter:       {Volume, 1e3},
+	FluidOunce:  {Volume, 29.5735295625},
+	Kilojoule:   {Energy, 1e3},
+	Kilocalorie: {Energy, 4184},
+}
+
+// Dimension returns the [Dimension] measured by b,
+// or [UnknownDimension] when b is unknown.
+func (b Base) Dimension() Dimension {
+	return measures[b].dimension
+}
+
+// Compatible reports whether a value can be converted from b to other.
+func (b Base) Compatible(other Base) bool {
+	d := b.Dimension()
+	return d != UnknownDimension && d == other.Dimension()
+}
+
+// Convert expresses v, measured in from, in the unit to.
+//
+// On failure the error wraps either:
+//   - [ErrUnknownUnit]
+//   - [ErrIncompatibleUnits]
+func Convert(v float64, from, to Base) (float64, error) {
+	src, ok := measures[from]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, from)
+	}
+
+	dst, ok := measures[to]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, to)
+	}
+
+	if src.dimension != dst.dimension {
+		return 0, fmt.Errorf("%w: %s (%s) to %s (%s)",
+			ErrIncompatibleUnits,
+			from,
+			src.dimension,
+			to,
+			dst.dimension,
+		)
+	}
+
+	if from == to {
+		return v, nil
+	}
+
+	return v * src.factor / dst.factor, nil
+}
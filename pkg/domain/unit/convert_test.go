@@ -0,0 +1,69 @@
+package unit
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+)
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name    string
+		v       float64
+		from    Base
+		to      Base
+		want    float64
+		wantErr error
+	}{
+		{name: "same unit", v: 12.5, from: Gram, to: Gram, want: 12.5},
+		{name: "gram to milligram", v: 1.5, from: Gram, to: Milligram, want: 1500},
+		{name: "milligram to microgram", v: 2, from: Milligram, to: Microgram, want: 2000},
+		{name: "microgram to gram", v: 500, from: Microgram, to: Gram, want: 0.0005},
+		{name: "pound to ounce", v: 1, from: Pound, to: Ounce, want: 16},
+		{name: "ounce to gram", v: 1, from: Ounce, to: Gram, want: 28.349523125},
+		{name: "kilocalorie to kilojoule", v: 100, from: Kilocalorie, to: Kilojoule, want: 418.4},
+		{name: "kilojoule to kilocalorie", v: 418.4, from: Kilojoule, to: Kilocalorie, want: 100},
+		{name: "liter to milliliter", v: 0.5, from: Liter, to: Milliliter, want: 500},
+		{name: "fluid ounce to milliliter", v: 2, from: FluidOunce, to: Milliliter, want: 59.147059125},
+		{name: "mass to volume", v: 1, from: Gram, to: Milliliter, wantErr: ErrIncompatibleUnits},
+		{name: "energy to mass", v: 1, from: Kilocalorie, to: Gram, wantErr: ErrIncompatibleUnits},
+		{name: "unknown source", v: 1, from: "cup", to: Gram, wantErr: ErrUnknownUnit},
+		{name: "unknown target", v: 1, from: Gram, to: "stone", wantErr: ErrUnknownUnit},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Convert(tb.v, tb.from, tb.to)
+			if tb.wantErr != nil {
+				if !errors.Is(err, tb.wantErr) {
+					t.Fatalf("got %v, want %v", err, tb.wantErr)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			if math.Abs(got-tb.want) > 1e-9 {
+				t.Fatalf("got %v, want %v", got, tb.want)
+			}
+		})
+	}
+}
+
+func TestBase_Dimension(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, Microgram.Dimension(), Mass)
+	assert.Equal(t, FluidOunce.Dimension(), Volume)
+	assert.Equal(t, Kilojoule.Dimension(), Energy)
+	assert.Equal(t, Base("cup").Dimension(), UnknownDimension)
+
+	assert.Equal(t, Pound.Compatible(Milligram), true)
+	assert.Equal(t, Liter.Compatible(Gram), false)
+	assert.Equal(t, Base("cup").Compatible("cup"), false)
+}
@@ -0,0 +1,8 @@
+package unit
+
+import "errors"
+
+var (
+	ErrUnknownUnit       = errors.New("given unit is unknown")
+	ErrIncompatibleUnits = errors.New("given units measure different dimensions")
+)
@@ -0,0 +1,7 @@
+package export
+
+import "errors"
+
+var (
+	ErrUnknownFormat = errors.New("given export format is unknown")
+)
@@ -0,0 +1,189 @@
+// Package export archives the diary history of a YAZIO
+// user as CSV, JSON or NDJSON.
+//
+// Days are fetched in windows and written as soon as each
+// window arrives, so long ranges don't have to fit in memory:
+//
+//	e := export.New(user, export.WithKinds(intake.Water), export.WithEntries())
+//	err := e.Export(ctx, os.Stdout, export.NDJSON, r)
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/diary"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+)
+
+const (
+	CSV    Format = "csv"    // CSV writes a header, then a row per day and per entry.
+	JSON   Format = "json"   // JSON writes an array with an object per day.
+	NDJSON Format = "ndjson" // NDJSON writes an object per day, one per line.
+)
+
+const (
+	defaultWindow = 31 // days
+)
+
+// Format is the encoding of an export.
+type Format string
+
+func (f Format) String() string {
+	return string(f)
+}
+
+// ParseFormat resolves the [Format] named s, ignoring case.
+//
+// On failure the error wraps either:
+//   - [ErrUnknownFormat]
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(s))
+	switch f {
+	case CSV, JSON, NDJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+	}
+}
+
+// User is the part of a user an [Exporter] reads
+// (usually a *yazio.User).
+type User interface {
+	Macros(ctx context.Context, r date.Range) (intake.MacrosRange, error)
+	Intakes(ctx context.Context, kinds []intake.Kind, r date.Range) (intake.Matrix, error)
+	Diary(ctx context.Context, day time.Time) ([]diary.Entry, error)
+}
+
+// Exporter writes the history of a user.
+//
+// Instances of Exporter should be created using [New].
+type Exporter struct {
+	user       User
+	kinds      []intake.Kind
+	entries    bool
+	dateLayout string
+	timeLayout string
+	window     int
+}
+
+// New returns an [*Exporter] of the history of u
+// (usually a *yazio.User). By default only macros
+// are exported; see [WithKinds] and [WithEntries].
+func New(u User, opts ...Option) *Exporter {
+	e := &Exporter{
+		user:       u,
+		dateLayout: time.DateOnly,
+		timeLayout: time.RFC3339,
+		window:     defaultWindow,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// day is the history of a single day.
+type day struct {
+	date    time.Time
+	macros  *intake.Macros
+	intakes map[intake.Kind]float64
+	entries []diary.Entry
+}
+
+// Export writes the days of r holding any data to w, encoded as f,
+// in chronological order.
+//
+// On failure the error wraps either:
+//   - [ErrUnknownFormat]
+//   - The errors of the user methods (e.g. yazio.ErrExpiredToken)
+//   - Other: generic (writer related)
+func (e *Exporter) Export(ctx context.Context, w io.Writer, f Format, r date.Range) error {
+	rw, err := e.newWriter(w, f)
+	if err != nil {
+		return err
+	}
+
+	if err := rw.begin(); err != nil {
+		return err
+	}
+
+	for _, window := range r.Split(e.window) {
+		days, err := e.collect(ctx, window)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range days {
+			if err := rw.write(d); err != nil {
+				return err
+			}
+		}
+	}
+
+	return rw.end()
+}
+
+// collect fetches every record of r, merged by day.
+func (e *Exporter) collect(ctx context.Context, r date.Range) ([]*day, error) {
+	var (
+		days  []*day
+		byKey = make(map[string]*day)
+		dayOf = func(t time.Time) *day {
+			key := t.Format(time.DateOnly)
+			if d, ok := byKey[key]; ok {
+				return d
+			}
+			d := &day{date: t}
+			byKey[key] = d
+			days = append(days, d)
+			return d
+		}
+	)
+
+	mr, err := e.user.Macros(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("exporting macros: %w", err)
+	}
+
+	for _, m := range mr {
+		dayOf(m.Date).macros = &m
+	}
+
+	if len(e.kinds) > 0 {
+		m, err := e.user.Intakes(ctx, e.kinds, r)
+		if err != nil {
+			return nil, fmt.Errorf("exporting intakes: %w", err)
+		}
+
+		for _, row := range m.Rows {
+			dayOf(row.Date).intakes = row.Values
+		}
+	}
+
+	if e.entries {
+		for _, t := range r.Dates() {
+			entries, err := e.user.Diary(ctx, t)
+			if err != nil {
+				return nil, fmt.Errorf("exporting diary of %s: %w", t.Format(time.DateOnly), err)
+			}
+
+			if len(entries) > 0 {
+				dayOf(t).entries = entries
+			}
+		}
+	}
+
+	slices.SortFunc(days, func(a, b *day) int {
+		return strings.Compare(a.date.Format(time.DateOnly), b.date.Format(time.DateOnly))
+	})
+
+	return days, nil
+}
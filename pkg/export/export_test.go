@@ -0,0 +1,227 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/internal/testutil/fakeuser"
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/yazio"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
+	"github.com/google/uuid"
+)
+
+var (
+	firstDay = time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)
+	lastDay  = firstDay.AddDate(0, 0, 2)
+
+	productID = uuid.MustParse("0b8f2a64-0d8f-4c36-9a3e-6f7f8c1b2d01")
+	entryIDs  = []uuid.UUID{
+		uuid.MustParse("9a1c6c8e-1f5b-4f0e-8a77-2b0c2f5d7e10"),
+		uuid.MustParse("9a1c6c8e-1f5b-4f0e-8a77-2b0c2f5d7e11"),
+	}
+)
+
+// seeded returns a user whose diary holds two entries of milk on the
+// first day and one on the last, leaving the middle day empty.
+func seeded(t *testing.T, opts ...yazio.Option) *yazio.User {
+	t.Helper()
+
+	fake := yaziotest.New(t,
+		yaziotest.WithProduct(yaziotest.Product{
+			ID:       productID,
+			Name:     "Milk",
+			BaseUnit: "ml",
+			Nutrients: map[string]float64{
+				"energy.energy":    64,
+				"nutrient.carb":    4.8,
+				"nutrient.fat":     3.6,
+				"nutrient.protein": 3.2,
+				"nutrient.water":   88,
+			},
+		}),
+	)
+
+	items := []yaziotest.ConsumedItem{
+		{ID: entryIDs[0], Date: firstDay.Add(8 * time.Hour), Daytime: "breakfast", Serving: "glass", Amount: 250, Quantity: 1},
+		{ID: entryIDs[1], Date: firstDay.Add(20 * time.Hour), Daytime: "snack", Serving: "glass", Amount: 250, Quantity: 1},
+		{ID: uuid.New(), Date: lastDay.Add(8 * time.Hour), Daytime: "breakfast", Serving: "cup", Amount: 200, Quantity: 0.5},
+	}
+	for _, ci := range items {
+		ci.ProductID = productID
+		assert.NoError(t, fake.Consume(yaziotest.DefaultUsername, ci))
+	}
+
+	return fakeuser.Login(t, fake, opts...)
+}
+
+func TestExporter_CSV(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf bytes.Buffer
+		e   = New(seeded(t), WithKinds(intake.Water), WithDateLayout("02/01/2006"), WithWindow(1))
+	)
+
+	err := e.Export(context.Background(), &buf, CSV, date.Range{Start: firstDay, End: lastDay})
+	assert.NoError(t, err)
+
+	want := strings.Join([]string{
+		"record,date,energy_kcal,carb_g,fat_g,protein_g,nutrient.water_ml",
+		"day,10/04/2025,320,24,18,16,440",
+		"day,12/04/2025,64,4.8,3.6,3.2,88",
+		"",
+	}, "\n")
+	assert.Equal(t, buf.String(), want)
+}
+
+func TestExporter_CSVEntries(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf bytes.Buffer
+		e   = New(seeded(t), WithEntries(), WithTimeLayout(time.TimeOnly))
+	)
+
+	err := e.Export(context.Background(), &buf, CSV, date.Range{Start: firstDay, End: firstDay})
+	assert.NoError(t, err)
+
+	want := strings.Join([]string{
+		"record,date,time,energy_kcal,carb_g,fat_g,protein_g,entry_id,meal,food_id,serving,amount,quantity",
+		"day,2025-04-10,,320,24,18,16,,,,,,",
+		"entry,2025-04-10,08:00:00,,,,," + entryIDs[0].String() + ",breakfast," + productID.String() + ",glass,250,1",
+		"entry,2025-04-10,20:00:00,,,,," + entryIDs[1].String() + ",snack," + productID.String() + ",glass,250,1",
+		"",
+	}, "\n")
+	assert.Equal(t, buf.String(), want)
+}
+
+func TestExporter_JSON(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf bytes.Buffer
+		e   = New(seeded(t), WithKinds(intake.Water), WithEntries())
+	)
+
+	err := e.Export(context.Background(), &buf, JSON, date.Range{Start: firstDay, End: lastDay})
+	assert.NoError(t, err)
+
+	var days []dayJSON
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &days))
+	assert.Equal(t, len(days), 2)
+
+	assert.Equal(t, days[0].Date, "2025-04-10")
+	assert.Equal(t, *days[0].Macros, macrosJSON{Energy: 320, Carb: 24, Fat: 18, Protein: 16})
+	assert.Equal(t, days[0].Intakes[intake.Water], 440.0)
+	assert.Equal(t, len(days[0].Entries), 2)
+	assert.Equal(t, days[0].Entries[0].Time, "2025-04-10T08:00:00Z")
+
+	assert.Equal(t, days[1].Date, "2025-04-12")
+	assert.Equal(t, days[1].Entries[0].Quantity, 0.5)
+}
+
+func TestExporter_GapFilling(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf bytes.Buffer
+		u   = seeded(t, yazio.WithGapFilling(intake.FillNoData))
+		e   = New(u, WithKinds(intake.Water))
+	)
+
+	err := e.Export(context.Background(), &buf, JSON, date.Range{Start: firstDay, End: lastDay})
+	assert.NoError(t, err)
+
+	var days []dayJSON
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &days))
+	assert.Equal(t, len(days), 3)
+	assert.Equal(t, days[1].Date, "2025-04-11")
+	assert.Equal(t, len(days[1].Intakes), 0) // a gap, not a value
+	assert.Equal(t, days[2].Intakes[intake.Water], 88.0)
+}
+
+func TestExporter_NDJSON(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf bytes.Buffer
+		e   = New(seeded(t))
+	)
+
+	err := e.Export(context.Background(), &buf, NDJSON, date.Range{Start: firstDay, End: lastDay})
+	assert.NoError(t, err)
+
+	var (
+		sc    = bufio.NewScanner(&buf)
+		dates []string
+	)
+
+	for sc.Scan() {
+		var d dayJSON
+		assert.NoError(t, json.Unmarshal(sc.Bytes(), &d))
+		assert.Equal(t, len(d.Entries), 0)
+		assert.Equal(t, len(d.Intakes), 0)
+		dates = append(dates, d.Date)
+	}
+
+	assert.DeepEqual(t, dates, []string{"2025-04-10", "2025-04-12"})
+}
+
+func TestExporter_Empty(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf   bytes.Buffer
+		e     = New(seeded(t))
+		empty = date.Range{Start: firstDay.AddDate(0, 0, -7), End: firstDay.AddDate(0, 0, -1)}
+	)
+
+	assert.NoError(t, e.Export(context.Background(), &buf, JSON, empty))
+	assert.Equal(t, buf.String(), "[]\n")
+
+	err := e.Export(context.Background(), &buf, "xml", empty)
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Fatalf("got %v, want %v", err, ErrUnknownFormat)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		{"csv", CSV, false},
+		{"JSON", JSON, false},
+		{"ndjson", NDJSON, false},
+		{"xml", "", true},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseFormat(tb.input)
+			if tb.wantErr {
+				if !errors.Is(err, ErrUnknownFormat) {
+					t.Fatalf("got %v, want %v", err, ErrUnknownFormat)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, got, tb.want)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package export
+
+import "github.com/controlado/go-yazio/pkg/domain/intake"
+
+type Option func(e *Exporter)
+
+// WithKinds exports the series of ks along with the
+// macros, in the given order (which is the CSV column order).
+func WithKinds(ks ...intake.Kind) Option {
+	return func(e *Exporter) {
+		e.kinds = append(e.kinds, ks...)
+	}
+}
+
+// WithEntries exports the diary entries of each day,
+// which costs one request per day of the range.
+func WithEntries() Option {
+	return func(e *Exporter) {
+		e.entries = true
+	}
+}
+
+// WithDateLayout sets the [time.Layout] of days.
+// It defaults to [time.DateOnly].
+func WithDateLayout(layout string) Option {
+	return func(e *Exporter) {
+		e.dateLayout = layout
+	}
+}
+
+// WithTimeLayout sets the [time.Layout] of diary entry
+// timestamps. It defaults to [time.RFC3339].
+func WithTimeLayout(layout string) Option {
+	return func(e *Exporter) {
+		e.timeLayout = layout
+	}
+}
+
+// WithWindow sets how many days are fetched (and kept in
+// memory) before being written. Values below 1 are ignored.
+func WithWindow(days int) Option {
+	return func(e *Exporter) {
+		if days > 0 {
+			e.window = days
+		}
+	}
+}
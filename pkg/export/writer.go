@@ -0,0 +1,236 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/controlado/go-yazio/pkg/domain/diary"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/google/uuid"
+)
+
+// recordWriter encodes days to an underlying writer.
+type recordWriter interface {
+	begin() error
+	write(d *day) error
+	end() error
+}
+
+func (e *Exporter) newWriter(w io.Writer, f Format) (recordWriter, error) {
+	switch f {
+	case CSV:
+		return &csvWriter{e: e, w: csv.NewWriter(w)}, nil
+	case JSON:
+		return &jsonWriter{e: e, w: w, array: true}, nil
+	case NDJSON:
+		return &jsonWriter{e: e, w: w}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, f)
+	}
+}
+
+type (
+	dayJSON struct {
+		Date    string                  `json:"date"`
+		Macros  *macrosJSON             `json:"macros,omitempty"`
+		Intakes map[intake.Kind]float64 `json:"intakes,omitempty"`
+		Entries []entryJSON             `json:"entries,omitempty"`
+	}
+	macrosJSON struct {
+		Energy  float64 `json:"energy"`
+		Carb    float64 `json:"carb"`
+		Fat     float64 `json:"fat"`
+		Protein float64 `json:"protein"`
+	}
+	entryJSON struct {
+		ID       uuid.UUID `json:"id"`
+		Time     string    `json:"time"`
+		Meal     string    `json:"meal"`
+		FoodID   uuid.UUID `json:"food_id"`
+		Serving  string    `json:"serving,omitempty"`
+		Amount   float64   `json:"amount"`
+		Quantity float64   `json:"quantity"`
+	}
+)
+
+func (e *Exporter) toJSON(d *day) dayJSON {
+	out := dayJSON{
+		Date:    d.date.Format(e.dateLayout),
+		Intakes: d.intakes,
+	}
+
+	if m := d.macros; m != nil {
+		out.Macros = &macrosJSON{m.Energy, m.Carb, m.Fat, m.Protein}
+	}
+
+	for _, entry := range d.entries {
+		out.Entries = append(out.Entries, entryJSON{
+			ID:       entry.ID,
+			Time:     entry.Date.Format(e.timeLayout),
+			Meal:     entry.Meal.String(),
+			FoodID:   entry.FoodID,
+			Serving:  entry.Serving.Kind.String(),
+			Amount:   entry.Serving.Amount,
+			Quantity: entry.Quantity,
+		})
+	}
+
+	return out
+}
+
+// jsonWriter writes an object per day, either as
+// elements of an array or as NDJSON lines.
+type jsonWriter struct {
+	e       *Exporter
+	w       io.Writer
+	array   bool
+	written int
+}
+
+func (jw *jsonWriter) begin() error {
+	if !jw.array {
+		return nil
+	}
+	_, err := io.WriteString(jw.w, "[")
+	return err
+}
+
+func (jw *jsonWriter) write(d *day) error {
+	raw, err := json.Marshal(jw.e.toJSON(d))
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !jw.array:
+		raw = append(raw, '\n')
+	case jw.written == 0:
+		raw = append([]byte("\n  "), raw...)
+	default:
+		raw = append([]byte(",\n  "), raw...)
+	}
+
+	jw.written++
+	_, err = jw.w.Write(raw)
+	return err
+}
+
+func (jw *jsonWriter) end() error {
+	if !jw.array {
+		return nil
+	}
+
+	closing := "]\n"
+	if jw.written > 0 {
+		closing = "\n]\n"
+	}
+
+	_, err := io.WriteString(jw.w, closing)
+	return err
+}
+
+// csvWriter writes a row per day, followed by a row per diary
+// entry of that day (when enabled), told apart by the first column.
+//
+// Columns are: record, date, [time], energy_kcal, carb_g, fat_g,
+// protein_g, a column per kind in the given order (e.g. water_ml)
+// and, with entries, entry_id, meal, food_id, serving, amount and quantity.
+type csvWriter struct {
+	e *Exporter
+	w *csv.Writer
+}
+
+const (
+	dayRecord   = "day"
+	entryRecord = "entry"
+)
+
+func (cw *csvWriter) begin() error {
+	header := []string{"record", "date"}
+	if cw.e.entries {
+		header = append(header, "time")
+	}
+
+	header = append(header, "energy_kcal", "carb_g", "fat_g", "protein_g")
+	for _, k := range cw.e.kinds {
+		header = append(header, fmt.Sprintf("%s_%s", k, k.Unit()))
+	}
+
+	if cw.e.entries {
+		header = append(header, "entry_id", "meal", "food_id", "serving", "amount", "quantity")
+	}
+
+	return cw.w.Write(header)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func (cw *csvWriter) write(d *day) error {
+	row := []string{dayRecord, d.date.Format(cw.e.dateLayout)}
+	if cw.e.entries {
+		row = append(row, "")
+	}
+
+	if m := d.macros; m != nil {
+		row = append(row, formatFloat(m.Energy), formatFloat(m.Carb), formatFloat(m.Fat), formatFloat(m.Protein))
+	} else {
+		row = append(row, "", "", "", "")
+	}
+
+	for _, k := range cw.e.kinds {
+		value := ""
+		if v, ok := d.intakes[k]; ok {
+			value = formatFloat(v)
+		}
+		row = append(row, value)
+	}
+
+	if cw.e.entries {
+		row = append(row, "", "", "", "", "", "")
+	}
+
+	if err := cw.w.Write(row); err != nil {
+		return err
+	}
+
+	for _, entry := range d.entries {
+		if err := cw.w.Write(cw.entryRow(d, entry)); err != nil {
+			return err
+		}
+	}
+
+	cw.w.Flush() // a window at a time
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) entryRow(d *day, entry diary.Entry) []string {
+	row := []string{
+		entryRecord,
+		d.date.Format(cw.e.dateLayout),
+		entry.Date.Format(cw.e.timeLayout),
+		"", "", "", "", // macros
+	}
+
+	for range cw.e.kinds {
+		row = append(row, "")
+	}
+
+	return append(row,
+		entry.ID.String(),
+		entry.Meal.String(),
+		entry.FoodID.String(),
+		entry.Serving.Kind.String(),
+		formatFloat(entry.Serving.Amount),
+		formatFloat(entry.Quantity),
+	)
+}
+
+func (cw *csvWriter) end() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
@@ -0,0 +1,267 @@
+package fdc
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+	"github.com/google/uuid"
+)
+
+var (
+	// namespace derives the food IDs from FDC IDs.
+	namespace = uuid.MustParse("a4e2c7d9-5b13-4f6a-8e0c-3d9b1f72c658")
+
+	// sources lists, for each kind, the numbers of the nutrients
+	// holding it, preferred first (e.g. energy in kcal over kJ).
+	sources = []struct {
+		kind    intake.Kind
+		numbers []string
+	}{
+		{intake.Energy, []string{"208", "958", "957", "268"}}, // kcal, Atwater specific, general, kJ
+		{intake.Protein, []string{"203"}},
+		{intake.Fat, []string{"204"}},
+		{intake.Carb, []string{"205"}},
+		{intake.Fiber, []string{"291"}},
+		{intake.Sugar, []string{"269", "269.3"}},
+		{intake.AddedSugar, []string{"539"}},
+		{intake.Saturated, []string{"606"}},
+		{intake.Monounsaturated, []string{"645"}},
+		{intake.Polyunsaturated, []string{"646"}},
+		{intake.TransFat, []string{"605"}},
+		{intake.Cholesterol, []string{"601"}},
+		{intake.Sodium, []string{"307"}},
+		{intake.Water, []string{"255"}},
+		{intake.Alcohol, []string{"221"}},
+		{intake.Calcium, []string{"301"}},
+		{intake.Iron, []string{"303"}},
+		{intake.MineralMagnesium, []string{"304"}},
+		{intake.MineralPhosphorus, []string{"305"}},
+		{intake.Potassium, []string{"306"}},
+		{intake.MineralZinc, []string{"309"}},
+		{intake.MineralCopper, []string{"312"}},
+		{intake.MineralFluoride, []string{"313"}},
+		{intake.MineralIodine, []string{"314"}},
+		{intake.MineralManganese, []string{"315"}},
+		{intake.MineralSelenium, []string{"317"}},
+		{intake.MineralMolybdenum, []string{"316"}},
+		{intake.VitaminA, []string{"320"}}, // RAE
+		{intake.VitaminE, []string{"323"}}, // alpha-tocopherol
+		{intake.VitaminD, []string{"328"}}, // D2 + D3, in µg
+		{intake.VitaminC, []string{"401"}},
+		{intake.VitaminB1, []string{"404"}},
+		{intake.VitaminB2, []string{"405"}},
+		{intake.VitaminB3, []string{"406"}},
+		{intake.VitaminB5, []string{"410"}},
+		{intake.VitaminB6, []string{"415"}},
+		{intake.VitaminB7, []string{"416"}},
+		{intake.VitaminB11, []string{"417", "435"}}, // total, DFE
+		{intake.VitaminB12, []string{"418"}},
+		{intake.MineralCholine, []string{"421"}},
+		{intake.VitaminK, []string{"430"}}, // phylloquinone
+	}
+
+	// alternates are the numbers of sources, which aren't
+	// reported as unmapped when a preferred one was used.
+	alternates = func() map[string]bool {
+		out := make(map[string]bool)
+		for _, s := range sources {
+			for _, n := range s.numbers {
+				out[n] = true
+			}
+		}
+		return out
+	}()
+
+	// categories maps the food categories onto [food.Category].
+	categories = map[string]food.Category{
+		"Baby Foods":                        food.BabyFood,
+		"Baked Products":                    food.Bread,
+		"Beef Products":                     food.Meat,
+		"Beverages":                         food.NonAlcoholicDrink,
+		"Breakfast Cereals":                 food.Cereal,
+		"Cereal Grains and Pasta":           food.Grain,
+		"Dairy and Egg Products":            food.Dairy,
+		"Fast Foods":                        food.FastFood,
+		"Fats and Oils":                     food.Fat,
+		"Finfish and Shellfish Products":    food.Fish,
+		"Fruits and Fruit Juices":           food.Fruit,
+		"Lamb, Veal, and Game Products":     food.Meat,
+		"Legumes and Legume Products":       food.Legume,
+		"Meals, Entrees, and Side Dishes":   food.Dish,
+		"Nut and Seed Products":             food.Nut,
+		"Pork Products":                     food.Meat,
+		"Poultry Products":                  food.Poultry,
+		"Restaurant Foods":                  food.Dish,
+		"Sausages and Luncheon Meats":       food.Sausage,
+		"Snacks":                            food.Snack,
+		"Soups, Sauces, and Gravies":        food.Soup,
+		"Spices and Herbs":                  food.Spice,
+		"Sweets":                            food.Sweet,
+		"Vegetables and Vegetable Products": food.Vegetable,
+	}
+)
+
+// Report tells what a conversion left out.
+type Report struct {
+	FdcID    int            // FdcID identifies the converted food.
+	Unmapped []FoodNutrient // Unmapped are nutrients YAZIO doesn't track, or in units it can't convert (e.g. IU).
+}
+
+func (r Report) String() string {
+	names := make([]string, len(r.Unmapped))
+	for i, fn := range r.Unmapped {
+		names[i] = fn.Nutrient.Name
+	}
+	return fmt.Sprintf("%d: %d unmapped nutrients (%s)", r.FdcID, len(names), strings.Join(names, ", "))
+}
+
+// ID returns the food ID derived from fdcID, so
+// converting a food twice gives the same ID.
+func ID(fdcID int) food.ID {
+	return uuid.NewSHA1(namespace, []byte(strconv.Itoa(fdcID)))
+}
+
+// Convert converts f into a [food.Food] in grams, identified by [ID].
+//
+// Nutrients are converted into the base unit of their kind (e.g. sodium
+// from mg into mcg, energy from kJ when kcal is missing). Portions become
+// servings weighing a single measure, the first of each kind kept.
+//
+// The food is not validated; see [food.Food.Validate].
+//
+// On failure the error wraps either:
+//   - [food.ErrMissingNutrients] if energy, fat, carbs or protein are missing
+//   - [food.ErrInvalidName]
+func (f Food) Convert() (food.Food, Report, error) {
+	nutrients, rep := f.Nutrients()
+
+	var missing []string
+	for _, k := range []intake.Kind{intake.Energy, intake.Fat, intake.Carb, intake.Protein} {
+		if _, ok := nutrients[k]; !ok {
+			missing = append(missing, k.String())
+		}
+	}
+	if len(missing) > 0 {
+		return food.Food{}, rep, fmt.Errorf("%w: %s", food.ErrMissingNutrients, strings.Join(missing, ", "))
+	}
+
+	opts := []food.Option{food.WithID(ID(f.FdcID))}
+	for _, s := range f.Servings() {
+		opts = append(opts, food.WithServing(s))
+	}
+
+	cat, ok := categories[f.FoodCategory.Description]
+	if !ok {
+		cat = food.Miscellaneous
+	}
+
+	out, err := food.New(f.Description, cat, nutrients, opts...)
+	return out, rep, err
+}
+
+// Nutrients returns the nutrients of f per 100g, reporting
+// the ones that couldn't be mapped.
+func (f Food) Nutrients() (food.Nutrients, Report) {
+	var (
+		out      = make(food.Nutrients)
+		rep      = Report{FdcID: f.FdcID}
+		byNumber = make(map[string]FoodNutrient, len(f.FoodNutrients))
+	)
+
+	for _, fn := range f.FoodNutrients {
+		byNumber[fn.Nutrient.Number] = fn
+	}
+
+	used := make(map[string]bool)
+	for _, s := range sources {
+		for _, n := range s.numbers {
+			fn, ok := byNumber[n]
+			if !ok {
+				continue
+			}
+
+			v, err := s.kind.FromUnit(fn.Amount, unitOf(fn.Nutrient.UnitName))
+			if err != nil {
+				continue
+			}
+
+			out[s.kind] = v
+			used[n] = true
+			break
+		}
+	}
+
+	for _, fn := range f.FoodNutrients {
+		n := fn.Nutrient.Number
+		if used[n] || alternates[n] && coveredBy(n, used) {
+			continue
+		}
+		rep.Unmapped = append(rep.Unmapped, fn)
+	}
+
+	return out, rep
+}
+
+// coveredBy reports whether the kind that number is
+// a source of was taken from another used number.
+func coveredBy(number string, used map[string]bool) bool {
+	for _, s := range sources {
+		if slices.Contains(s.numbers, number) {
+			return slices.ContainsFunc(s.numbers, func(n string) bool { return used[n] })
+		}
+	}
+	return false
+}
+
+// unitOf maps the unit names of FoodData Central onto [unit.Base].
+func unitOf(name string) unit.Base {
+	switch u := strings.ToLower(name); u {
+	case "µg", "ug", "mcg":
+		return unit.Microgram
+	case "kj":
+		return unit.Kilojoule
+	default:
+		return unit.Base(u)
+	}
+}
+
+// Servings returns a serving per kind of portion of f, weighing
+// one measure, in the order of the portions.
+func (f Food) Servings() []food.Serving {
+	portions := slices.Clone(f.FoodPortions)
+	slices.SortStableFunc(portions, func(a, b Portion) int {
+		return a.SequenceNumber - b.SequenceNumber
+	})
+
+	var out []food.Serving
+	for _, p := range portions {
+		if p.GramWeight <= 0 {
+			continue
+		}
+
+		amount := p.Amount
+		if amount <= 0 {
+			amount = 1
+		}
+
+		desc := p.MeasureUnit.Name
+		if desc == "" || desc == "undetermined" {
+			desc = p.Modifier
+		}
+		if desc == "" {
+			desc = p.PortionDescription
+		}
+
+		s := food.Serving{Kind: food.GuessServingKind(desc), Amount: p.GramWeight / amount}
+		if !slices.ContainsFunc(out, func(other food.Serving) bool { return other.Kind == s.Kind }) {
+			out = append(out, s)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,7 @@
+package fdc
+
+import "errors"
+
+var (
+	ErrInvalidDocument = errors.New("given fooddata central document is invalid")
+)
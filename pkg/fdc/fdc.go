@@ -0,0 +1,131 @@
+// Package fdc converts foods of USDA FoodData Central into
+// foods that can be registered with YAZIO.
+//
+// It reads the JSON downloads of the Foundation and SR Legacy
+// data types, or single foods in their full format:
+//
+//	foods, err := fdc.Open("FoodData_Central_foundation_food_json.json")
+//	f, report, err := foods[0].Convert()
+//	// report.Unmapped: nutrients YAZIO doesn't track
+package fdc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Food is a food of FoodData Central, in its full format.
+type Food struct {
+	FdcID         int            `json:"fdcId"`
+	Description   string         `json:"description"`
+	DataType      string         `json:"dataType"` // e.g. "Foundation", "SR Legacy".
+	FoodCategory  Category       `json:"foodCategory"`
+	FoodNutrients []FoodNutrient `json:"foodNutrients"`
+	FoodPortions  []Portion      `json:"foodPortions"`
+}
+
+func (f Food) String() string {
+	return fmt.Sprintf("Food(%d, %s)", f.FdcID, f.Description)
+}
+
+// Category is the food category of a [Food].
+type Category struct {
+	Description string `json:"description"` // e.g. "Dairy and Egg Products".
+}
+
+// FoodNutrient is the amount of a nutrient per 100g of a [Food].
+type FoodNutrient struct {
+	Nutrient Nutrient `json:"nutrient"`
+	Amount   float64  `json:"amount"`
+}
+
+func (fn FoodNutrient) String() string {
+	return fmt.Sprintf("%s %s: %g%s", fn.Nutrient.Number, fn.Nutrient.Name, fn.Amount, fn.Nutrient.UnitName)
+}
+
+// Nutrient identifies a nutrient by its number (e.g. "203" is protein).
+type Nutrient struct {
+	ID       int    `json:"id"`
+	Number   string `json:"number"`
+	Name     string `json:"name"`
+	UnitName string `json:"unitName"` // e.g. "g", "mg", "µg", "kcal", "IU".
+}
+
+// Portion is a household measure of a [Food]
+// (e.g. 1 cup, chopped) and its weight.
+type Portion struct {
+	Amount             float64     `json:"amount"`     // Amount is how many measures weigh GramWeight.
+	GramWeight         float64     `json:"gramWeight"` // GramWeight is the weight of Amount measures.
+	Modifier           string      `json:"modifier"`   // Modifier describes the measure on SR Legacy.
+	PortionDescription string      `json:"portionDescription"`
+	MeasureUnit        MeasureUnit `json:"measureUnit"`
+	SequenceNumber     int         `json:"sequenceNumber"`
+}
+
+// MeasureUnit is the unit of a [Portion], "undetermined" on SR Legacy.
+type MeasureUnit struct {
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+}
+
+// download is the document of the bulk downloads.
+type download struct {
+	Foundation []Food `json:"FoundationFoods"`
+	SRLegacy   []Food `json:"SRLegacyFoods"`
+}
+
+// Decode reads the foods of r, which holds either a bulk download
+// (Foundation or SR Legacy), an array of foods or a single food.
+//
+// The whole document is held in memory; SR Legacy takes a few
+// hundred megabytes.
+//
+// On failure the error wraps either:
+//   - [ErrInvalidDocument]
+func Decode(r io.Reader) ([]Food, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var foods []Food
+		if err := json.Unmarshal(data, &foods); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+		}
+		return foods, nil
+	}
+
+	var dl download
+	if err := json.Unmarshal(data, &dl); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+	if foods := append(dl.Foundation, dl.SRLegacy...); len(foods) > 0 {
+		return foods, nil
+	}
+
+	var f Food
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+	if f.FdcID == 0 {
+		return nil, fmt.Errorf("%w: no foods", ErrInvalidDocument)
+	}
+
+	return []Food{f}, nil
+}
+
+// Open reads the foods of the document at path; see [Decode].
+func Open(path string) ([]Food, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Decode(file)
+}
@@ -0,0 +1,118 @@
+package fdc
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+)
+
+func assertNear(t *testing.T, got, want float64) {
+	t.Helper()
+
+	if math.Abs(got-want) > 1e-9 {
+		t.Fatalf("\ngot %v\nwant %v", got, want)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name    string
+		input   string
+		wantIDs []int
+		wantErr bool
+	}{
+		{name: "single food", input: `{"fdcId": 1, "description": "Oats"}`, wantIDs: []int{1}},
+		{name: "array", input: ` [{"fdcId": 1}, {"fdcId": 2}]`, wantIDs: []int{1, 2}},
+		{name: "download", input: `{"SRLegacyFoods": [{"fdcId": 3}]}`, wantIDs: []int{3}},
+		{name: "no foods", input: `{"FoundationFoods": []}`, wantErr: true},
+		{name: "not json", input: `fdcId,description`, wantErr: true},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			foods, err := Decode(strings.NewReader(tb.input))
+			if tb.wantErr {
+				if !errors.Is(err, ErrInvalidDocument) {
+					t.Fatalf("got %v, want %v", err, ErrInvalidDocument)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, len(foods), len(tb.wantIDs))
+			for i, f := range foods {
+				assert.Equal(t, f.FdcID, tb.wantIDs[i])
+			}
+		})
+	}
+}
+
+func TestFood_ConvertFoundation(t *testing.T) {
+	t.Parallel()
+
+	foods, err := Open("testdata/foundation.json")
+	assert.NoError(t, err)
+	assert.Equal(t, len(foods), 1)
+
+	f, rep, err := foods[0].Convert()
+	assert.NoError(t, err)
+	assert.NoError(t, f.Validate())
+
+	assert.Equal(t, f.ID, ID(321358))
+	assert.Equal(t, f.Name, "Hummus, commercial")
+	assert.Equal(t, f.Category, food.Legume)
+	assert.Equal(t, f.BaseUnit, unit.Gram)
+
+	assert.Equal(t, f.Nutrients[intake.Energy], 229.0) // Atwater specific, over kJ
+	assert.Equal(t, f.Nutrients[intake.Protein], 7.35)
+	assert.Equal(t, f.Nutrients[intake.Sodium], 438000.0) // mcg
+	assert.Equal(t, f.Nutrients[intake.Water], 57.6)      // ml
+
+	assert.DeepEqual(t, f.Servings, []food.Serving{
+		{Kind: food.Cup, Amount: 246},
+		{Kind: food.Tablespoon, Amount: 15},
+	})
+
+	assert.Equal(t, len(rep.Unmapped), 2)
+	assert.Equal(t, rep.Unmapped[0].Nutrient.Number, "324") // IU
+	assert.Equal(t, rep.Unmapped[1].Nutrient.Number, "454") // betaine
+	assert.Equal(t, rep.String(), "321358: 2 unmapped nutrients (Vitamin D (D2 + D3), International Units, Betaine)")
+}
+
+func TestFood_ConvertSRLegacy(t *testing.T) {
+	t.Parallel()
+
+	foods, err := Open("testdata/srlegacy.json")
+	assert.NoError(t, err)
+	assert.Equal(t, len(foods), 2)
+
+	milk, rep, err := foods[0].Convert()
+	assert.NoError(t, err)
+	assert.Equal(t, len(rep.Unmapped), 0)
+
+	assert.Equal(t, milk.Category, food.Dairy)
+	assert.Equal(t, milk.Nutrients[intake.Sugar], 5.05)
+	assert.Equal(t, milk.Nutrients[intake.Calcium], 113.0)
+	assert.Equal(t, milk.Nutrients[intake.VitaminD], 1.3)
+	assertNear(t, milk.Nutrients[intake.VitaminB12], 0.45)
+
+	// modifiers describe the measure; unknown ones are portions
+	assert.DeepEqual(t, milk.Servings, []food.Serving{
+		{Kind: food.Cup, Amount: 244},
+		{Kind: food.Portion, Amount: 976},
+	})
+
+	if _, _, err := foods[1].Convert(); !errors.Is(err, food.ErrMissingNutrients) {
+		t.Fatalf("got %v, want %v", err, food.ErrMissingNutrients)
+	}
+}
@@ -0,0 +1,246 @@
+// Package ics exports the diary of a YAZIO user as an
+// iCalendar (RFC 5545) feed, with an event per meal:
+//
+//	e := ics.New(user, ics.WithName("Meals"))
+//	err := e.Export(ctx, os.Stdout, r)
+//
+// The UID of an event derives from its account, day and meal, so
+// importing (or subscribing to) a newer export of the same days
+// updates the events instead of duplicating them, even after entries
+// are logged or deleted, while the feeds of other accounts loaded into
+// the same calendar app keep events of their own. Its SEQUENCE is the export time, in seconds since the
+// Unix epoch, so each export supersedes the previous ones.
+package ics
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/diary"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/meal"
+	"github.com/controlado/go-yazio/pkg/domain/user"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultName     = "YAZIO"
+	defaultDuration = 30 * time.Minute
+
+	prodID      = "-//controlado//go-yazio//EN"
+	uidDomain   = "go-yazio"
+	unknownFood = "Unknown food"
+
+	layoutUTC = "20060102T150405Z"
+)
+
+// uidNamespace is the namespace of the name-based UIDs of events.
+var uidNamespace = uuid.MustParse("a9f17b85-9ad7-40f4-8114-7a408bac5c6b")
+
+// User is the part of a user an [Exporter] reads
+// (usually a *yazio.User).
+type User interface {
+	Data(ctx context.Context) (user.Data, error)
+	Diary(ctx context.Context, day time.Time) ([]diary.Entry, error)
+	Food(ctx context.Context, id food.ID) (food.Food, error)
+}
+
+// Exporter writes the diary of a user as a calendar.
+//
+// Instances of Exporter should be created using [New].
+type Exporter struct {
+	user     User
+	name     string
+	duration time.Duration
+	now      func() time.Time
+}
+
+// New returns an [*Exporter] of the diary of u
+// (usually a *yazio.User).
+func New(u User, opts ...Option) *Exporter {
+	e := &Exporter{
+		user:     u,
+		name:     defaultName,
+		duration: defaultDuration,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// event is a meal of a single day.
+type event struct {
+	day     time.Time
+	meal    meal.Time
+	entries []diary.Entry // sorted by time
+}
+
+// Export writes a calendar holding an event per meal logged
+// in the days of r to w, in chronological order.
+//
+// Foods that no longer exist are listed as unknown,
+// without energy, instead of failing the export.
+//
+// On failure the error wraps either:
+//   - The errors of the user methods (e.g. yazio.ErrExpiredToken)
+//   - Other: generic (writer related)
+func (e *Exporter) Export(ctx context.Context, w io.Writer, r date.Range) error {
+	data, err := e.user.Data(ctx)
+	if err != nil {
+		return fmt.Errorf("exporting account: %w", err)
+	}
+
+	var (
+		cw    = &calendarWriter{w: w}
+		foods = make(map[food.ID]*food.Food)
+		now   = e.now()
+	)
+
+	cw.property("BEGIN", "VCALENDAR")
+	cw.property("VERSION", "2.0")
+	cw.property("PRODID", prodID)
+	cw.property("CALSCALE", "GREGORIAN")
+	cw.property("METHOD", "PUBLISH")
+	cw.property("X-WR-CALNAME", escapeText(e.name))
+
+	for _, t := range r.Dates() {
+		entries, err := e.user.Diary(ctx, t)
+		if err != nil {
+			return fmt.Errorf("exporting diary of %s: %w", t.Format(time.DateOnly), err)
+		}
+
+		for _, ev := range group(t, entries) {
+			if err := e.resolve(ctx, foods, ev); err != nil {
+				return err
+			}
+			e.writeEvent(cw, ev, foods, uidOf(data.ID, ev), now)
+		}
+	}
+
+	cw.property("END", "VCALENDAR")
+	return cw.err
+}
+
+// group splits the entries of day into an event
+// per meal, in the order of [meal.Times].
+func group(day time.Time, entries []diary.Entry) []event {
+	var events []event
+
+	for _, m := range meal.Times() {
+		ev := event{day: day, meal: m}
+		for _, en := range entries {
+			if en.Meal == m {
+				ev.entries = append(ev.entries, en)
+			}
+		}
+
+		if len(ev.entries) == 0 {
+			continue
+		}
+
+		slices.SortStableFunc(ev.entries, func(a, b diary.Entry) int {
+			return cmp.Or(
+				a.Date.Compare(b.Date),
+				strings.Compare(a.ID.String(), b.ID.String()),
+			)
+		})
+		events = append(events, ev)
+	}
+
+	return events
+}
+
+// resolve fetches the foods of ev missing from foods,
+// remembering unknown ones as nil.
+func (e *Exporter) resolve(ctx context.Context, foods map[food.ID]*food.Food, ev event) error {
+	for _, en := range ev.entries {
+		if _, ok := foods[en.FoodID]; ok {
+			continue
+		}
+
+		f, err := e.user.Food(ctx, en.FoodID)
+		switch {
+		case err == nil:
+			foods[en.FoodID] = &f
+		case errors.Is(err, food.ErrNotFound):
+			foods[en.FoodID] = nil
+		default:
+			return fmt.Errorf("exporting food %s: %w", en.FoodID, err)
+		}
+	}
+
+	return nil
+}
+
+// uidOf returns the UID of ev in the calendar of account,
+// e.g. derived from "<account>/2025-04-12/breakfast".
+func uidOf(account uuid.UUID, ev event) string {
+	name := fmt.Sprintf("%s/%s/%s", account, ev.day.Format(time.DateOnly), ev.meal)
+	return fmt.Sprintf("%s@%s", uuid.NewSHA1(uidNamespace, []byte(name)), uidDomain)
+}
+
+func (e *Exporter) writeEvent(cw *calendarWriter, ev event, foods map[food.ID]*food.Food, uid string, now time.Time) {
+	var (
+		stamp = now.UTC().Format(layoutUTC)
+		first = ev.entries[0]
+		last  = ev.entries[len(ev.entries)-1]
+		lines = make([]string, 0, len(ev.entries))
+		kcal  float64
+	)
+
+	for _, en := range ev.entries {
+		f := foods[en.FoodID]
+		if f == nil {
+			lines = append(lines, fmt.Sprintf("%s: %s", unknownFood, amountOf(en, "")))
+			continue
+		}
+
+		energy := f.NutrientsPer(en.BaseAmount())[intake.Energy]
+		kcal += energy
+		lines = append(lines, fmt.Sprintf("%s: %s, %.0f kcal", f.Name, amountOf(en, f.BaseUnit.String()), energy))
+	}
+
+	cw.property("BEGIN", "VEVENT")
+	cw.property("UID", uid)
+	cw.property("SEQUENCE", strconv.FormatInt(now.Unix(), 10))
+	cw.property("DTSTAMP", stamp)
+	cw.property("LAST-MODIFIED", stamp)
+	cw.property("DTSTART", first.Date.UTC().Format(layoutUTC))
+	cw.property("DTEND", last.Date.Add(e.duration).UTC().Format(layoutUTC))
+	cw.property("SUMMARY", escapeText(fmt.Sprintf("%s: %.0f kcal", titleOf(ev.meal), kcal)))
+	cw.property("DESCRIPTION", escapeText(strings.Join(lines, "\n")))
+	cw.property("CATEGORIES", escapeText(ev.meal.String()))
+	cw.property("TRANSP", "TRANSPARENT")
+	cw.property("END", "VEVENT")
+}
+
+// amountOf describes how much of the food en logs,
+// e.g. "2 slice (60 g)" or "150 g".
+func amountOf(en diary.Entry, baseUnit string) string {
+	base := strings.TrimSpace(fmt.Sprintf("%g %s", en.BaseAmount(), baseUnit))
+	if en.Serving.Kind == "" {
+		return base
+	}
+	return fmt.Sprintf("%g %s (%s)", en.Quantity, en.Serving.Kind, base)
+}
+
+func titleOf(m meal.Time) string {
+	s := m.String()
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
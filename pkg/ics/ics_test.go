@@ -0,0 +1,276 @@
+package ics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/internal/testutil/fakeuser"
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/yazio"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
+	"github.com/google/uuid"
+)
+
+var (
+	firstDay = time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)
+	lastDay  = firstDay.AddDate(0, 0, 1)
+	stamp    = time.Date(2025, 4, 12, 9, 30, 0, 0, time.UTC)
+
+	breadID  = uuid.MustParse("0b8f2a64-0d8f-4c36-9a3e-6f7f8c1b2d01")
+	milkID   = uuid.MustParse("0b8f2a64-0d8f-4c36-9a3e-6f7f8c1b2d02")
+	entryIDs = []uuid.UUID{
+		uuid.MustParse("9a1c6c8e-1f5b-4f0e-8a77-2b0c2f5d7e10"),
+		uuid.MustParse("9a1c6c8e-1f5b-4f0e-8a77-2b0c2f5d7e11"),
+		uuid.MustParse("9a1c6c8e-1f5b-4f0e-8a77-2b0c2f5d7e12"),
+		uuid.MustParse("9a1c6c8e-1f5b-4f0e-8a77-2b0c2f5d7e13"),
+	}
+)
+
+// seeded returns a user whose diary holds a breakfast of bread and
+// milk and a dinner of a deleted food on the first day, leaving
+// the last day empty.
+func seeded(t *testing.T, opts ...yazio.Option) (*yaziotest.Server, *yazio.User) {
+	t.Helper()
+
+	fake := yaziotest.New(t,
+		yaziotest.WithProduct(yaziotest.Product{
+			ID:        breadID,
+			Name:      "Bread, whole; sliced",
+			BaseUnit:  "g",
+			Nutrients: map[string]float64{"energy.energy": 250},
+		}),
+		yaziotest.WithProduct(yaziotest.Product{
+			ID:        milkID,
+			Name:      "Milk",
+			BaseUnit:  "ml",
+			Nutrients: map[string]float64{"energy.energy": 64},
+		}),
+	)
+
+	items := []yaziotest.ConsumedItem{
+		{ID: entryIDs[1], ProductID: milkID, Date: firstDay.Add(8*time.Hour + 10*time.Minute), Daytime: "breakfast", Serving: "glass", Amount: 250, Quantity: 1},
+		{ID: entryIDs[0], ProductID: breadID, Date: firstDay.Add(8 * time.Hour), Daytime: "breakfast", Serving: "slice", Amount: 30, Quantity: 2},
+		{ID: entryIDs[2], ProductID: uuid.New(), Date: firstDay.Add(19 * time.Hour), Daytime: "dinner", Amount: 300, Quantity: 1},
+	}
+	for _, ci := range items {
+		assert.NoError(t, fake.Consume(yaziotest.DefaultUsername, ci))
+	}
+
+	return fake, fakeuser.Login(t, fake, opts...)
+}
+
+func export(t *testing.T, e *Exporter) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	assert.NoError(t, e.Export(context.Background(), &buf, date.Range{Start: firstDay, End: lastDay}))
+
+	return buf.String()
+}
+
+func TestExporter_Export(t *testing.T) {
+	t.Parallel()
+
+	var (
+		loc  = time.FixedZone("UTC-3", -3*60*60)
+		_, u = seeded(t, yazio.WithLocation(loc))
+		e    = New(u, WithName("Meals"), WithDuration(time.Hour))
+	)
+	e.now = func() time.Time { return stamp }
+
+	data, err := u.Data(context.Background())
+	assert.NoError(t, err)
+
+	uid := func(name string) string {
+		return uuid.NewSHA1(uidNamespace, []byte(data.ID.String()+"/"+name)).String() + "@go-yazio"
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + prodID,
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Meals",
+		"BEGIN:VEVENT",
+		"UID:" + uid("2025-04-10/breakfast"),
+		"SEQUENCE:1744450200",
+		"DTSTAMP:20250412T093000Z",
+		"LAST-MODIFIED:20250412T093000Z",
+		"DTSTART:20250410T110000Z",
+		"DTEND:20250410T121000Z",
+		"SUMMARY:Breakfast: 310 kcal",
+		`DESCRIPTION:Bread\, whole\; sliced: 2 slice (60 g)\, 150 kcal\nMilk: 1 glas`,
+		` s (250 ml)\, 160 kcal`,
+		"CATEGORIES:breakfast",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:" + uid("2025-04-10/dinner"),
+		"SEQUENCE:1744450200",
+		"DTSTAMP:20250412T093000Z",
+		"LAST-MODIFIED:20250412T093000Z",
+		"DTSTART:20250410T220000Z",
+		"DTEND:20250410T230000Z",
+		"SUMMARY:Dinner: 0 kcal",
+		"DESCRIPTION:Unknown food: 300",
+		"CATEGORIES:dinner",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	assert.Equal(t, export(t, e), want)
+}
+
+func TestExporter_StableUIDs(t *testing.T) {
+	t.Parallel()
+
+	fake, u := seeded(t)
+	e := New(u)
+	e.now = func() time.Time { return stamp }
+
+	before := export(t, e)
+
+	// logged before the first entry of the breakfast
+	assert.NoError(t, fake.Consume(yaziotest.DefaultUsername, yaziotest.ConsumedItem{
+		ID:        entryIDs[3],
+		ProductID: breadID,
+		Date:      firstDay.Add(7*time.Hour + 30*time.Minute),
+		Daytime:   "breakfast",
+		Serving:   "slice",
+		Amount:    30,
+		Quantity:  1,
+	}))
+
+	later := stamp.Add(time.Hour)
+	e.now = func() time.Time { return later }
+	after := export(t, e)
+	seq := strconv.FormatInt(later.Unix(), 10)
+
+	assert.Equal(t, strings.Count(after, "BEGIN:VEVENT"), 2)
+	assert.Equal(t, properties(after, "UID"), properties(before, "UID"))
+	assert.Equal(t, properties(after, "SEQUENCE"), seq+","+seq)
+	assert.Equal(t, strings.Contains(after, "DTSTART:20250410T073000Z"), true)
+	assert.Equal(t, strings.Contains(after, "SUMMARY:Breakfast: 385 kcal"), true)
+}
+
+// properties joins the values of every name property of cal.
+func properties(cal, name string) string {
+	var out []string
+	for _, line := range strings.Split(cal, "\r\n") {
+		if v, ok := strings.CutPrefix(line, name+":"); ok {
+			out = append(out, v)
+		}
+	}
+	return strings.Join(out, ",")
+}
+
+func TestExporter_UIDsPerAccount(t *testing.T) {
+	t.Parallel()
+
+	const (
+		username = "maria@yaziotest.local"
+		password = "yaziotest2"
+	)
+
+	fake := yaziotest.New(t,
+		yaziotest.WithAccount(yaziotest.Account{Username: username, Password: password}),
+		yaziotest.WithProduct(yaziotest.Product{ID: breadID, Name: "Bread", BaseUnit: "g"}),
+	)
+
+	for _, name := range []string{yaziotest.DefaultUsername, username} {
+		assert.NoError(t, fake.Consume(name, yaziotest.ConsumedItem{
+			ID:        uuid.New(),
+			ProductID: breadID,
+			Date:      firstDay.Add(8 * time.Hour),
+			Daytime:   "breakfast",
+			Amount:    30,
+			Quantity:  1,
+		}))
+	}
+
+	var cals []string
+	for _, u := range []*yazio.User{
+		fakeuser.Login(t, fake),
+		fakeuser.LoginAs(t, fake, username, password),
+	} {
+		cals = append(cals, export(t, New(u)))
+	}
+
+	if properties(cals[0], "UID") == properties(cals[1], "UID") {
+		t.Fatalf("accounts share the UID %s", properties(cals[0], "UID"))
+	}
+}
+
+func TestExporter_ExportErrors(t *testing.T) {
+	t.Parallel()
+
+	fake, u := seeded(t)
+	fake.ExpireTokens()
+
+	var buf bytes.Buffer
+	err := New(u).Export(context.Background(), &buf, date.Range{Start: firstDay, End: lastDay})
+	if !errors.Is(err, yazio.ErrExpiredToken) {
+		t.Fatalf("got %v, want %v", err, yazio.ErrExpiredToken)
+	}
+}
+
+func TestFold(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name string
+		line string
+		want string
+	}{
+		{name: "short", line: "SUMMARY:Lunch", want: "SUMMARY:Lunch"},
+		{name: "exactly the limit", line: strings.Repeat("a", 75), want: strings.Repeat("a", 75)},
+		{
+			name: "ascii",
+			line: strings.Repeat("a", 80),
+			want: strings.Repeat("a", 75) + "\r\n " + strings.Repeat("a", 5),
+		},
+		{
+			name: "keeps multi-octet runes whole",
+			line: strings.Repeat("a", 74) + "ção",
+			want: strings.Repeat("a", 74) + "\r\n çã" + "o",
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, fold(tb.line), tb.want)
+		})
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "plain", s: "Lunch", want: "Lunch"},
+		{name: "separators", s: "a,b;c", want: `a\,b\;c`},
+		{name: "backslash", s: `a\b`, want: `a\\b`},
+		{name: "line breaks", s: "a\nb\r\nc", want: `a\nb\nc`},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, escapeText(tb.s), tb.want)
+		})
+	}
+}
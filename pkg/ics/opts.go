@@ -0,0 +1,24 @@
+package ics
+
+import "time"
+
+type Option func(e *Exporter)
+
+// WithName sets the name calendar apps show for
+// the feed. It defaults to "YAZIO".
+func WithName(name string) Option {
+	return func(e *Exporter) {
+		e.name = name
+	}
+}
+
+// WithDuration sets how long after the last entry of a meal
+// its event ends. It defaults to 30 minutes; values below
+// zero are ignored.
+func WithDuration(d time.Duration) Option {
+	return func(e *Exporter) {
+		if d >= 0 {
+			e.duration = d
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package ics
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	lineLimit = 75 // octets, excluding the line break
+	lineBreak = "\r\n"
+)
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", "",
+)
+
+// escapeText escapes s as a TEXT value (RFC 5545, section 3.3.11).
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// calendarWriter writes content lines, keeping
+// the first error so callers check it only once.
+type calendarWriter struct {
+	w   io.Writer
+	err error
+}
+
+// property writes the content line name:value,
+// folded into lines of at most 75 octets.
+func (cw *calendarWriter) property(name, value string) {
+	if cw.err != nil {
+		return
+	}
+	_, cw.err = io.WriteString(cw.w, fold(name+":"+value)+lineBreak)
+}
+
+// fold splits line as described by RFC 5545 (section 3.1),
+// never breaking a multi-octet UTF-8 sequence.
+func fold(line string) string {
+	if len(line) <= lineLimit {
+		return line
+	}
+
+	var (
+		b     strings.Builder
+		width int
+	)
+
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if width+size > lineLimit {
+			b.WriteString(lineBreak + " ")
+			width = 1 // the leading space
+		}
+
+		b.WriteRune(r)
+		width += size
+	}
+
+	return b.String()
+}
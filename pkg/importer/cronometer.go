@@ -0,0 +1,105 @@
+package importer
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+)
+
+const (
+	cronDay    = "day"
+	cronTime   = "time"
+	cronGroup  = "group"
+	cronFood   = "food name"
+	cronAmount = "amount"
+)
+
+// cronNutrients are the nutrient columns of Cronometer, named
+// with their units (e.g. "Vitamin C (mg)"). Vitamin D is
+// exported in IU, which isn't imported.
+var cronNutrients = map[string]nutrientColumn{
+	"energy":                {intake.Energy, unit.Kilocalorie},
+	"alcohol":               {intake.Alcohol, unit.Gram},
+	"water":                 {intake.Water, unit.Gram},
+	"b1 (thiamine)":         {intake.VitaminB1, unit.Milligram},
+	"b2 (riboflavin)":       {intake.VitaminB2, unit.Milligram},
+	"b3 (niacin)":           {intake.VitaminB3, unit.Milligram},
+	"b5 (pantothenic acid)": {intake.VitaminB5, unit.Milligram},
+	"b6 (pyridoxine)":       {intake.VitaminB6, unit.Milligram},
+	"b12 (cobalamin)":       {intake.VitaminB12, unit.Microgram},
+	"biotin":                {intake.VitaminB7, unit.Microgram},
+	"choline":               {intake.MineralCholine, unit.Milligram},
+	"folate":                {intake.VitaminB11, unit.Microgram},
+	"vitamin a":             {intake.VitaminA, unit.Microgram},
+	"vitamin c":             {intake.VitaminC, unit.Milligram},
+	"vitamin d":             {intake.VitaminD, unit.Microgram},
+	"vitamin e":             {intake.VitaminE, unit.Milligram},
+	"vitamin k":             {intake.VitaminK, unit.Microgram},
+	"calcium":               {intake.Calcium, unit.Milligram},
+	"copper":                {intake.MineralCopper, unit.Milligram},
+	"iron":                  {intake.Iron, unit.Milligram},
+	"magnesium":             {intake.MineralMagnesium, unit.Milligram},
+	"manganese":             {intake.MineralManganese, unit.Milligram},
+	"phosphorus":            {intake.MineralPhosphorus, unit.Milligram},
+	"potassium":             {intake.Potassium, unit.Milligram},
+	"selenium":              {intake.MineralSelenium, unit.Microgram},
+	"sodium":                {intake.Sodium, unit.Milligram},
+	"zinc":                  {intake.MineralZinc, unit.Milligram},
+	"carbs":                 {intake.Carb, unit.Gram},
+	"fiber":                 {intake.Fiber, unit.Gram},
+	"sugars":                {intake.Sugar, unit.Gram},
+	"fat":                   {intake.Fat, unit.Gram},
+	"cholesterol":           {intake.Cholesterol, unit.Milligram},
+	"monounsaturated":       {intake.Monounsaturated, unit.Gram},
+	"polyunsaturated":       {intake.Polyunsaturated, unit.Gram},
+	"saturated":             {intake.Saturated, unit.Gram},
+	"trans-fats":            {intake.TransFat, unit.Gram},
+	"protein":               {intake.Protein, unit.Gram},
+}
+
+// cronWeight finds the weight in an amount like "150.00 g"
+// or "1.00 cup - 240 g".
+var cronWeight = regexp.MustCompile(`([0-9]+(?:\.[0-9]+)?)\s*(g|ml)\b`)
+
+// ReadCronometer reads the rows of a Cronometer "Servings"
+// export, without importing them.
+//
+// Each row defines a food with the name of the row, whose nutrients are
+// per 100 base units of the weight found in its amount; when imported,
+// the first row naming a food defines it. Rows without a weight define
+// a food per nutrient totals instead (see [Row.Food]). Groups YAZIO doesn't have
+// (e.g. "Uncategorized") are logged as snacks.
+//
+// On failure the error wraps either:
+//   - [ErrMissingColumn]
+func (im *Importer) ReadCronometer(r io.Reader) ([]Row, []RowError, error) {
+	required := []string{cronDay, cronFood, cronAmount}
+
+	return im.readTracker(r, required, cronNutrients, func(h trackerHeader, record []string) (Row, error) {
+		m := trackerMeal(h.get(record, cronGroup))
+
+		day, err := im.parseDate(h.get(record, cronDay), h.get(record, cronTime), m)
+		if err != nil {
+			return Row{}, err
+		}
+
+		totals, err := h.totals(record)
+		if err != nil {
+			return Row{}, err
+		}
+
+		var (
+			weight float64
+			base   unit.Base
+		)
+		if match := cronWeight.FindStringSubmatch(h.get(record, cronAmount)); match != nil {
+			weight, _ = strconv.ParseFloat(match[1], 64)
+			base = unit.Base(match[2])
+		}
+
+		return trackerRow(day, m, h.get(record, cronFood), totals, weight, base), nil
+	})
+}
@@ -0,0 +1,231 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/meal"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+)
+
+const (
+	colDate     = "date"     // required
+	colTime     = "time"     // optional; defaults by meal
+	colMeal     = "meal"     // required
+	colProduct  = "product"  // required; food ID or name
+	colAmount   = "amount"   // required; base units per serving
+	colServing  = "serving"  // optional; defaults to portion
+	colQuantity = "quantity" // optional; defaults to 1
+	colCategory = "category" // optional; for new products
+	colUnit     = "unit"     // optional; for new products
+)
+
+var (
+	requiredColumns = []string{colDate, colMeal, colProduct, colAmount}
+
+	// nutrientColumns define a new product, per 100 base units.
+	nutrientColumns = map[string]intake.Kind{
+		"energy":  intake.Energy,
+		"carb":    intake.Carb,
+		"fat":     intake.Fat,
+		"protein": intake.Protein,
+	}
+
+	// clockLayouts are tried after the time layout,
+	// as trackers export times in the user's locale.
+	clockLayouts = []string{"15:04:05", "3:04 PM", "3:04PM"}
+
+	// mealTimes are used when a row has no time.
+	mealTimes = map[meal.Time]time.Duration{
+		meal.Breakfast: 8 * time.Hour,
+		meal.Lunch:     12 * time.Hour,
+		meal.Snack:     16 * time.Hour,
+		meal.Dinner:    19 * time.Hour,
+	}
+)
+
+// header maps the (lower-cased) column names to their index.
+type header map[string]int
+
+func (h header) get(record []string, col string) string {
+	i, ok := h[col]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// readCSV reads the rows of r. Rows that can't be parsed are
+// reported as row errors; a malformed header fails the read.
+func (im *Importer) readCSV(r io.Reader) (rows []Row, rowErrs []RowError, err error) {
+	cr := csv.NewReader(r)
+	cr.Comma = im.comma
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	names, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("%w: empty input", ErrMissingColumn)
+		}
+		return nil, nil, err
+	}
+
+	h := make(header, len(names))
+	for i, name := range names {
+		h[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, col := range requiredColumns {
+		if _, ok := h[col]; !ok {
+			return nil, nil, fmt.Errorf("%w: %q", ErrMissingColumn, col)
+		}
+	}
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, rowErrs, nil
+		}
+
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			var pe *csv.ParseError
+			if !errors.As(err, &pe) {
+				return rows, rowErrs, err
+			}
+			rowErrs = append(rowErrs, RowError{Line: pe.Line, Err: fmt.Errorf("%w: %w", ErrInvalidValue, pe.Err)})
+			continue
+		}
+
+		row, err := im.parseRow(h, record)
+		if err != nil {
+			rowErrs = append(rowErrs, RowError{Line: line, Err: err})
+			continue
+		}
+
+		row.Line = line
+		rows = append(rows, row)
+	}
+}
+
+func (im *Importer) parseRow(h header, record []string) (row Row, err error) {
+	if row.Meal, err = meal.Parse(h.get(record, colMeal)); err != nil {
+		return row, fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+
+	if row.Date, err = im.parseDate(h.get(record, colDate), h.get(record, colTime), row.Meal); err != nil {
+		return row, err
+	}
+
+	if row.Product = h.get(record, colProduct); row.Product == "" {
+		return row, fmt.Errorf("%w: empty product", ErrInvalidValue)
+	}
+
+	row.Serving.Kind = food.Portion
+	if s := h.get(record, colServing); s != "" {
+		if row.Serving.Kind, err = food.ParseServingKind(s); err != nil {
+			return row, fmt.Errorf("%w: %w", ErrInvalidValue, err)
+		}
+	}
+
+	if row.Serving.Amount, err = im.parseNumber(h.get(record, colAmount), colAmount); err != nil {
+		return row, err
+	}
+
+	row.Quantity = 1
+	if s := h.get(record, colQuantity); s != "" {
+		if row.Quantity, err = im.parseNumber(s, colQuantity); err != nil {
+			return row, err
+		}
+	}
+
+	return row, im.parseDefinition(h, record, &row)
+}
+
+// parseDefinition reads the optional columns defining a new
+// product. They're either all present or all empty.
+func (im *Importer) parseDefinition(h header, record []string, row *Row) error {
+	nutrients := make(food.Nutrients, len(nutrientColumns))
+	for col, k := range nutrientColumns {
+		s := h.get(record, col)
+		if s == "" {
+			continue
+		}
+
+		v, err := im.parseNumber(s, col)
+		if err != nil {
+			return err
+		}
+		nutrients[k] = v
+	}
+
+	switch len(nutrients) {
+	case 0:
+		return nil
+	case len(nutrientColumns):
+		row.Nutrients = nutrients
+	default:
+		return fmt.Errorf("%w: product defined by energy, carb, fat and protein, got %d of them", ErrInvalidValue, len(nutrients))
+	}
+
+	row.Category = food.Miscellaneous
+	if s := h.get(record, colCategory); s != "" {
+		cat, err := food.ParseCategory(s)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidValue, err)
+		}
+		row.Category = cat
+	}
+
+	row.BaseUnit = unit.Gram
+	if s := h.get(record, colUnit); s != "" {
+		switch b := unit.Base(strings.ToLower(s)); b {
+		case unit.Gram, unit.Milliliter:
+			row.BaseUnit = b
+		default:
+			return fmt.Errorf("%w: unit %q (want g or ml)", ErrInvalidValue, s)
+		}
+	}
+
+	return nil
+}
+
+func (im *Importer) parseDate(day, clock string, m meal.Time) (time.Time, error) {
+	d, err := time.ParseInLocation(im.dateLayout, day, im.loc)
+	if err != nil {
+		return d, fmt.Errorf("%w: date %q", ErrInvalidValue, day)
+	}
+
+	if clock == "" {
+		return d.Add(mealTimes[m]), nil
+	}
+
+	for _, layout := range append([]string{im.timeLayout}, clockLayouts...) {
+		if c, err := time.Parse(layout, strings.ToUpper(clock)); err == nil {
+			return time.Date(d.Year(), d.Month(), d.Day(), c.Hour(), c.Minute(), c.Second(), 0, im.loc), nil
+		}
+	}
+
+	return d, fmt.Errorf("%w: time %q", ErrInvalidValue, clock)
+}
+
+// parseNumber reads a positive number, accepting decimal
+// commas when the delimiter isn't a comma.
+func (im *Importer) parseNumber(s, col string) (float64, error) {
+	if im.comma != ',' {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%w: %s %q", ErrInvalidValue, col, s)
+	}
+	return v, nil
+}
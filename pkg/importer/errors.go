@@ -0,0 +1,28 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrMissingColumn  = errors.New("given import is missing a required column")
+	ErrInvalidValue   = errors.New("given import row has an invalid value")
+	ErrUnknownProduct = errors.New("given import product is unknown")
+	ErrUnknownSource  = errors.New("given import source is unknown")
+)
+
+// RowError is the failure of a single row, which
+// doesn't stop the rows after it from being imported.
+type RowError struct {
+	Line int   // Line is the line of the row in its source.
+	Err  error // Err is why the row wasn't imported.
+}
+
+func (re RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", re.Line, re.Err)
+}
+
+func (re RowError) Unwrap() error {
+	return re.Err
+}
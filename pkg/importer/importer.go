@@ -0,0 +1,394 @@
+// Package importer logs diary history kept elsewhere
+// (e.g. a spreadsheet) into the diary of a YAZIO user.
+//
+// The CSV source has a header naming its columns, in any order:
+//
+//	date,time,meal,product,amount,serving,quantity,energy,carb,fat,protein
+//	2025-04-10,08:15,breakfast,Oat porridge,250,bowl,1,68,12,1.4,2.4
+//	2025-04-10,,snack,0b8f2a64-0d8f-4c36-9a3e-6f7f8c1b2d01,30,,2,,,,
+//
+// Only date, meal, product and amount are required. The product is
+// either a food ID or a name, resolved with [WithFoods] or defined
+// by the energy, carb, fat and protein columns (per 100 base units)
+// and created on first use. The amount is the base units (e.g. grams)
+// of a serving; quantity is how many servings were eaten.
+//
+// Exports of MyFitnessPal and Cronometer are imported with
+// [Importer.ImportFrom], defining a food for what each row sums up to.
+//
+// Entries get IDs derived from their content and the account they're
+// imported into, so re-running an import skips what was already
+// imported instead of duplicating it.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/diary"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/meal"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+	"github.com/controlado/go-yazio/pkg/domain/user"
+	"github.com/controlado/go-yazio/pkg/visibility"
+	"github.com/controlado/go-yazio/pkg/yazio"
+	"github.com/google/uuid"
+)
+
+var (
+	// namespace derives the namespaces of the accounts imported into.
+	namespace = uuid.MustParse("6d1b4f0e-3f6a-4c52-9b8e-2a7d5c9e1f30")
+)
+
+// accountNamespace derives the IDs of the products and entries
+// imported into account. YAZIO IDs are unique across accounts,
+// so the same rows imported into another one get other IDs.
+func accountNamespace(account uuid.UUID) uuid.UUID {
+	return uuid.NewSHA1(namespace, account[:])
+}
+
+// ProductID returns the ID an import into the account
+// (see [user.Data]) gives to the product named name,
+// ignoring case.
+func ProductID(account uuid.UUID, name string) food.ID {
+	return productID(account, foldName(name))
+}
+
+func productID(account uuid.UUID, key string) food.ID {
+	return uuid.NewSHA1(accountNamespace(account), []byte("product:"+key))
+}
+
+func foldName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Row is a diary entry read from a source.
+type Row struct {
+	Line     int          // Line is the position of the row in its source.
+	Date     time.Time    // Date is when the food was consumed.
+	Meal     meal.Time    // Meal is the meal the food is logged to.
+	Product  string       // Product is a food ID or name.
+	Serving  food.Serving // Serving is the serving consumed.
+	Quantity float64      // Quantity is how many servings were consumed.
+
+	// Nutrients, Category and BaseUnit define Product when it's
+	// a name not given by [WithFoods]. Nil Nutrients define nothing.
+	Nutrients food.Nutrients
+	Category  food.Category
+	BaseUnit  unit.Base
+
+	// variant tells apart foods of the same name defined by rows
+	// whose nutrients only hold for their own amount (see trackerRow).
+	variant string
+}
+
+// productKey identifies the product of row: its name, ignoring
+// case, and its variant if any.
+func (row Row) productKey() string {
+	key := foldName(row.Product)
+	if row.variant != "" {
+		key += "|" + row.variant
+	}
+	return key
+}
+
+// Food returns the food defined by row, whose ID is the
+// [ProductID] of its name in the account. Rows of trackers
+// sized without a weight derive it from their nutrients too.
+//
+// On failure the error wraps either:
+//   - [ErrUnknownProduct] if row defines no food
+//   - the errors of [food.New] and [food.Food.Validate]
+func (row Row) Food(account uuid.UUID) (food.Food, error) {
+	if row.Nutrients == nil {
+		return food.Food{}, fmt.Errorf("%w: %q", ErrUnknownProduct, row.Product)
+	}
+
+	f, err := food.New(row.Product, row.Category, row.Nutrients,
+		food.WithID(productID(account, row.productKey())),
+		food.WithBaseUnit(row.BaseUnit),
+		food.WithServing(row.Serving),
+	)
+	if err != nil {
+		return f, err
+	}
+
+	return f, f.Validate()
+}
+
+// Entry returns the diary entry of row, logging the food foodID.
+// Its ID is random; imports derive it from the row instead.
+func (row Row) Entry(foodID food.ID) diary.Entry {
+	return diary.Entry{
+		ID:       uuid.New(),
+		Date:     row.Date,
+		Meal:     row.Meal,
+		FoodID:   foodID,
+		Serving:  row.Serving,
+		Quantity: row.Quantity,
+	}
+}
+
+// Report is the outcome of an import.
+type Report struct {
+	Rows     int           // Rows is how many rows were read.
+	Imported []diary.Entry // Imported are the entries logged (or to be, on dry-run).
+	Skipped  []diary.Entry // Skipped are the entries already in the diary.
+	Created  []food.Food   // Created are the foods added (or to be, on dry-run).
+	Errors   []RowError    // Errors are the rows that weren't imported.
+}
+
+// Err joins the row errors of r, being nil when every row succeeded.
+func (r Report) Err() error {
+	errs := make([]error, len(r.Errors))
+	for i, re := range r.Errors {
+		errs[i] = re
+	}
+	return errors.Join(errs...)
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("%d rows: %d imported, %d skipped, %d failed, %d foods created",
+		r.Rows, len(r.Imported), len(r.Skipped), len(r.Errors), len(r.Created))
+}
+
+// User is the part of a user an [Importer] imports into
+// (usually a *yazio.User).
+type User interface {
+	Data(ctx context.Context) (user.Data, error)
+	AddFood(ctx context.Context, f food.Food, vis visibility.Food) error
+	AddEntry(ctx context.Context, e diary.Entry) error
+	Diary(ctx context.Context, day time.Time) ([]diary.Entry, error)
+}
+
+// Importer logs rows in the diary of a user.
+//
+// Instances of Importer should be created using [New].
+type Importer struct {
+	user       User
+	foods      map[string]food.Food
+	visibility visibility.Food
+	dryRun     bool
+	dateLayout string
+	timeLayout string
+	loc        *time.Location
+	comma      rune
+}
+
+// New returns an [*Importer] into the diary of u (usually a *yazio.User).
+func New(u User, opts ...Option) *Importer {
+	im := &Importer{
+		user:       u,
+		foods:      make(map[string]food.Food),
+		visibility: visibility.PrivateFood,
+		dateLayout: time.DateOnly,
+		timeLayout: "15:04",
+		loc:        time.Local,
+		comma:      ',',
+	}
+
+	for _, opt := range opts {
+		opt(im)
+	}
+
+	return im
+}
+
+// Import reads the CSV r and imports its rows; see the package docs.
+//
+// Rows that fail are reported in [Report.Errors], and don't stop
+// the import. The returned error is only set when the import
+// can't go on (e.g. a missing column or an expired token).
+//
+// On failure the error wraps either:
+//   - [ErrMissingColumn]
+//   - [yazio.ErrExpiredToken]
+func (im *Importer) Import(ctx context.Context, r io.Reader) (Report, error) {
+	return im.ImportFrom(ctx, CSV, r)
+}
+
+// product is a resolved [Row.Product].
+type product struct {
+	food    food.Food
+	pending bool  // pending foods must be added before use.
+	err     error // err is why the product can't be used.
+}
+
+// ImportRows imports rows read by other sources, the same way [Importer.Import] does.
+func (im *Importer) ImportRows(ctx context.Context, rows []Row) (Report, error) {
+	var (
+		rep      = Report{Rows: len(rows)}
+		products = make(map[string]*product)
+		seen     = make(map[string]int)
+		logged   = make(map[string]map[uuid.UUID]bool) // dry-run only
+	)
+
+	data, err := im.user.Data(ctx)
+	if err != nil {
+		return rep, fmt.Errorf("fetching the account imported into: %w", err)
+	}
+
+	for _, row := range rows {
+		err := validate(row)
+
+		var p *product
+		if err == nil {
+			p, err = im.resolve(products, row, data.ID)
+		}
+		if err == nil {
+			err = im.ensure(ctx, p, &rep)
+		}
+		if err != nil {
+			if fatal(ctx, err) {
+				return rep, err
+			}
+			rep.Errors = append(rep.Errors, RowError{Line: row.Line, Err: err})
+			continue
+		}
+
+		e := entryOf(row, p.food.ID, data.ID, seen)
+
+		if im.dryRun {
+			exists, err := im.exists(ctx, logged, e)
+			if err != nil {
+				if fatal(ctx, err) {
+					return rep, err
+				}
+				rep.Errors = append(rep.Errors, RowError{Line: row.Line, Err: err})
+				continue
+			}
+
+			if exists {
+				rep.Skipped = append(rep.Skipped, e)
+			} else {
+				rep.Imported = append(rep.Imported, e)
+			}
+			continue
+		}
+
+		switch err := im.user.AddEntry(ctx, e); {
+		case err == nil:
+			rep.Imported = append(rep.Imported, e)
+		case errors.Is(err, diary.ErrAlreadyExists):
+			rep.Skipped = append(rep.Skipped, e)
+		case fatal(ctx, err):
+			return rep, err
+		default:
+			rep.Errors = append(rep.Errors, RowError{Line: row.Line, Err: err})
+		}
+	}
+
+	return rep, nil
+}
+
+// resolve finds the food row refers to, memoizing it in products.
+func (im *Importer) resolve(products map[string]*product, row Row, account uuid.UUID) (*product, error) {
+	if id, err := uuid.Parse(row.Product); err == nil {
+		return &product{food: food.Food{ID: id}}, nil
+	}
+
+	key := row.productKey()
+	if p, ok := products[key]; ok {
+		return p, p.err
+	}
+
+	p := &product{pending: true}
+	products[key] = p
+
+	if f, ok := im.foods[foldName(row.Product)]; ok {
+		p.food = f
+		return p, nil
+	}
+
+	p.food, p.err = row.Food(account)
+	return p, p.err
+}
+
+// ensure adds p to YAZIO when it's pending.
+func (im *Importer) ensure(ctx context.Context, p *product, rep *Report) error {
+	if !p.pending {
+		return p.err
+	}
+	p.pending = false
+
+	if !im.dryRun {
+		err := im.user.AddFood(ctx, p.food, im.visibility)
+		switch {
+		case errors.Is(err, food.ErrAlreadyExists):
+			return nil
+		case err != nil:
+			if !fatal(ctx, err) {
+				p.err = err
+			}
+			return err
+		}
+	}
+
+	rep.Created = append(rep.Created, p.food)
+	return nil
+}
+
+// exists reports whether e is already in the diary,
+// fetching each day once into logged.
+func (im *Importer) exists(ctx context.Context, logged map[string]map[uuid.UUID]bool, e diary.Entry) (bool, error) {
+	day := e.Date.Format(time.DateOnly)
+
+	ids, ok := logged[day]
+	if !ok {
+		entries, err := im.user.Diary(ctx, e.Date)
+		if err != nil {
+			return false, err
+		}
+
+		ids = make(map[uuid.UUID]bool, len(entries))
+		for _, other := range entries {
+			ids[other.ID] = true
+		}
+		logged[day] = ids
+	}
+
+	exists := ids[e.ID]
+	ids[e.ID] = true
+
+	return exists, nil
+}
+
+func validate(row Row) error {
+	if row.Serving.Amount <= 0 {
+		return fmt.Errorf("%w: amount %g should be positive", ErrInvalidValue, row.Serving.Amount)
+	}
+	if row.Quantity <= 0 {
+		return fmt.Errorf("%w: quantity %g should be positive", ErrInvalidValue, row.Quantity)
+	}
+	return nil
+}
+
+// entryOf returns the entry of row, whose ID is derived from its content
+// and the account. Identical rows are told apart by how many of them
+// were seen before.
+func entryOf(row Row, foodID food.ID, account uuid.UUID, seen map[string]int) diary.Entry {
+	key := fmt.Sprintf("entry:%s|%s|%s|%s|%g|%g",
+		row.Date.UTC().Format(time.RFC3339),
+		row.Meal,
+		foodID,
+		row.Serving.Kind,
+		row.Serving.Amount,
+		row.Quantity,
+	)
+	n := seen[key]
+	seen[key]++
+
+	e := row.Entry(foodID)
+	e.ID = uuid.NewSHA1(accountNamespace(account), fmt.Appendf(nil, "%s#%d", key, n))
+
+	return e
+}
+
+// fatal reports whether err stops the whole import.
+func fatal(ctx context.Context, err error) bool {
+	return errors.Is(err, yazio.ErrExpiredToken) || ctx.Err() != nil
+}
@@ -0,0 +1,257 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/internal/testutil/fakeuser"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/meal"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+	"github.com/controlado/go-yazio/pkg/yazio"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
+	"github.com/google/uuid"
+)
+
+const (
+	sheet = `Date,Time,Meal,Product,Amount,Serving,Quantity,Energy,Carb,Fat,Protein
+2025-04-10,08:15,breakfast,Oat porridge,250,bowl,1,68,12,1.4,2.4
+2025-04-10,,snack,0b8f2a64-0d8f-4c36-9a3e-6f7f8c1b2d01,30,,2,,,,
+2025-04-11,12:30,lunch,oat porridge,250,bowl,1,,,,
+2025-04-11,12:30,lunch,oat porridge,250,bowl,1,,,,
+`
+)
+
+var (
+	knownID = uuid.MustParse("0b8f2a64-0d8f-4c36-9a3e-6f7f8c1b2d01")
+)
+
+// accountID returns the ID of the account of u.
+func accountID(t *testing.T, u *yazio.User) uuid.UUID {
+	t.Helper()
+
+	data, err := u.Data(context.Background())
+	assert.NoError(t, err)
+
+	return data.ID
+}
+
+func TestImporter_Import(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx  = context.Background()
+		fake = yaziotest.New(t)
+		u    = fakeuser.Login(t, fake)
+		im   = New(u, WithLocation(time.UTC))
+	)
+
+	rep, err := im.Import(ctx, strings.NewReader(sheet))
+	assert.NoError(t, err)
+	assert.NoError(t, rep.Err())
+	assert.Equal(t, rep.Rows, 4)
+	assert.Equal(t, len(rep.Imported), 4)
+	assert.Equal(t, len(rep.Created), 1)
+	assert.Equal(t, rep.Created[0].ID, ProductID(accountID(t, u), "OAT PORRIDGE"))
+	assert.Equal(t, rep.Created[0].Nutrients[intake.Energy], 68.0)
+
+	snack := rep.Imported[1]
+	assert.Equal(t, snack.FoodID, knownID)
+	assert.Equal(t, snack.Meal, meal.Snack)
+	assert.Equal(t, snack.Date, time.Date(2025, 4, 10, 16, 0, 0, 0, time.UTC))
+	assert.Equal(t, snack.Serving, food.Serving{Kind: food.Portion, Amount: 30})
+	assert.Equal(t, snack.Quantity, 2.0)
+
+	// identical rows are distinct entries
+	if rep.Imported[2].ID == rep.Imported[3].ID {
+		t.Fatalf("identical rows got the same entry ID %s", rep.Imported[2].ID)
+	}
+
+	assert.Equal(t, len(fake.Products()), 1)
+	assert.Equal(t, len(fake.Consumed(yaziotest.DefaultUsername)), 4)
+
+	t.Run("re-running skips what was imported", func(t *testing.T) {
+		rep, err := im.Import(ctx, strings.NewReader(sheet))
+		assert.NoError(t, err)
+		assert.Equal(t, len(rep.Imported), 0)
+		assert.Equal(t, len(rep.Skipped), 4)
+		assert.Equal(t, len(rep.Created), 0)
+
+		assert.Equal(t, len(fake.Products()), 1)
+		assert.Equal(t, len(fake.Consumed(yaziotest.DefaultUsername)), 4)
+	})
+}
+
+func TestImporter_DryRun(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx  = context.Background()
+		fake = yaziotest.New(t)
+		u    = fakeuser.Login(t, fake)
+	)
+
+	rep, err := New(u, DryRun(), WithLocation(time.UTC)).Import(ctx, strings.NewReader(sheet))
+	assert.NoError(t, err)
+	assert.Equal(t, len(rep.Imported), 4)
+	assert.Equal(t, len(rep.Created), 1)
+	assert.Equal(t, len(fake.Products()), 0)
+	assert.Equal(t, len(fake.Consumed(yaziotest.DefaultUsername)), 0)
+
+	_, err = New(u, WithLocation(time.UTC)).Import(ctx, strings.NewReader(sheet))
+	assert.NoError(t, err)
+
+	rep, err = New(u, DryRun(), WithLocation(time.UTC)).Import(ctx, strings.NewReader(sheet))
+	assert.NoError(t, err)
+	assert.Equal(t, len(rep.Imported), 0)
+	assert.Equal(t, len(rep.Skipped), 4)
+}
+
+func TestImporter_Accounts(t *testing.T) {
+	t.Parallel()
+
+	const (
+		username = "maria@yaziotest.local"
+		password = "yaziotest2"
+	)
+
+	var (
+		ctx  = context.Background()
+		fake = yaziotest.New(t, yaziotest.WithAccount(yaziotest.Account{Username: username, Password: password}))
+	)
+
+	for _, u := range []*yazio.User{
+		fakeuser.Login(t, fake),
+		fakeuser.LoginAs(t, fake, username, password),
+	} {
+		rep, err := New(u, WithLocation(time.UTC)).Import(ctx, strings.NewReader(sheet))
+		assert.NoError(t, err)
+		assert.Equal(t, len(rep.Imported), 4)
+		assert.Equal(t, len(rep.Skipped), 0)
+		assert.Equal(t, len(rep.Created), 1)
+		assert.Equal(t, rep.Created[0].ID, ProductID(accountID(t, u), "oat porridge"))
+	}
+
+	assert.Equal(t, len(fake.Products()), 2)
+	assert.Equal(t, len(fake.Consumed(username)), 4)
+}
+
+func TestImporter_RowErrors(t *testing.T) {
+	t.Parallel()
+
+	const input = `date;meal;product;amount;energy;carb;fat;protein
+10/04/2025;dinner;Bread;40;;;;
+10/04/2025;brunch;Bread;40;;;;
+2025-04-10;dinner;Bread;40;;;;
+10/04/2025;dinner;Cheese;30;350;1,3;;25
+10/04/2025;dinner;Yogurt;0;60;4;3;3,5
+10/04/2025;dinner;Yogurt;125;60;4;3;3,5
+`
+
+	var (
+		fake  = yaziotest.New(t)
+		bread = food.Food{
+			ID:        uuid.New(),
+			Name:      "Bread",
+			BaseUnit:  unit.Gram,
+			Category:  food.Bread,
+			Nutrients: food.Nutrients{intake.Energy: 265, intake.Carb: 49, intake.Fat: 3.2, intake.Protein: 9},
+		}
+		u  = fakeuser.Login(t, fake)
+		im = New(u, WithComma(';'), WithDateLayout("02/01/2006"), WithFoods(bread))
+	)
+
+	rep, err := im.Import(context.Background(), strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, rep.Rows, 6)
+	assert.Equal(t, len(rep.Imported), 2)
+	assert.Equal(t, rep.Imported[0].FoodID, bread.ID)
+	assert.Equal(t, rep.Imported[1].FoodID, ProductID(accountID(t, u), "yogurt"))
+
+	testBlocks := []struct {
+		line int
+		want error
+	}{
+		{line: 3, want: ErrInvalidValue}, // unknown meal
+		{line: 4, want: ErrInvalidValue}, // date layout
+		{line: 5, want: ErrInvalidValue}, // partial definition
+		{line: 6, want: ErrInvalidValue}, // zero amount
+	}
+
+	assert.Equal(t, len(rep.Errors), len(testBlocks))
+	for i, tb := range testBlocks {
+		got := rep.Errors[i]
+		assert.Equal(t, got.Line, tb.line)
+		if !errors.Is(got, tb.want) {
+			t.Fatalf("line %d: got %v, want %v", tb.line, got, tb.want)
+		}
+	}
+
+	if !errors.Is(rep.Err(), ErrInvalidValue) {
+		t.Fatalf("got %v, want %v", rep.Err(), ErrInvalidValue)
+	}
+}
+
+func TestImporter_UnknownProduct(t *testing.T) {
+	t.Parallel()
+
+	const input = "date,meal,product,amount\n2025-04-10,lunch,Mystery stew,300\n"
+
+	rep, err := New(fakeuser.Login(t, yaziotest.New(t))).Import(context.Background(), strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, len(rep.Errors), 1)
+
+	if !errors.Is(rep.Errors[0], ErrUnknownProduct) {
+		t.Fatalf("got %v, want %v", rep.Errors[0], ErrUnknownProduct)
+	}
+}
+
+func TestImporter_Fatal(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name   string
+		input  string
+		expire bool
+		want   error
+	}{
+		{
+			name:  "missing column",
+			input: "date,meal,amount\n2025-04-10,lunch,300\n",
+			want:  ErrMissingColumn,
+		},
+		{
+			name:  "empty input",
+			input: "",
+			want:  ErrMissingColumn,
+		},
+		{
+			name:   "expired token",
+			input:  sheet,
+			expire: true,
+			want:   yazio.ErrExpiredToken,
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := yaziotest.New(t)
+			u := fakeuser.Login(t, fake)
+			if tb.expire {
+				fake.ExpireTokens()
+			}
+
+			_, err := New(u).Import(context.Background(), strings.NewReader(tb.input))
+			if !errors.Is(err, tb.want) {
+				t.Fatalf("got %v, want %v", err, tb.want)
+			}
+		})
+	}
+}
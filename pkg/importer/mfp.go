@@ -0,0 +1,67 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+)
+
+const (
+	mfpDate = "date"
+	mfpMeal = "meal"
+	mfpTime = "time"
+)
+
+// mfpNutrients are the nutrient columns of MyFitnessPal, which only
+// name the unit of some of them. Vitamin A, vitamin C, calcium and
+// iron are in % of daily values, whose references changed over the
+// years, so they're not imported.
+var mfpNutrients = map[string]nutrientColumn{
+	"calories":            {intake.Energy, unit.Kilocalorie},
+	"fat":                 {intake.Fat, unit.Gram},
+	"saturated fat":       {intake.Saturated, unit.Gram},
+	"polyunsaturated fat": {intake.Polyunsaturated, unit.Gram},
+	"monounsaturated fat": {intake.Monounsaturated, unit.Gram},
+	"trans fat":           {intake.TransFat, unit.Gram},
+	"cholesterol":         {intake.Cholesterol, unit.Milligram},
+	"sodium":              {intake.Sodium, unit.Milligram},
+	"potassium":           {intake.Potassium, unit.Milligram},
+	"carbohydrates":       {intake.Carb, unit.Gram},
+	"fiber":               {intake.Fiber, unit.Gram},
+	"sugar":               {intake.Sugar, unit.Gram},
+	"protein":             {intake.Protein, unit.Gram},
+}
+
+// ReadMyFitnessPal reads the rows of a MyFitnessPal "Nutrition Summary"
+// export, without importing them.
+//
+// That export holds the totals of each meal, not the foods in it, so
+// each row becomes a food named after its meal and day (e.g. "MyFitnessPal
+// breakfast 2025-04-10"), logged as a single portion. Custom meals
+// are logged as snacks.
+//
+// On failure the error wraps either:
+//   - [ErrMissingColumn]
+func (im *Importer) ReadMyFitnessPal(r io.Reader) ([]Row, []RowError, error) {
+	required := []string{mfpDate, mfpMeal}
+
+	return im.readTracker(r, required, mfpNutrients, func(h trackerHeader, record []string) (Row, error) {
+		m := trackerMeal(h.get(record, mfpMeal))
+
+		day, err := im.parseDate(h.get(record, mfpDate), h.get(record, mfpTime), m)
+		if err != nil {
+			return Row{}, err
+		}
+
+		totals, err := h.totals(record)
+		if err != nil {
+			return Row{}, err
+		}
+
+		name := fmt.Sprintf("MyFitnessPal %s %s", h.get(record, mfpMeal), day.Format(time.DateOnly))
+		return trackerRow(day, m, name, totals, 0, ""), nil
+	})
+}
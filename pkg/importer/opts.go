@@ -0,0 +1,74 @@
+package importer
+
+import (
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/visibility"
+)
+
+type Option func(im *Importer)
+
+// DryRun resolves and validates every row, reporting what
+// would be imported, without adding foods or entries.
+func DryRun() Option {
+	return func(im *Importer) {
+		im.dryRun = true
+	}
+}
+
+// WithFoods makes fs resolvable by name (ignoring case).
+// Each of them is added with [User.AddFood]
+// the first time a row refers to it, unless it already exists.
+//
+// Give them stable IDs (e.g. [ProductID]), otherwise
+// re-running the import adds them again.
+func WithFoods(fs ...food.Food) Option {
+	return func(im *Importer) {
+		for _, f := range fs {
+			im.foods[foldName(f.Name)] = f
+		}
+	}
+}
+
+// WithVisibility sets the visibility of the foods created
+// by the import. It defaults to [visibility.PrivateFood].
+func WithVisibility(vis visibility.Food) Option {
+	return func(im *Importer) {
+		im.visibility = vis
+	}
+}
+
+// WithDateLayout sets the [time.Layout] of the date
+// column. It defaults to [time.DateOnly].
+func WithDateLayout(layout string) Option {
+	return func(im *Importer) {
+		im.dateLayout = layout
+	}
+}
+
+// WithTimeLayout sets the [time.Layout] of the time
+// column. It defaults to "15:04".
+func WithTimeLayout(layout string) Option {
+	return func(im *Importer) {
+		im.timeLayout = layout
+	}
+}
+
+// WithLocation sets the location dates and times are
+// read in. It defaults to [time.Local].
+func WithLocation(loc *time.Location) Option {
+	return func(im *Importer) {
+		if loc != nil {
+			im.loc = loc
+		}
+	}
+}
+
+// WithComma sets the CSV field delimiter (e.g. ';' for
+// spreadsheets using decimal commas). It defaults to ','.
+func WithComma(r rune) Option {
+	return func(im *Importer) {
+		im.comma = r
+	}
+}
@@ -0,0 +1,80 @@
+package importer
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+const (
+	CSV          Source = "csv"          // CSV is the format described in the package docs.
+	MyFitnessPal Source = "myfitnesspal" // MyFitnessPal is its "Nutrition Summary" export.
+	Cronometer   Source = "cronometer"   // Cronometer is its "Servings" export.
+)
+
+// Source is the format of the history being imported.
+type Source string
+
+func (s Source) String() string {
+	return string(s)
+}
+
+// ParseSource resolves the [Source] named s, ignoring case.
+// "mfp" is accepted for [MyFitnessPal].
+//
+// On failure the error wraps either:
+//   - [ErrUnknownSource]
+func ParseSource(s string) (Source, error) {
+	src := Source(strings.ToLower(strings.TrimSpace(s)))
+	switch src {
+	case CSV, MyFitnessPal, Cronometer:
+		return src, nil
+	case "mfp":
+		return MyFitnessPal, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownSource, s)
+	}
+}
+
+// ImportFrom reads r, exported by src, and imports its rows
+// like [Importer.Import] does.
+//
+// Trackers export the nutrients each entry sums up to, which become
+// foods named after them; see [Importer.ReadMyFitnessPal] and
+// [Importer.ReadCronometer].
+//
+// On failure the error wraps either:
+//   - [ErrUnknownSource]
+//   - [ErrMissingColumn]
+//   - [yazio.ErrExpiredToken]
+func (im *Importer) ImportFrom(ctx context.Context, src Source, r io.Reader) (Report, error) {
+	var read func(io.Reader) ([]Row, []RowError, error)
+
+	switch src {
+	case CSV:
+		read = im.readCSV
+	case MyFitnessPal:
+		read = im.ReadMyFitnessPal
+	case Cronometer:
+		read = im.ReadCronometer
+	default:
+		return Report{}, fmt.Errorf("%w: %q", ErrUnknownSource, src)
+	}
+
+	rows, rowErrs, err := read(r)
+	if err != nil {
+		return Report{}, err
+	}
+
+	rep, err := im.ImportRows(ctx, rows)
+	rep.Rows += len(rowErrs)
+	rep.Errors = append(rowErrs, rep.Errors...)
+	slices.SortStableFunc(rep.Errors, func(a, b RowError) int {
+		return cmp.Compare(a.Line, b.Line)
+	})
+
+	return rep, err
+}
@@ -0,0 +1,222 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/meal"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+)
+
+// nutrientColumn is a column of a tracker export holding
+// the amount of a nutrient, measured in unit.
+type nutrientColumn struct {
+	kind intake.Kind
+	unit unit.Base
+}
+
+// trackerHeader locates the columns of a tracker export.
+type trackerHeader struct {
+	header
+	nutrients map[int]nutrientColumn
+}
+
+// readTracker reads the header of a tracker export, and calls parse for each
+// record. known maps the nutrient column names (lower-cased, without unit)
+// to their kind, and to the unit used when the name has no unit.
+//
+// Columns of unknown names, or whose unit can't be converted into the base
+// unit of their kind (e.g. vitamin D in IU), are ignored.
+func (im *Importer) readTracker(
+	r io.Reader,
+	required []string,
+	known map[string]nutrientColumn,
+	parse func(h trackerHeader, record []string) (Row, error),
+) (rows []Row, rowErrs []RowError, err error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	names, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("%w: empty input", ErrMissingColumn)
+		}
+		return nil, nil, err
+	}
+
+	h := trackerHeader{
+		header:    make(header, len(names)),
+		nutrients: make(map[int]nutrientColumn),
+	}
+	for i, raw := range names {
+		name, u := splitUnit(raw)
+		h.header[name] = i
+
+		col, ok := known[name]
+		if !ok {
+			continue
+		}
+		if u != "" {
+			col.unit = u
+		}
+		if _, err := col.kind.FromUnit(1, col.unit); err == nil {
+			h.nutrients[i] = col
+		}
+	}
+
+	for _, col := range required {
+		if _, ok := h.header[col]; !ok {
+			return nil, nil, fmt.Errorf("%w: %q", ErrMissingColumn, col)
+		}
+	}
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, rowErrs, nil
+		}
+
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			var pe *csv.ParseError
+			if !errors.As(err, &pe) {
+				return rows, rowErrs, err
+			}
+			rowErrs = append(rowErrs, RowError{Line: pe.Line, Err: fmt.Errorf("%w: %w", ErrInvalidValue, pe.Err)})
+			continue
+		}
+
+		row, err := parse(h, record)
+		if err != nil {
+			rowErrs = append(rowErrs, RowError{Line: line, Err: err})
+			continue
+		}
+
+		row.Line = line
+		rows = append(rows, row)
+	}
+}
+
+// totals reads the nutrient columns of record, in the base unit of each kind.
+func (h trackerHeader) totals(record []string) (food.Nutrients, error) {
+	out := make(food.Nutrients, len(h.nutrients))
+	for i, col := range h.nutrients {
+		if i >= len(record) {
+			continue
+		}
+
+		s := strings.ReplaceAll(strings.TrimSpace(record[i]), ",", "")
+		if s == "" {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %q", ErrInvalidValue, col.kind, record[i])
+		}
+
+		if out[col.kind], err = col.kind.FromUnit(v, col.unit); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// splitUnit splits a column name like "Sodium (mg)" into its
+// lower-cased name and unit. Names without a known unit
+// between their last parentheses have no unit.
+func splitUnit(raw string) (string, unit.Base) {
+	name := strings.ToLower(strings.TrimSpace(raw))
+
+	i := strings.LastIndex(name, "(")
+	if i < 0 || !strings.HasSuffix(name, ")") {
+		return name, ""
+	}
+
+	u := strings.TrimSpace(name[i+1 : len(name)-1])
+	switch u {
+	case "µg", "μg", "ug":
+		u = unit.Microgram.String()
+	case "kj":
+		u = unit.Kilojoule.String()
+	}
+
+	if unit.Base(u).Dimension() == unit.UnknownDimension {
+		return name, ""
+	}
+	return strings.TrimSpace(name[:i]), unit.Base(u)
+}
+
+// trackerMeal maps the meals (or groups) of trackers onto [meal.Time].
+// Custom meals YAZIO doesn't have are logged as snacks.
+func trackerMeal(s string) meal.Time {
+	s = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), "s")
+	if t, err := meal.Parse(s); err == nil {
+		return t
+	}
+	return meal.Snack
+}
+
+// trackerRow returns the row of a food named name, whose nutrients
+// sum up to totals. When weight (in base units) is unknown, a portion
+// is sized to hold the macros, but no less than 100 base units; since
+// the nutrients of such a food only hold for the amount of its row,
+// rows of the same name but other totals define other foods.
+func trackerRow(day time.Time, m meal.Time, name string, totals food.Nutrients, weight float64, base unit.Base) Row {
+	for _, k := range []intake.Kind{intake.Energy, intake.Carb, intake.Fat, intake.Protein} {
+		if _, ok := totals[k]; !ok { // required by YAZIO
+			totals[k] = 0
+		}
+	}
+
+	sized := weight <= 0
+	if sized {
+		base = unit.Gram
+		weight = max(100, math.Ceil(totals[intake.Carb]+totals[intake.Fat]+totals[intake.Protein]))
+	}
+
+	per100 := make(food.Nutrients, len(totals))
+	for k, v := range totals {
+		per100[k] = v * 100 / weight
+	}
+
+	var variant string
+	if sized {
+		variant = nutrientsKey(per100)
+	}
+
+	return Row{
+		Date:      day,
+		Meal:      m,
+		Product:   name,
+		Serving:   food.Serving{Kind: food.Portion, Amount: weight},
+		Quantity:  1,
+		Nutrients: per100,
+		Category:  food.Miscellaneous,
+		BaseUnit:  base,
+		variant:   variant,
+	}
+}
+
+// nutrientsKey formats ns in a canonical form (e.g.
+// "energy.energy=52;nutrient.carb=14"), sorted by kind.
+func nutrientsKey(ns food.Nutrients) string {
+	parts := make([]string, 0, len(ns))
+	for k, v := range ns {
+		parts = append(parts, fmt.Sprintf("%s=%.6g", k.ID(), v))
+	}
+	slices.Sort(parts)
+
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,238 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/internal/testutil/fakeuser"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/meal"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
+	"github.com/google/uuid"
+)
+
+const (
+	mfpExport = `Date,Meal,Time,Calories,Fat (g),Saturated Fat,Polyunsaturated Fat,Monounsaturated Fat,Trans Fat,Cholesterol,Sodium (mg),Potassium,Carbohydrates (g),Fiber,Sugar,Protein (g),Vitamin A,Vitamin C,Calcium,Iron,Note
+2025-04-10,Breakfast,8:15 AM,420,12.5,3.1,2,5.2,0,210,480,390,55,6,12,22,10,15,20,18,
+2025-04-10,Snacks,,180,9,1.5,2.5,4,0,0,90,210,20,3,14,4,0,4,2,6,
+2025-04-10,Pre-workout,,,,,,,,,,,,,,,,,,,
+2025-04-11,Dinner,7:40 PM,bad,,,,,,,,,,,,,,,,,
+`
+
+	cronExport = `Day,Time,Group,Food Name,Amount,Energy (kcal),Alcohol (g),Water (g),B12 (Cobalamin) (µg),Vitamin C (mg),Vitamin D (IU),Sodium (mg),Carbs (g),Fiber (g),Sugars (g),Fat (g),Protein (g),Category
+2025-04-10,07:30:00,Breakfast,Greek Yogurt,200.00 g,194,0,162,1.5,0,0,70,7.8,0,7.8,10,18,Dairy
+2025-04-10,,Uncategorized,Red Wine,1.00 glass - 150 ml,125,14,130,0,0,0,6,3.8,0,0.9,0,0.1,Beverages
+2025-04-11,12:00:00,Lunch,Greek Yogurt,100.00 g,97,0,81,0.75,0,0,35,3.9,0,3.9,5,9,Dairy
+`
+)
+
+func TestImporter_ReadMyFitnessPal(t *testing.T) {
+	t.Parallel()
+
+	im := New(nil, WithLocation(time.UTC))
+
+	rows, rowErrs, err := im.ReadMyFitnessPal(strings.NewReader(mfpExport))
+	assert.NoError(t, err)
+	assert.Equal(t, len(rows), 3)
+	assert.Equal(t, len(rowErrs), 1)
+	assert.Equal(t, rowErrs[0].Line, 5)
+
+	breakfast := rows[0]
+	assert.Equal(t, breakfast.Meal, meal.Breakfast)
+	assert.Equal(t, breakfast.Date, time.Date(2025, 4, 10, 8, 15, 0, 0, time.UTC))
+	assert.Equal(t, breakfast.Product, "MyFitnessPal Breakfast 2025-04-10")
+	assert.Equal(t, breakfast.Serving, food.Serving{Kind: food.Portion, Amount: 100})
+	assert.Equal(t, breakfast.Nutrients[intake.Energy], 420.0)
+	assert.Equal(t, breakfast.Nutrients[intake.Sodium], 480000.0)      // mcg
+	assert.Equal(t, breakfast.Nutrients[intake.Cholesterol], 210000.0) // mcg
+	if _, ok := breakfast.Nutrients[intake.VitaminC]; ok {
+		t.Fatal("vitamin C in % of daily value should be ignored")
+	}
+
+	snack := rows[1]
+	assert.Equal(t, snack.Meal, meal.Snack)
+	assert.Equal(t, snack.Date, time.Date(2025, 4, 10, 16, 0, 0, 0, time.UTC))
+
+	custom := rows[2]
+	assert.Equal(t, custom.Meal, meal.Snack)
+	assert.Equal(t, custom.Nutrients[intake.Energy], 0.0)
+
+	_, err = breakfast.Food(uuid.New())
+	assert.NoError(t, err)
+}
+
+func TestImporter_ReadCronometer(t *testing.T) {
+	t.Parallel()
+
+	im := New(nil, WithLocation(time.UTC))
+
+	rows, rowErrs, err := im.ReadCronometer(strings.NewReader(cronExport))
+	assert.NoError(t, err)
+	assert.Equal(t, len(rowErrs), 0)
+	assert.Equal(t, len(rows), 3)
+
+	yogurt := rows[0]
+	assert.Equal(t, yogurt.Meal, meal.Breakfast)
+	assert.Equal(t, yogurt.Date, time.Date(2025, 4, 10, 7, 30, 0, 0, time.UTC))
+	assert.Equal(t, yogurt.BaseUnit, unit.Gram)
+	assert.Equal(t, yogurt.Serving, food.Serving{Kind: food.Portion, Amount: 200})
+	assert.Equal(t, yogurt.Nutrients[intake.Energy], 97.0)
+	assert.Equal(t, yogurt.Nutrients[intake.Protein], 9.0)
+	assert.Equal(t, yogurt.Nutrients[intake.VitaminB12], 0.75)
+	assert.Equal(t, yogurt.Nutrients[intake.Water], 81.0) // ml
+	if _, ok := yogurt.Nutrients[intake.VitaminD]; ok {
+		t.Fatal("vitamin D in IU should be ignored")
+	}
+
+	wine := rows[1]
+	assert.Equal(t, wine.Meal, meal.Snack)
+	assert.Equal(t, wine.BaseUnit, unit.Milliliter)
+	assert.Equal(t, wine.Serving.Amount, 150.0)
+
+	alcohol := wine.Nutrients[intake.Alcohol] * wine.Serving.Amount / 100
+	if math.Abs(alcohol-14/intake.AlcoholDensity) > 1e-9 {
+		t.Fatalf("got %gml of alcohol, want %gml", alcohol, 14/intake.AlcoholDensity)
+	}
+
+	account := uuid.New()
+	f, err := wine.Food(account)
+	assert.NoError(t, err)
+	assert.Equal(t, f.ID, ProductID(account, "red wine"))
+}
+
+func TestImporter_ImportFrom(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name         string
+		source       Source
+		input        string
+		wantImported int
+		wantCreated  int
+		wantErrors   int
+	}{
+		{name: "myfitnesspal", source: MyFitnessPal, input: mfpExport, wantImported: 3, wantCreated: 3, wantErrors: 1},
+		{name: "cronometer", source: Cronometer, input: cronExport, wantImported: 3, wantCreated: 2},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				ctx  = context.Background()
+				fake = yaziotest.New(t)
+				im   = New(fakeuser.Login(t, fake), WithLocation(time.UTC))
+			)
+
+			rep, err := im.ImportFrom(ctx, tb.source, strings.NewReader(tb.input))
+			assert.NoError(t, err)
+			assert.Equal(t, len(rep.Imported), tb.wantImported)
+			assert.Equal(t, len(rep.Created), tb.wantCreated)
+			assert.Equal(t, len(rep.Errors), tb.wantErrors)
+			assert.Equal(t, len(fake.Products()), tb.wantCreated)
+			assert.Equal(t, len(fake.Consumed(yaziotest.DefaultUsername)), tb.wantImported)
+
+			rep, err = im.ImportFrom(ctx, tb.source, strings.NewReader(tb.input))
+			assert.NoError(t, err)
+			assert.Equal(t, len(rep.Skipped), tb.wantImported)
+			assert.Equal(t, len(fake.Consumed(yaziotest.DefaultUsername)), tb.wantImported)
+		})
+	}
+}
+
+func TestImporter_ImportFrom_UnknownWeight(t *testing.T) {
+	t.Parallel()
+
+	const input = `Day,Group,Food Name,Amount,Energy (kcal),Carbs (g),Fat (g),Protein (g)
+2025-04-10,Breakfast,Apple,1.00 medium,95,25,0.3,0.5
+2025-04-10,Snacks,Apple,2.00 medium,190,50,0.6,1
+2025-04-11,Breakfast,Apple,1.00 medium,95,25,0.3,0.5
+`
+
+	var (
+		fake = yaziotest.New(t)
+		im   = New(fakeuser.Login(t, fake), WithLocation(time.UTC))
+	)
+
+	rep, err := im.ImportFrom(context.Background(), Cronometer, strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, len(rep.Imported), 3)
+	assert.Equal(t, len(rep.Created), 2)
+	assert.Equal(t, len(fake.Products()), 2)
+
+	one, two := rep.Created[0], rep.Created[1]
+	assert.Equal(t, one.Name, two.Name)
+	assert.Equal(t, one.Nutrients[intake.Energy], 95.0)
+	assert.Equal(t, two.Nutrients[intake.Energy], 190.0)
+
+	assert.Equal(t, rep.Imported[0].FoodID, one.ID)
+	assert.Equal(t, rep.Imported[1].FoodID, two.ID)
+	assert.Equal(t, rep.Imported[2].FoodID, one.ID)
+}
+
+func TestParseSource(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name    string
+		input   string
+		want    Source
+		wantErr bool
+	}{
+		{name: "csv", input: "csv", want: CSV},
+		{name: "alias", input: "MFP", want: MyFitnessPal},
+		{name: "cronometer", input: "Cronometer", want: Cronometer},
+		{name: "unknown", input: "loseit", wantErr: true},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSource(tb.input)
+			if tb.wantErr {
+				if !errors.Is(err, ErrUnknownSource) {
+					t.Fatalf("got %v, want %v", err, ErrUnknownSource)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, got, tb.want)
+		})
+	}
+}
+
+func TestSplitUnit(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		input    string
+		wantName string
+		wantUnit unit.Base
+	}{
+		{input: "Fat (g)", wantName: "fat", wantUnit: unit.Gram},
+		{input: "B12 (Cobalamin) (µg)", wantName: "b12 (cobalamin)", wantUnit: unit.Microgram},
+		{input: "B1 (Thiamine)", wantName: "b1 (thiamine)"},
+		{input: "Vitamin D (IU)", wantName: "vitamin d (iu)"},
+		{input: " Calories ", wantName: "calories"},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.input, func(t *testing.T) {
+			t.Parallel()
+
+			name, u := splitUnit(tb.input)
+			assert.Equal(t, name, tb.wantName)
+			assert.Equal(t, u, tb.wantUnit)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// Package metrics exposes the nutrition data of a YAZIO user, and
+// the requests of the client, in the Prometheus text exposition format:
+//
+//	rq := metrics.NewRequester(http.DefaultClient)
+//	api, err := yazio.New(yazio.WithRequester(rq))
+//	...
+//	p := metrics.NewPoller(userFunc, metrics.WithKinds(intake.Water))
+//	go p.Run(ctx)
+//
+//	http.Handle("/metrics", metrics.Handler(p, rq))
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	gauge     = "gauge"
+	counter   = "counter"
+	histogram = "histogram"
+
+	contentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// Collector is a source of metrics, like a [*Poller] or a [*Requester].
+type Collector interface {
+	collect() []family
+}
+
+// family is a metric with every one of its samples.
+type family struct {
+	name    string
+	help    string
+	kind    string // kind is the TYPE: gauge, counter or histogram.
+	samples []sample
+}
+
+// sample is a value of a family. Its suffix is appended to the
+// family name (e.g. "_bucket" for histograms).
+type sample struct {
+	suffix string
+	labels []label
+	value  float64
+}
+
+type label struct {
+	name, value string
+}
+
+// Handler serves the metrics of cs in the Prometheus
+// text exposition format, sorted by name.
+func Handler(cs ...Collector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_ = Write(w, cs...)
+	})
+}
+
+// Write writes the metrics of cs to w in the
+// Prometheus text exposition format, sorted by name.
+func Write(w io.Writer, cs ...Collector) error {
+	var families []family
+	for _, c := range cs {
+		families = append(families, c.collect()...)
+	}
+
+	slices.SortStableFunc(families, func(a, b family) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		writeFamily(bw, f)
+	}
+	return bw.Flush()
+}
+
+func writeFamily(w *bufio.Writer, f family) {
+	w.WriteString("# HELP " + f.name + " " + escapeHelp(f.help) + "\n")
+	w.WriteString("# TYPE " + f.name + " " + f.kind + "\n")
+
+	for _, s := range f.samples {
+		w.WriteString(f.name + s.suffix)
+
+		if len(s.labels) > 0 {
+			w.WriteByte('{')
+			for i, l := range s.labels {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				w.WriteString(l.name + `="` + escapeLabel(l.value) + `"`)
+			}
+			w.WriteByte('}')
+		}
+
+		w.WriteString(" " + formatValue(s.value) + "\n")
+	}
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+)
+
+// collectorFunc adapts a function to a [Collector].
+type collectorFunc func() []family
+
+func (fn collectorFunc) collect() []family {
+	return fn()
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	var (
+		b = collectorFunc(func() []family {
+			return []family{{
+				name: "b_total",
+				help: "Second,\nwith a \\ line.",
+				kind: counter,
+				samples: []sample{
+					{labels: []label{{"path", `C:\"x"`}}, value: 3},
+					{labels: []label{{"path", "new\nline"}}, value: math.Inf(1)},
+				},
+			}}
+		})
+		a = collectorFunc(func() []family {
+			return []family{
+				{name: "a", help: "First.", kind: gauge, samples: []sample{{value: 0.25}}},
+				{name: "c", help: "Empty.", kind: gauge},
+			}
+		})
+		buf bytes.Buffer
+	)
+
+	assert.NoError(t, Write(&buf, b, a))
+	assert.Equal(t, buf.String(), `# HELP a First.
+# TYPE a gauge
+a 0.25
+# HELP b_total Second,\nwith a \\ line.
+# TYPE b_total counter
+b_total{path="C:\\\"x\""} 3
+b_total{path="new\nline"} +Inf
+# HELP c Empty.
+# TYPE c gauge
+`)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rec = httptest.NewRecorder()
+		c   = collectorFunc(func() []family {
+			return []family{{name: "up", help: "Up.", kind: gauge, samples: []sample{{value: 1}}}}
+		})
+	)
+
+	Handler(c).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, rec.Header().Get("Content-Type"), contentType)
+	assert.Equal(t, rec.Body.String(), "# HELP up Up.\n# TYPE up gauge\nup 1\n")
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+)
+
+type PollerOption func(p *Poller)
+
+// WithKinds also polls the intakes of ks, exposed
+// as yazio_daily_intake and yazio_average_intake.
+func WithKinds(ks ...intake.Kind) PollerOption {
+	return func(p *Poller) {
+		p.kinds = append(p.kinds, ks...)
+	}
+}
+
+// WithDays sets how many days, up to today, are polled.
+// Values below 1 are ignored.
+func WithDays(days int) PollerOption {
+	return func(p *Poller) {
+		if days > 0 {
+			p.days = days
+		}
+	}
+}
+
+// WithInterval sets the time between polls of [Poller.Run].
+// Values below 1 are ignored.
+func WithInterval(d time.Duration) PollerOption {
+	return func(p *Poller) {
+		if d > 0 {
+			p.interval = d
+		}
+	}
+}
+
+// WithLocation sets the time zone of days. It defaults to [time.Local].
+func WithLocation(loc *time.Location) PollerOption {
+	return func(p *Poller) {
+		p.loc = loc
+	}
+}
+
+// WithErrorHandler makes [Poller.Run] hand failed polls to fn
+// (e.g. to log them).
+func WithErrorHandler(fn func(error)) PollerOption {
+	return func(p *Poller) {
+		p.onError = fn
+	}
+}
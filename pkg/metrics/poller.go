@@ -0,0 +1,293 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/yazio"
+)
+
+const (
+	defaultDays     = 7
+	defaultInterval = 15 * time.Minute
+
+	layoutDay = "2006-01-02"
+)
+
+// User is the part of a user a [Poller] reads
+// (usually a *yazio.User).
+type User interface {
+	Macros(ctx context.Context, r date.Range) (intake.MacrosRange, error)
+	Intakes(ctx context.Context, kinds []intake.Kind, r date.Range) (intake.Matrix, error)
+}
+
+// UserFunc returns the user a [Poller] reads. It's called before
+// every poll, so stored sessions can be resumed (and refreshed).
+type UserFunc func(ctx context.Context) (User, error)
+
+// Poller periodically fetches the macros (and the intakes of some
+// kinds) of the last days of a user, exposing them as gauges:
+//
+//	p := metrics.NewPoller(userFunc, metrics.WithKinds(intake.Water))
+//	go p.Run(ctx)
+//	http.Handle("/metrics", metrics.Handler(p))
+//
+// Between polls the values of the last successful one are kept.
+//
+// Instances of Poller should be created using [NewPoller].
+type Poller struct {
+	user     UserFunc
+	kinds    []intake.Kind
+	days     int
+	interval time.Duration
+	loc      *time.Location
+	onError  func(error)
+	now      func() time.Time
+
+	mu          sync.Mutex
+	macros      intake.MacrosRange
+	series      map[intake.Kind]intake.SingleRange
+	polls       map[string]float64 // by result
+	lastPoll    time.Time
+	lastSuccess time.Time
+	duration    time.Duration
+}
+
+// NewPoller returns a [*Poller] of the user returned by user.
+// By default it polls the macros of the last 7 days
+// every 15 minutes; see [PollerOption].
+func NewPoller(user UserFunc, opts ...PollerOption) *Poller {
+	p := &Poller{
+		user:     user,
+		days:     defaultDays,
+		interval: defaultInterval,
+		loc:      time.Local,
+		onError:  func(error) {},
+		now:      time.Now,
+		series:   make(map[intake.Kind]intake.SingleRange),
+		polls:    make(map[string]float64),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Run polls right away and then every interval, until ctx is done.
+// Failed polls are counted (and handed to [WithErrorHandler]),
+// without stopping it.
+func (p *Poller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Poll(ctx); err != nil && ctx.Err() == nil {
+			p.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll fetches the last days once. What's fetched replaces the
+// previous values even when something else fails, like some of
+// the kinds.
+//
+// On failure the error wraps either:
+//   - The errors of the [UserFunc]
+//   - The errors of the user methods (e.g. yazio.ErrExpiredToken)
+func (p *Poller) Poll(ctx context.Context) error {
+	start := p.now()
+
+	err := p.poll(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.lastPoll = start
+	p.duration = p.now().Sub(start)
+	if err != nil {
+		p.polls["failure"]++
+		return err
+	}
+
+	p.polls["success"]++
+	p.lastSuccess = start
+	return nil
+}
+
+func (p *Poller) poll(ctx context.Context) error {
+	u, err := p.user(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		now   = p.now().In(p.loc)
+		today = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, p.loc)
+		r     = date.Range{Start: today.AddDate(0, 0, 1-p.days), End: today}
+		errs  []error
+	)
+
+	mr, err := u.Macros(ctx, r)
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		p.mu.Lock()
+		p.macros = mr
+		p.mu.Unlock()
+	}
+
+	if len(p.kinds) == 0 {
+		return errors.Join(errs...)
+	}
+
+	m, err := u.Intakes(ctx, p.kinds, r)
+
+	var ie *yazio.IntakesError
+	switch {
+	case err == nil:
+	case errors.As(err, &ie):
+		errs = append(errs, err)
+	default:
+		return errors.Join(append(errs, err)...)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, k := range p.kinds {
+		if ie != nil && slices.ContainsFunc(ie.Failed, func(ke yazio.KindError) bool { return ke.Kind == k }) {
+			continue
+		}
+		p.series[k] = m.Series(k)
+	}
+
+	return errors.Join(errs...)
+}
+
+// macroGauges are the gauges of [intake.Macros], per day
+// and on average.
+var macroGauges = []struct {
+	name  string
+	help  string
+	value func(m intake.Macros) float64
+}{
+	{"energy_kcal", "energy (kcal)", func(m intake.Macros) float64 { return m.Energy }},
+	{"carb_grams", "carbohydrate (g)", func(m intake.Macros) float64 { return m.Carb }},
+	{"fat_grams", "fat (g)", func(m intake.Macros) float64 { return m.Fat }},
+	{"protein_grams", "protein (g)", func(m intake.Macros) float64 { return m.Protein }},
+}
+
+func (p *Poller) collect() []family {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var (
+		families []family
+		average  = p.macros.Average()
+	)
+
+	for _, g := range macroGauges {
+		daily := family{
+			name: "yazio_daily_" + g.name,
+			help: "Daily " + g.help + " logged, by date.",
+			kind: gauge,
+		}
+		for _, m := range p.macros {
+			daily.samples = append(daily.samples, sample{
+				labels: []label{{"date", m.Date.In(p.loc).Format(layoutDay)}},
+				value:  g.value(m),
+			})
+		}
+
+		avg := family{
+			name: "yazio_average_" + g.name,
+			help: "Average daily " + g.help + " of the polled days with data.",
+			kind: gauge,
+		}
+		if average.DaysLength > 0 {
+			avg.samples = []sample{{value: g.value(intake.Macros{
+				Energy:  average.Energy,
+				Carb:    average.Carb,
+				Fat:     average.Fat,
+				Protein: average.Protein,
+			})}}
+		}
+
+		families = append(families, daily, avg)
+	}
+
+	daily := family{
+		name: "yazio_daily_intake",
+		help: "Daily intake of a nutrient, by kind, unit and date.",
+		kind: gauge,
+	}
+	avg := family{
+		name: "yazio_average_intake",
+		help: "Average daily intake of a nutrient of the polled days with data, by kind and unit.",
+		kind: gauge,
+	}
+	for _, k := range p.kinds {
+		sr, ok := p.series[k]
+		if !ok {
+			continue
+		}
+
+		labels := []label{{"kind", k.ID()}, {"unit", k.Unit()}}
+		for _, s := range sr {
+			daily.samples = append(daily.samples, sample{
+				labels: append(slices.Clip(labels), label{"date", s.Date.In(p.loc).Format(layoutDay)}),
+				value:  s.Value,
+			})
+		}
+		if sa := sr.Average(); sa.DaysLength > 0 {
+			avg.samples = append(avg.samples, sample{labels: labels, value: sa.Average})
+		}
+	}
+	families = append(families, daily, avg)
+
+	polls := family{
+		name: "yazio_polls_total",
+		help: "Polls of the user data, by result.",
+		kind: counter,
+	}
+	for _, result := range []string{"success", "failure"} {
+		polls.samples = append(polls.samples, sample{
+			labels: []label{{"result", result}},
+			value:  p.polls[result],
+		})
+	}
+
+	families = append(families, polls,
+		timestampGauge("yazio_last_poll_timestamp_seconds", "When the last poll started.", p.lastPoll),
+		timestampGauge("yazio_last_successful_poll_timestamp_seconds", "When the last successful poll started.", p.lastSuccess),
+		family{
+			name:    "yazio_poll_duration_seconds",
+			help:    "How long the last poll took.",
+			kind:    gauge,
+			samples: []sample{{value: p.duration.Seconds()}},
+		},
+	)
+
+	return families
+}
+
+func timestampGauge(name, help string, t time.Time) family {
+	f := family{name: name, help: help, kind: gauge}
+	if !t.IsZero() {
+		f.samples = []sample{{value: float64(t.UnixNano()) / 1e9}}
+	}
+	return f
+}
@@ -0,0 +1,181 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/internal/testutil/fakeuser"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/yazio"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
+	"github.com/google/uuid"
+)
+
+var (
+	firstDay = time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)
+	lastDay  = firstDay.AddDate(0, 0, 2)
+
+	productID = uuid.MustParse("0b8f2a64-0d8f-4c36-9a3e-6f7f8c1b2d01")
+)
+
+// seeded returns a fake whose diary holds a glass of milk on
+// the first day and two on the last, leaving the middle day empty.
+func seeded(t *testing.T) *yaziotest.Server {
+	t.Helper()
+
+	fake := yaziotest.New(t,
+		yaziotest.WithProduct(yaziotest.Product{
+			ID:       productID,
+			Name:     "Milk",
+			BaseUnit: "ml",
+			Nutrients: map[string]float64{
+				"energy.energy":    64,
+				"nutrient.carb":    4.8,
+				"nutrient.fat":     3.6,
+				"nutrient.protein": 3.2,
+				"nutrient.water":   88,
+			},
+		}),
+	)
+
+	for _, ci := range []yaziotest.ConsumedItem{
+		{Date: firstDay.Add(8 * time.Hour), Daytime: "breakfast", Quantity: 1},
+		{Date: lastDay.Add(8 * time.Hour), Daytime: "breakfast", Quantity: 2},
+	} {
+		ci.ID, ci.ProductID, ci.Serving, ci.Amount = uuid.New(), productID, "glass", 250
+		assert.NoError(t, fake.Consume(yaziotest.DefaultUsername, ci))
+	}
+
+	return fake
+}
+
+func login(t *testing.T, fake *yaziotest.Server) UserFunc {
+	t.Helper()
+
+	u := fakeuser.Login(t, fake)
+
+	return func(context.Context) (User, error) { return u, nil }
+}
+
+func newTestPoller(user UserFunc, opts ...PollerOption) *Poller {
+	p := NewPoller(user, append([]PollerOption{WithDays(3), WithLocation(time.UTC)}, opts...)...)
+	p.now = func() time.Time { return lastDay.Add(12 * time.Hour) }
+	return p
+}
+
+func written(t *testing.T, c Collector) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, c))
+	return buf.String()
+}
+
+func assertLines(t *testing.T, out string, lines ...string) {
+	t.Helper()
+
+	for _, line := range lines {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, out)
+		}
+	}
+}
+
+func TestPoller_Poll(t *testing.T) {
+	t.Parallel()
+
+	p := newTestPoller(login(t, seeded(t)), WithKinds(intake.Water, intake.VitaminC))
+	assert.NoError(t, p.Poll(context.Background()))
+
+	out := written(t, p)
+	assertLines(t, out,
+		`yazio_daily_energy_kcal{date="2025-04-10"} 160`,
+		`yazio_daily_energy_kcal{date="2025-04-12"} 320`,
+		`yazio_daily_protein_grams{date="2025-04-12"} 16`,
+		`yazio_average_energy_kcal 240`,
+		`yazio_daily_intake{kind="nutrient.water",unit="ml",date="2025-04-10"} 220`,
+		`yazio_average_intake{kind="nutrient.water",unit="ml"} 330`,
+		`yazio_polls_total{result="success"} 1`,
+		`yazio_polls_total{result="failure"} 0`,
+		"# TYPE yazio_polls_total counter",
+	)
+
+	for _, unwanted := range []string{`date="2025-04-11"`, `kind="vitamin.c"`} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("unexpected %q in:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestPoller_PollFailure(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		fake    = seeded(t)
+		user    = login(t, fake)
+		failing = errors.New("no session")
+		fail    bool
+	)
+
+	p := newTestPoller(func(ctx context.Context) (User, error) {
+		if fail {
+			return nil, failing
+		}
+		return user(ctx)
+	})
+
+	assert.NoError(t, p.Poll(ctx))
+
+	fail = true
+	if err := p.Poll(ctx); !errors.Is(err, failing) {
+		t.Fatalf("got %v, want %v", err, failing)
+	}
+
+	fail = false
+	fake.ExpireTokens()
+	if err := p.Poll(ctx); !errors.Is(err, yazio.ErrExpiredToken) {
+		t.Fatalf("got %v, want %v", err, yazio.ErrExpiredToken)
+	}
+
+	assertLines(t, written(t, p),
+		`yazio_daily_energy_kcal{date="2025-04-12"} 320`, // kept from the first poll
+		`yazio_polls_total{result="success"} 1`,
+		`yazio_polls_total{result="failure"} 2`,
+		`yazio_last_successful_poll_timestamp_seconds 1.7444592e+09`,
+	)
+}
+
+func TestPoller_Run(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		errs        = make(chan error, 1)
+		p           = newTestPoller(
+			func(context.Context) (User, error) { return nil, errors.New("boom") },
+			WithInterval(time.Millisecond),
+			WithErrorHandler(func(err error) {
+				select {
+				case errs <- err:
+				default:
+				}
+			}),
+		)
+	)
+
+	done := make(chan error)
+	go func() { done <- p.Run(ctx) }()
+
+	assert.Equal(t, (<-errs).Error(), "boom")
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want %v", err, context.Canceled)
+	}
+}
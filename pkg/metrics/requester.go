@@ -0,0 +1,229 @@
+package metrics
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds (in seconds) of the
+// request latency histogram of a [Requester].
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// tokenEndpoint is where YAZIO issues tokens,
+// either logging in or refreshing.
+const tokenEndpoint = "/oauth/token"
+
+// Requester wraps the requester of a yazio.API (see
+// yazio.WithRequester), counting its requests per endpoint
+// and status, their latency and the tokens it asked for:
+//
+//	rq := metrics.NewRequester(http.DefaultClient)
+//	api, err := yazio.New(yazio.WithRequester(rq))
+//
+// Instances of Requester should be created using [NewRequester].
+type Requester struct {
+	next interface {
+		Do(*http.Request) (*http.Response, error)
+	}
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[requestKey]float64
+	latencies map[endpointKey]*latency
+	tokens    map[string]float64 // by grant type
+}
+
+type (
+	endpointKey struct {
+		method   string
+		endpoint string
+	}
+	requestKey struct {
+		endpointKey
+		code string
+	}
+	latency struct {
+		counts []float64 // per bucket, not cumulative
+		count  float64
+		sum    float64
+	}
+)
+
+// NewRequester wraps next (usually an [*http.Client]). Its latency
+// histogram uses buckets, or [DefaultBuckets] when none are given.
+func NewRequester(next interface {
+	Do(*http.Request) (*http.Response, error)
+}, buckets ...float64) *Requester {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	return &Requester{
+		next:      next,
+		buckets:   slices.Sorted(slices.Values(buckets)),
+		requests:  make(map[requestKey]float64),
+		latencies: make(map[endpointKey]*latency),
+		tokens:    make(map[string]float64),
+	}
+}
+
+// Do sends req through the wrapped requester, recording it.
+// Transport failures are counted with the code "error".
+func (rq *Requester) Do(req *http.Request) (*http.Response, error) {
+	var (
+		key       = endpointKey{method: req.Method, endpoint: endpointOf(req.URL.Path)}
+		grantType = grantTypeOf(req)
+		start     = time.Now()
+	)
+
+	resp, err := rq.next.Do(req)
+	elapsed := time.Since(start).Seconds()
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	rq.requests[requestKey{key, code}]++
+
+	l, ok := rq.latencies[key]
+	if !ok {
+		l = &latency{counts: make([]float64, len(rq.buckets))}
+		rq.latencies[key] = l
+	}
+	l.count++
+	l.sum += elapsed
+	if i, _ := slices.BinarySearch(rq.buckets, elapsed); i < len(l.counts) {
+		l.counts[i]++
+	}
+
+	if grantType != "" && err == nil && resp.StatusCode < http.StatusBadRequest {
+		rq.tokens[grantType]++
+	}
+
+	return resp, err
+}
+
+var idSegment = regexp.MustCompile(`^([0-9]+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$`)
+
+// endpointOf replaces the IDs of path with "{id}", so
+// requests for different resources share an endpoint.
+func endpointOf(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if idSegment.MatchString(s) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// grantTypeOf returns the grant_type of a token request
+// (e.g. "refresh_token"), leaving its body unread.
+func grantTypeOf(req *http.Request) string {
+	if !strings.HasSuffix(req.URL.Path, tokenEndpoint) || req.GetBody == nil {
+		return ""
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	var payload struct {
+		GrantType string `json:"grant_type"`
+	}
+	if err := json.NewDecoder(io.LimitReader(body, 1<<16)).Decode(&payload); err != nil {
+		return ""
+	}
+
+	return payload.GrantType
+}
+
+func (rq *Requester) collect() []family {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	requests := family{
+		name: "yazio_client_requests_total",
+		help: "Requests sent to YAZIO, by endpoint and status code.",
+		kind: counter,
+	}
+	for _, k := range sortedKeys(rq.requests, func(k requestKey) string {
+		return k.endpoint + " " + k.method + " " + k.code
+	}) {
+		requests.samples = append(requests.samples, sample{
+			labels: []label{{"endpoint", k.endpoint}, {"method", k.method}, {"code", k.code}},
+			value:  rq.requests[k],
+		})
+	}
+
+	durations := family{
+		name: "yazio_client_request_duration_seconds",
+		help: "Latency of the requests sent to YAZIO, by endpoint.",
+		kind: histogram,
+	}
+	for _, k := range sortedKeys(rq.latencies, func(k endpointKey) string {
+		return k.endpoint + " " + k.method
+	}) {
+		var (
+			l          = rq.latencies[k]
+			labels     = []label{{"endpoint", k.endpoint}, {"method", k.method}}
+			cumulative float64
+		)
+
+		for i, le := range rq.buckets {
+			cumulative += l.counts[i]
+			durations.samples = append(durations.samples, sample{
+				suffix: "_bucket",
+				labels: append(slices.Clip(labels), label{"le", formatValue(le)}),
+				value:  cumulative,
+			})
+		}
+
+		durations.samples = append(durations.samples,
+			sample{suffix: "_bucket", labels: append(slices.Clip(labels), label{"le", "+Inf"}), value: l.count},
+			sample{suffix: "_sum", labels: labels, value: l.sum},
+			sample{suffix: "_count", labels: labels, value: l.count},
+		)
+	}
+
+	tokens := family{
+		name: "yazio_client_tokens_total",
+		help: "Tokens issued by YAZIO, by grant type (password logins or refreshes).",
+		kind: counter,
+	}
+	for _, grantType := range sortedKeys(rq.tokens, func(g string) string { return g }) {
+		tokens.samples = append(tokens.samples, sample{
+			labels: []label{{"grant_type", grantType}},
+			value:  rq.tokens[grantType],
+		})
+	}
+
+	return []family{requests, durations, tokens}
+}
+
+// sortedKeys returns the keys of m, sorted by their name.
+func sortedKeys[K comparable, V any](m map[K]V, name func(K) string) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.SortFunc(keys, func(a, b K) int {
+		return strings.Compare(name(a), name(b))
+	})
+
+	return keys
+}
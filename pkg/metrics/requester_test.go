@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/session"
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/yazio"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
+)
+
+type failingRequester struct{}
+
+func (failingRequester) Do(*http.Request) (*http.Response, error) {
+	return nil, errors.New("dial: connection refused")
+}
+
+func TestRequester(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx  = context.Background()
+		fake = yaziotest.New(t)
+		rq   = NewRequester(http.DefaultClient, 10, 60)
+	)
+
+	api, err := yazio.New(yazio.WithBaseURL(fake.URL), yazio.WithRequester(rq))
+	assert.NoError(t, err)
+
+	_, err = api.Login(ctx, yazio.NewPasswordCred(yaziotest.DefaultUsername, "wrong"))
+	assert.WantErr(t, true, err)
+
+	u, err := api.Login(ctx, yazio.NewPasswordCred(yaziotest.DefaultUsername, yaziotest.DefaultPassword))
+	assert.NoError(t, err)
+
+	_, err = u.Data(ctx)
+	assert.NoError(t, err)
+
+	fake.ExpireTokens()
+	_, err = u.Data(ctx)
+	assert.WantErr(t, true, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, rq))
+	out := buf.String()
+
+	for _, line := range []string{
+		`yazio_client_requests_total{endpoint="/v18/oauth/token",method="POST",code="200"} 1`,
+		`yazio_client_requests_total{endpoint="/v18/user",method="GET",code="200"} 1`,
+		`yazio_client_requests_total{endpoint="/v18/user",method="GET",code="401"} 1`,
+		`yazio_client_request_duration_seconds_bucket{endpoint="/v18/user",method="GET",le="10"} 2`,
+		`yazio_client_request_duration_seconds_bucket{endpoint="/v18/user",method="GET",le="+Inf"} 2`,
+		`yazio_client_request_duration_seconds_count{endpoint="/v18/user",method="GET"} 2`,
+		`yazio_client_tokens_total{grant_type="password"} 1`,
+		"# TYPE yazio_client_request_duration_seconds histogram",
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, out)
+		}
+	}
+
+	if strings.Contains(out, `grant_type="refresh_token"`) {
+		t.Errorf("unexpected refresh in:\n%s", out)
+	}
+}
+
+func TestRequester_Refresh(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx  = context.Background()
+		fake = yaziotest.New(t)
+		rq   = NewRequester(http.DefaultClient)
+	)
+
+	api, err := yazio.New(yazio.WithBaseURL(fake.URL), yazio.WithRequester(rq))
+	assert.NoError(t, err)
+
+	u, err := api.Login(ctx, yazio.NewPasswordCred(yaziotest.DefaultUsername, yaziotest.DefaultPassword))
+	assert.NoError(t, err)
+
+	sess := session.FromToken(yaziotest.DefaultUsername, u.Token())
+	sess.ExpiresAt = time.Now().Add(-time.Minute)
+	assert.NoError(t, api.Refresh(ctx, api.Resume(sess.Token())))
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, rq))
+	assert.Equal(t, strings.Contains(buf.String(), `yazio_client_tokens_total{grant_type="refresh_token"} 1`+"\n"), true)
+}
+
+func TestRequester_TransportError(t *testing.T) {
+	t.Parallel()
+
+	rq := NewRequester(failingRequester{})
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/v18/user/products/0b8f2a64-0d8f-4c36-9a3e-6f7f8c1b2d01", nil)
+	assert.NoError(t, err)
+
+	_, err = rq.Do(req)
+	assert.WantErr(t, true, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, rq))
+	assert.Equal(t, strings.Contains(buf.String(), `yazio_client_requests_total{endpoint="/v18/user/products/{id}",method="GET",code="error"} 1`+"\n"), true)
+}
+
+func TestEndpointOf(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		path string
+		want string
+	}{
+		{"/v18/user", "/v18/user"},
+		{"/v18/products/0b8f2a64-0d8f-4c36-9a3e-6f7f8c1b2d01", "/v18/products/{id}"},
+		{"/v18/items/42/servings", "/v18/items/{id}/servings"},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.path, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, endpointOf(tb.path), tb.want)
+		})
+	}
+}
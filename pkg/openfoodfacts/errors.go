@@ -0,0 +1,8 @@
+package openfoodfacts
+
+import "errors"
+
+var (
+	ErrProductNotFound = errors.New("given open food facts document has no product")
+	ErrInvalidDocument = errors.New("given open food facts document is invalid")
+)
@@ -0,0 +1,321 @@
+package openfoodfacts
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+	"github.com/google/uuid"
+)
+
+const (
+	per100 = "_100g" // suffix of the values per 100g (or 100ml)
+
+	// saltPerSodium is how many grams of salt hold a gram of sodium.
+	saltPerSodium = 2.5
+)
+
+var (
+	// namespace derives the food IDs from barcodes.
+	namespace = uuid.MustParse("1f0c8a52-7d43-4b8e-9c21-5e6a3b7d9f04")
+
+	// nutriments maps the keys of Open Food Facts onto kinds. Besides energy
+	// (kcal) and alcohol (% vol, which is ml per 100ml), values are in grams.
+	nutriments = map[string]intake.Kind{
+		"energy-kcal":         intake.Energy,
+		"fat":                 intake.Fat,
+		"saturated-fat":       intake.Saturated,
+		"monounsaturated-fat": intake.Monounsaturated,
+		"polyunsaturated-fat": intake.Polyunsaturated,
+		"trans-fat":           intake.TransFat,
+		"cholesterol":         intake.Cholesterol,
+		"carbohydrates":       intake.Carb,
+		"sugars":              intake.Sugar,
+		"added-sugars":        intake.AddedSugar,
+		"fiber":               intake.Fiber,
+		"proteins":            intake.Protein,
+		"salt":                intake.Salt,
+		"sodium":              intake.Sodium,
+		"alcohol":             intake.Alcohol,
+		"vitamin-a":           intake.VitaminA,
+		"vitamin-b1":          intake.VitaminB1,
+		"vitamin-b2":          intake.VitaminB2,
+		"vitamin-pp":          intake.VitaminB3,
+		"pantothenic-acid":    intake.VitaminB5,
+		"vitamin-b6":          intake.VitaminB6,
+		"biotin":              intake.VitaminB7,
+		"vitamin-b9":          intake.VitaminB11,
+		"folates":             intake.VitaminB11,
+		"vitamin-b12":         intake.VitaminB12,
+		"vitamin-c":           intake.VitaminC,
+		"vitamin-d":           intake.VitaminD,
+		"vitamin-e":           intake.VitaminE,
+		"vitamin-k":           intake.VitaminK,
+		"calcium":             intake.Calcium,
+		"iron":                intake.Iron,
+		"potassium":           intake.Potassium,
+		"magnesium":           intake.MineralMagnesium,
+		"zinc":                intake.MineralZinc,
+		"phosphorus":          intake.MineralPhosphorus,
+		"copper":              intake.MineralCopper,
+		"manganese":           intake.MineralManganese,
+		"selenium":            intake.MineralSelenium,
+		"iodine":              intake.MineralIodine,
+		"chromium":            intake.MineralChrome,
+		"molybdenum":          intake.MineralMolybdenum,
+		"fluoride":            intake.MineralFluoride,
+		"chloride":            intake.MineralChlorine,
+		"choline":             intake.MineralCholine,
+	}
+
+	// categories maps category tags onto [food.Category].
+	categories = map[string]food.Category{
+		"en:alcoholic-beverages":        food.AlcoholicDrink,
+		"en:wines":                      food.AlcoholicDrink,
+		"en:beers":                      food.AlcoholicDrink,
+		"en:spirits":                    food.AlcoholicDrink,
+		"en:beverages":                  food.NonAlcoholicDrink,
+		"en:non-alcoholic-beverages":    food.NonAlcoholicDrink,
+		"en:waters":                     food.NonAlcoholicDrink,
+		"en:juices":                     food.NonAlcoholicDrink,
+		"en:sodas":                      food.NonAlcoholicDrink,
+		"en:coffees":                    food.NonAlcoholicDrink,
+		"en:teas":                       food.NonAlcoholicDrink,
+		"en:baby-foods":                 food.BabyFood,
+		"en:breads":                     food.Bread,
+		"en:cakes":                      food.Cake,
+		"en:breakfast-cereals":          food.Cereal,
+		"en:cheeses":                    food.Cheese,
+		"en:chocolates":                 food.Chocolate,
+		"en:dairies":                    food.Dairy,
+		"en:milks":                      food.Dairy,
+		"en:desserts":                   food.Dessert,
+		"en:meals":                      food.Dish,
+		"en:eggs":                       food.Egg,
+		"en:fats":                       food.Fat,
+		"en:vegetable-oils":             food.Fat,
+		"en:butters":                    food.Fat,
+		"en:fishes":                     food.Fish,
+		"en:seafood":                    food.Fish,
+		"en:fruits":                     food.Fruit,
+		"en:cereal-grains":              food.Grain,
+		"en:cereals-and-their-products": food.Grain,
+		"en:ice-creams":                 food.IceCream,
+		"en:legumes":                    food.Legume,
+		"en:meat-analogues":             food.MeatSubstitute,
+		"en:meats":                      food.Meat,
+		"en:nuts":                       food.Nut,
+		"en:pastas":                     food.Pasta,
+		"en:potatoes":                   food.Potato,
+		"en:poultries":                  food.Poultry,
+		"en:rices":                      food.Rice,
+		"en:sauces":                     food.Sauce,
+		"en:sausages":                   food.Sausage,
+		"en:snacks":                     food.Snack,
+		"en:salty-snacks":               food.Snack,
+		"en:soups":                      food.Soup,
+		"en:spices":                     food.Spice,
+		"en:condiments":                 food.Spice,
+		"en:spreads":                    food.Spread,
+		"en:dietary-supplements":        food.Supplement,
+		"en:confectioneries":            food.Sweet,
+		"en:vegetables":                 food.Vegetable,
+		"en:yogurts":                    food.Yogurt,
+	}
+
+	// weightPattern finds a weight like "30 g" or "250ml" in free text.
+	weightPattern = regexp.MustCompile(`(?i)([0-9]+(?:[.,][0-9]+)?)\s*(mg|g|kg|ml|cl|l|fl oz|oz)\b`)
+)
+
+// ID returns the food ID derived from the barcode code,
+// so converting a product twice gives the same ID.
+func ID(code string) food.ID {
+	return uuid.NewSHA1(namespace, []byte(strings.TrimSpace(code)))
+}
+
+// Food converts p into a [food.Food], identified by [ID].
+//
+// Its name has the brand appended (e.g. "Nutella (Ferrero)"). Energy in kJ
+// is converted to kcal, and salt and sodium are derived from each other
+// when only one is given. The serving size and the net content become
+// servings when their weight is known.
+//
+// The food is not validated; see [food.Food.Validate].
+//
+// On failure the error wraps either:
+//   - [food.ErrMissingNutrients] if energy, fat, carbohydrates or proteins are missing
+//   - [food.ErrInvalidName]
+func (p Product) Food() (food.Food, error) {
+	base := p.BaseUnit()
+
+	nutrients, err := p.Nutrients()
+	if err != nil {
+		return food.Food{}, err
+	}
+
+	opts := []food.Option{
+		food.WithID(ID(p.Code)),
+		food.WithBaseUnit(base),
+	}
+	for _, s := range p.Servings() {
+		opts = append(opts, food.WithServing(s))
+	}
+
+	return food.New(p.displayName(), p.Category(), nutrients, opts...)
+}
+
+func (p Product) displayName() string {
+	name := strings.TrimSpace(p.Name)
+	if name == "" {
+		name = strings.TrimSpace(p.GenericName)
+	}
+
+	brand, _, _ := strings.Cut(p.Brands, ",")
+	if brand = strings.TrimSpace(brand); brand != "" && name != "" {
+		name = fmt.Sprintf("%s (%s)", name, brand)
+	}
+
+	return name
+}
+
+// BaseUnit returns the unit the nutrients of p are given per 100 of.
+func (p Product) BaseUnit() unit.Base {
+	switch {
+	case strings.EqualFold(p.NutritionDataPer, "100ml"),
+		strings.EqualFold(p.ProductQuantityUnit, "ml"):
+		return unit.Milliliter
+	default:
+		return unit.Gram
+	}
+}
+
+// Category returns the most specific known category
+// of p, or [food.Miscellaneous].
+func (p Product) Category() food.Category {
+	for _, tag := range slices.Backward(p.CategoriesTags) {
+		if cat, ok := categories[tag]; ok {
+			return cat
+		}
+	}
+	return food.Miscellaneous
+}
+
+// Nutrients returns the nutrients of p per 100 base units.
+//
+// On failure the error wraps either:
+//   - [food.ErrMissingNutrients]
+func (p Product) Nutrients() (food.Nutrients, error) {
+	out := make(food.Nutrients)
+
+	for key, value := range p.Nutriments {
+		name, ok := strings.CutSuffix(key, per100)
+		if !ok {
+			continue
+		}
+
+		k, ok := nutriments[name]
+		if !ok {
+			continue
+		}
+
+		v := float64(value)
+		switch k {
+		case intake.Energy, intake.Alcohol:
+		default:
+			v, _ = unit.Convert(v, unit.Gram, k.BaseUnit())
+		}
+		out[k] = v
+	}
+
+	if _, ok := out[intake.Energy]; !ok {
+		for _, key := range []string{"energy-kj" + per100, "energy" + per100} { // kJ
+			if kj, ok := p.Nutriments[key]; ok {
+				out[intake.Energy], _ = unit.Convert(float64(kj), unit.Kilojoule, unit.Kilocalorie)
+				break
+			}
+		}
+	}
+
+	salt, hasSalt := p.Nutriments["salt"+per100]
+	sodium, hasSodium := p.Nutriments["sodium"+per100]
+	switch {
+	case hasSalt && !hasSodium:
+		out[intake.Sodium], _ = unit.Convert(float64(salt)/saltPerSodium, unit.Gram, intake.Sodium.BaseUnit())
+	case hasSodium && !hasSalt:
+		out[intake.Salt] = float64(sodium) * saltPerSodium
+	}
+
+	var missing []string
+	for _, k := range []intake.Kind{intake.Energy, intake.Fat, intake.Carb, intake.Protein} {
+		if _, ok := out[k]; !ok {
+			missing = append(missing, k.String())
+		}
+	}
+	if len(missing) > 0 {
+		return out, fmt.Errorf("%w: %s", food.ErrMissingNutrients, strings.Join(missing, ", "))
+	}
+
+	return out, nil
+}
+
+// Servings returns the serving size and the net content of p,
+// when their weight is known in the base unit of p.
+func (p Product) Servings() []food.Serving {
+	var (
+		base = p.BaseUnit()
+		out  []food.Serving
+	)
+
+	amount, ok := weightIn(float64(p.ServingQuantity), p.ServingQuantityUnit, base)
+	if !ok {
+		amount, ok = parseWeight(p.ServingSize, base)
+	}
+	if ok {
+		out = append(out, food.Serving{Kind: food.GuessServingKind(p.ServingSize), Amount: amount})
+	}
+
+	content, ok := weightIn(float64(p.ProductQuantity), p.ProductQuantityUnit, base)
+	if ok && !slices.ContainsFunc(out, func(s food.Serving) bool { return s.Kind == food.Pack }) {
+		out = append(out, food.Serving{Kind: food.Pack, Amount: content})
+	}
+
+	return out
+}
+
+// parseWeight finds the weight in text, in the unit base.
+func parseWeight(text string, base unit.Base) (float64, bool) {
+	match := weightPattern.FindStringSubmatch(text)
+	if match == nil {
+		return 0, false
+	}
+
+	v, ok := parseNumber(match[1])
+	if !ok {
+		return 0, false
+	}
+	return weightIn(v, match[2], base)
+}
+
+// weightIn converts v, measured in the Open Food Facts unit u, into base.
+func weightIn(v float64, u string, base unit.Base) (float64, bool) {
+	if v <= 0 {
+		return 0, false
+	}
+
+	from := unit.Base(strings.ToLower(strings.TrimSpace(u)))
+	switch from {
+	case "":
+		from = base
+	case "kg":
+		from, v = unit.Gram, v*1000
+	case "cl":
+		from, v = unit.Milliliter, v*10
+	}
+
+	out, err := unit.Convert(v, from, base)
+	return out, err == nil
+}
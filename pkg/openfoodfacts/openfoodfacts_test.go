@@ -0,0 +1,117 @@
+package openfoodfacts
+
+import (
+	"errors"
+	"io"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
+)
+
+func assertNear(t *testing.T, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name     string
+		input    string
+		wantCode string
+		wantErr  error
+	}{
+		{name: "bare product", input: `{"code":"17","product_name":"Oats"}`, wantCode: "17"},
+		{name: "api response", input: `{"status":1,"product":{"code":"17"}}`, wantCode: "17"},
+		{name: "not found", input: `{"status":0,"status_verbose":"product not found"}`, wantErr: ErrProductNotFound},
+		{name: "not json", input: `<html>`, wantErr: ErrInvalidDocument},
+		{name: "invalid number", input: `{"product_quantity":"lots"}`, wantErr: ErrInvalidDocument},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := Parse([]byte(tb.input))
+			if tb.wantErr != nil {
+				if !errors.Is(err, tb.wantErr) {
+					t.Fatalf("got %v, want %v", err, tb.wantErr)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, p.Code, tb.wantCode)
+		})
+	}
+}
+
+func TestProduct_Food(t *testing.T) {
+	t.Parallel()
+
+	p, err := Open("testdata/spread.json")
+	assert.NoError(t, err)
+
+	f, err := p.Food()
+	assert.NoError(t, err)
+	assert.NoError(t, f.Validate())
+
+	assert.Equal(t, f.ID, ID("3017620422003"))
+	assert.Equal(t, f.Name, "Hazelnut spread (Nocciola)")
+	assert.Equal(t, f.Category, food.Spread)
+	assert.Equal(t, f.BaseUnit, unit.Gram)
+	assert.DeepEqual(t, f.Servings, []food.Serving{
+		{Kind: food.Tablespoon, Amount: 15},
+		{Kind: food.Pack, Amount: 400},
+	})
+
+	assert.Equal(t, f.Nutrients[intake.Energy], 539.0)
+	assert.Equal(t, f.Nutrients[intake.Protein], 6.3)
+	assert.Equal(t, f.Nutrients[intake.Fiber], 0.0)
+	assertNear(t, f.Nutrients[intake.Calcium], 108)          // mg
+	assertNear(t, f.Nutrients[intake.Sodium], 0.107/2.5*1e6) // mcg, from salt
+}
+
+func TestDecoder(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("testdata/dump.jsonl")
+	assert.NoError(t, err)
+	t.Cleanup(func() { file.Close() })
+
+	dec := NewDecoder(file)
+
+	cola, err := dec.Next()
+	assert.NoError(t, err)
+
+	f, err := cola.Food()
+	assert.NoError(t, err)
+	assert.Equal(t, f.Name, "Cola")
+	assert.Equal(t, f.Category, food.NonAlcoholicDrink)
+	assert.Equal(t, f.BaseUnit, unit.Milliliter)
+	assert.DeepEqual(t, f.Servings, []food.Serving{
+		{Kind: food.Can, Amount: 330},
+		{Kind: food.Pack, Amount: 330},
+	})
+	assertNear(t, f.Nutrients[intake.Energy], 180/4.184) // from kJ
+	assertNear(t, f.Nutrients[intake.Salt], 0.01)        // from sodium
+
+	bar, err := dec.Next()
+	assert.NoError(t, err)
+
+	if _, err := bar.Food(); !errors.Is(err, food.ErrMissingNutrients) {
+		t.Fatalf("got %v, want %v", err, food.ErrMissingNutrients)
+	}
+
+	if _, err := dec.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("got %v, want %v", err, io.EOF)
+	}
+}
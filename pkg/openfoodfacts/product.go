@@ -0,0 +1,168 @@
+// Package openfoodfacts converts Open Food Facts products
+// into foods that can be registered with YAZIO.
+//
+// Documents are read from local files, API responses or the
+// JSONL dumps, and converted with [Product.Food]:
+//
+//	p, err := openfoodfacts.Open("3017620422003.json")
+//	f, err := p.Food()
+//	err = user.AddFood(ctx, f, visibility.PrivateFood)
+package openfoodfacts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Number is a number that Open Food Facts
+// may also encode as a (possibly empty) string.
+type Number float64
+
+func (n *Number) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if s, err := strconv.Unquote(string(data)); err == nil {
+		v, ok := parseNumber(s)
+		if !ok && strings.TrimSpace(s) != "" {
+			return fmt.Errorf("%w: number %q", ErrInvalidDocument, s)
+		}
+		*n = Number(v)
+		return nil
+	}
+
+	v, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("%w: number %s", ErrInvalidDocument, data)
+	}
+
+	*n = Number(v)
+	return nil
+}
+
+// parseNumber reads a number, accepting decimal commas.
+func parseNumber(s string) (float64, bool) {
+	s = strings.Replace(strings.TrimSpace(s), ",", ".", 1)
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
+
+// Product is the subset of an Open Food Facts product
+// needed to describe it as a food.
+type Product struct {
+	Code                string     `json:"code"`                  // Code is the barcode.
+	Name                string     `json:"product_name"`          // Name is the name in the main language.
+	GenericName         string     `json:"generic_name"`          // GenericName is used when Name is empty.
+	Brands              string     `json:"brands"`                // Brands is comma-separated.
+	ProductQuantity     Number     `json:"product_quantity"`      // ProductQuantity is the net content.
+	ProductQuantityUnit string     `json:"product_quantity_unit"` // ProductQuantityUnit is "g" or "ml".
+	ServingSize         string     `json:"serving_size"`          // ServingSize is free text, e.g. "2 slices (30 g)".
+	ServingQuantity     Number     `json:"serving_quantity"`      // ServingQuantity is the parsed weight of ServingSize.
+	ServingQuantityUnit string     `json:"serving_quantity_unit"` // ServingQuantityUnit is "g" or "ml".
+	NutritionDataPer    string     `json:"nutrition_data_per"`    // NutritionDataPer is "100g" or "100ml".
+	CategoriesTags      []string   `json:"categories_tags"`       // CategoriesTags go from general to specific.
+	Nutriments          Nutriments `json:"nutriments"`            // Nutriments are keyed like "fat_100g".
+}
+
+// Nutriments are the nutrient values of a product,
+// keyed by Open Food Facts (e.g. "sodium_100g").
+type Nutriments map[string]Number
+
+func (n *Nutriments) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+
+	*n = make(Nutriments, len(raw))
+	for key, value := range raw {
+		var v Number
+		if err := v.UnmarshalJSON(value); err != nil {
+			continue // units, labels and other metadata
+		}
+		(*n)[key] = v
+	}
+
+	return nil
+}
+
+func (p Product) String() string {
+	return fmt.Sprintf("Product(%s, %s)", p.Code, p.Name)
+}
+
+// envelope is the response of the product API.
+type envelope struct {
+	Status  *int     `json:"status"`
+	Product *Product `json:"product"`
+}
+
+// Parse decodes a product document, either a bare product
+// (as in the dumps) or the response of the product API.
+//
+// On failure the error wraps either:
+//   - [ErrInvalidDocument]
+//   - [ErrProductNotFound] when the API didn't find the product
+func Parse(data []byte) (Product, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return Product{}, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+
+	switch {
+	case env.Product != nil:
+		return *env.Product, nil
+	case env.Status != nil:
+		return Product{}, ErrProductNotFound
+	}
+
+	var p Product
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+	return p, nil
+}
+
+// Open parses the product document at path; see [Parse].
+func Open(path string) (Product, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Product{}, err
+	}
+	return Parse(data)
+}
+
+// Decoder reads the products of a dump (one document per
+// line, JSONL) without loading it whole.
+//
+// Instances of Decoder should be created using [NewDecoder].
+type Decoder struct {
+	dec *json.Decoder
+}
+
+// NewDecoder returns a [*Decoder] reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{dec: json.NewDecoder(r)}
+}
+
+// Next returns the next product, or [io.EOF] after the last one.
+//
+// On failure the error wraps either:
+//   - [ErrInvalidDocument]
+//   - [ErrProductNotFound]
+func (d *Decoder) Next() (Product, error) {
+	var raw json.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Product{}, io.EOF
+		}
+		return Product{}, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+	return Parse(raw)
+}
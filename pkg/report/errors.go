@@ -0,0 +1,7 @@
+package report
+
+import "errors"
+
+var (
+	ErrUnknownFormat = errors.New("given report format is unknown")
+)
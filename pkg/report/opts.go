@@ -0,0 +1,60 @@
+package report
+
+import (
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/reference"
+)
+
+type Option func(rp *Reporter)
+
+// WithTitle sets the title of reports. It defaults to "Daily
+// report" or "Weekly report" for ranges of 1 or 7 days.
+func WithTitle(title string) Option {
+	return func(rp *Reporter) {
+		rp.title = title
+	}
+}
+
+// WithKinds highlights the averages of ks, which
+// costs one request per kind.
+func WithKinds(ks ...intake.Kind) Option {
+	return func(rp *Reporter) {
+		rp.kinds = append(rp.kinds, ks...)
+	}
+}
+
+// WithGoals measures the adherence of the days to g,
+// ranking them by their distance to the energy goal.
+func WithGoals(g Goals) Option {
+	return func(rp *Reporter) {
+		rp.goals = g
+	}
+}
+
+// WithTolerance sets the relative distance (e.g. 0.1 for 10%) to
+// the energy goal a day still counts as on target within.
+// Values below 0 are ignored.
+func WithTolerance(share float64) Option {
+	return func(rp *Reporter) {
+		if share >= 0 {
+			rp.tolerance = share
+		}
+	}
+}
+
+// WithPeriod breaks the days down into the buckets of p
+// (e.g. [date.Weekly]), with their averages.
+func WithPeriod(p date.Period) Option {
+	return func(rp *Reporter) {
+		rp.period = &p
+	}
+}
+
+// WithProfile compares the highlighted kinds with
+// the reference values that apply to p.
+func WithProfile(p reference.Profile) Option {
+	return func(rp *Reporter) {
+		rp.profile = &p
+	}
+}
@@ -0,0 +1,95 @@
+package report
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"math"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+)
+
+const (
+	layoutDay = "2006-01-02"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// funcs are shared by the templates of every format.
+var funcs = map[string]any{
+	"num":     formatNumber,
+	"percent": formatPercent,
+	"signed":  func(share float64) string { return fmt.Sprintf("%+.0f%%", share*100) },
+	"day":     func(t time.Time) string { return t.Format(layoutDay) },
+	"weekday": func(t time.Time) string { return t.Format("Mon " + layoutDay) },
+	"instant": func(t time.Time) string { return t.Format(time.RFC3339) },
+	"unit":    func(k intake.Kind) string { return k.Unit() },
+	"share":   func(v float64) string { return formatPercent(v * 100) },
+	"goal": func(v float64) string {
+		if v <= 0 {
+			return "–"
+		}
+		return formatNumber(v)
+	},
+	"ofGoal": func(v float64) string {
+		if v <= 0 {
+			return "–"
+		}
+		return formatPercent(v)
+	},
+}
+
+// chartFuncs draw the inline SVG charts of HTML reports.
+var chartFuncs = htmltemplate.FuncMap{
+	"energyChart":       svgFunc(energyChart),
+	"distributionChart": svgFunc(distributionChart),
+	"referenceChart":    svgFunc(referenceChart),
+}
+
+var (
+	markdownTemplate = template.Must(
+		template.New("report.md.tmpl").Funcs(funcs).ParseFS(templatesFS, "templates/report.md.tmpl"),
+	)
+	htmlTemplate = htmltemplate.Must(
+		htmltemplate.New("report.html.tmpl").Funcs(funcs).Funcs(chartFuncs).ParseFS(templatesFS, "templates/report.html.tmpl"),
+	)
+)
+
+// svgFunc marks the charts, built from escaped
+// values only, as safe to embed in HTML.
+func svgFunc(chart func(Report) string) func(Report) htmltemplate.HTML {
+	return func(rep Report) htmltemplate.HTML {
+		return htmltemplate.HTML(chart(rep))
+	}
+}
+
+// formatNumber rounds v to one decimal, trimming zeros.
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
+
+// formatPercent rounds v (already a percentage) to a whole number.
+func formatPercent(v float64) string {
+	return strconv.FormatFloat(math.Round(v), 'f', -1, 64) + "%"
+}
+
+// Render writes r to w, encoded as f.
+//
+// On failure the error wraps either:
+//   - [ErrUnknownFormat]
+//   - Other: generic (writer related)
+func (r Report) Render(w io.Writer, f Format) error {
+	switch f {
+	case Markdown:
+		return markdownTemplate.Execute(w, r)
+	case HTML:
+		return htmlTemplate.Execute(w, r)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, f)
+	}
+}
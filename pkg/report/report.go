@@ -0,0 +1,346 @@
+// Package report summarizes the diary of a YAZIO user over a
+// period as Markdown or as a self-contained HTML page with
+// inline SVG charts:
+//
+//	rp := report.New(user,
+//		report.WithKinds(intake.VitaminC, intake.Iron),
+//		report.WithGoals(report.Goals{Energy: 2000, Protein: 120}),
+//	)
+//	rep, err := rp.Build(ctx, r)
+//	err = rep.Render(os.Stdout, report.HTML)
+//
+// Reports hold the macro averages, the best and worst days
+// against the goals, and micronutrient highlights against the
+// reference intakes of a profile (see [WithProfile]).
+package report
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/reference"
+)
+
+const (
+	Markdown Format = "markdown" // Markdown renders GitHub flavored Markdown.
+	HTML     Format = "html"     // HTML renders a self-contained page with inline SVG charts.
+)
+
+const (
+	defaultTolerance = 0.1
+	rankedDays       = 3
+)
+
+// Format is the encoding of a rendered [Report].
+type Format string
+
+func (f Format) String() string {
+	return string(f)
+}
+
+// ParseFormat resolves the [Format] named s,
+// ignoring case and accepting "md".
+//
+// On failure the error wraps either:
+//   - [ErrUnknownFormat]
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case Markdown, HTML:
+		return f, nil
+	case "md":
+		return Markdown, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+	}
+}
+
+// Goals are the daily targets of a user. Zero values are unset.
+type Goals struct {
+	Energy  float64 // Energy is the daily target, in kcal.
+	Carb    float64 // Carb is the daily target, in grams.
+	Fat     float64 // Fat is the daily target, in grams.
+	Protein float64 // Protein is the daily target, in grams.
+}
+
+// IsZero reports whether no goal is set.
+func (g Goals) IsZero() bool {
+	return g == Goals{}
+}
+
+// Adherence measures how the logged days met the [Goals].
+type Adherence struct {
+	Tolerance float64 // Tolerance is the relative distance to the energy goal still on target.
+	OnTarget  int     // OnTarget is how many days had their energy within tolerance.
+	Days      int     // Days is how many days were logged.
+
+	// Percentages of the average daily intake of each goal,
+	// zero when the goal is unset.
+	Energy, Carb, Fat, Protein float64
+}
+
+// Rate returns the share (0 to 1) of the logged days on target.
+func (a Adherence) Rate() float64 {
+	if a.Days == 0 {
+		return 0
+	}
+	return float64(a.OnTarget) / float64(a.Days)
+}
+
+// Day is a logged day, ranked by its distance to the energy goal.
+type Day struct {
+	intake.Macros
+	Deviation float64 // Deviation is (Energy - goal) / goal.
+}
+
+// Highlight is the average intake of a micronutrient, compared
+// with its reference value when a profile is given.
+type Highlight struct {
+	Kind      intake.Kind
+	Days      int     // Days is how many days hold data.
+	Average   float64 // Average is the daily average, in the base unit of the kind.
+	Reference *reference.Item
+}
+
+// Report summarizes a date range of a user.
+type Report struct {
+	Title        string
+	Range        date.Range
+	Generated    time.Time
+	Days         intake.MacrosRange   // Days are the logged days, sorted by date.
+	Average      intake.MacrosAverage // Average is the mean of the logged days.
+	Distribution intake.Distribution  // Distribution is the energy share of the average macros.
+	Buckets      []intake.MacrosBucket
+	Highest      *intake.Macros // Highest is the logged day with the most energy.
+	Lowest       *intake.Macros // Lowest is the logged day with the least energy.
+	Goals        Goals
+	Adherence    *Adherence // Adherence is nil without an energy or macro goal.
+	Best         []Day      // Best are the days closest to the energy goal.
+	Worst        []Day      // Worst are the days farthest from the energy goal.
+	Highlights   []Highlight
+	Uncovered    []intake.Kind // Uncovered are the kinds without reference values.
+}
+
+// Calendar returns how many days the range of r spans.
+func (r Report) Calendar() int {
+	return r.Range.Days()
+}
+
+// User is the part of a user a [Reporter] reads
+// (usually a *yazio.User).
+type User interface {
+	Macros(ctx context.Context, r date.Range) (intake.MacrosRange, error)
+	Intakes(ctx context.Context, kinds []intake.Kind, r date.Range) (intake.Matrix, error)
+}
+
+// Reporter builds the reports of a user.
+//
+// Instances of Reporter should be created using [New].
+type Reporter struct {
+	user      User
+	title     string
+	kinds     []intake.Kind
+	goals     Goals
+	tolerance float64
+	period    *date.Period
+	profile   *reference.Profile
+	now       func() time.Time
+}
+
+// New returns a [*Reporter] of u (usually a *yazio.User).
+// By default reports only hold macros; see [Option].
+func New(u User, opts ...Option) *Reporter {
+	rp := &Reporter{
+		user:      u,
+		tolerance: defaultTolerance,
+		now:       time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(rp)
+	}
+
+	return rp
+}
+
+// Build fetches the days of r and summarizes them.
+//
+// On failure the error wraps either:
+//   - [reference.ErrUnknownSex]
+//   - [reference.ErrUnsupportedAge]
+//   - The errors of the user methods (e.g. yazio.ErrExpiredToken)
+func (rp *Reporter) Build(ctx context.Context, r date.Range) (Report, error) {
+	mr, err := rp.user.Macros(ctx, r)
+	if err != nil {
+		return Report{}, err
+	}
+
+	rep := Report{
+		Title:     rp.titleOf(r),
+		Range:     r,
+		Generated: rp.now(),
+		Goals:     rp.goals,
+	}
+
+	for _, m := range mr {
+		if m != (intake.Macros{Date: m.Date}) {
+			rep.Days = append(rep.Days, m)
+		}
+	}
+	slices.SortFunc(rep.Days, func(a, b intake.Macros) int {
+		return a.Date.Compare(b.Date)
+	})
+
+	rep.Average = rep.Days.Average()
+	rep.Distribution = rep.Average.Distribution()
+	if rp.period != nil {
+		rep.Buckets = rep.Days.GroupBy(*rp.period)
+	}
+
+	if len(rep.Days) > 0 {
+		byEnergy := func(a, b intake.Macros) int { return cmp.Compare(a.Energy, b.Energy) }
+		highest, lowest := slices.MaxFunc(rep.Days, byEnergy), slices.MinFunc(rep.Days, byEnergy)
+		rep.Highest, rep.Lowest = &highest, &lowest
+	}
+
+	rp.rank(&rep)
+
+	if len(rp.kinds) == 0 {
+		return rep, nil
+	}
+
+	m, err := rp.user.Intakes(ctx, rp.kinds, r)
+	if err != nil {
+		return Report{}, err
+	}
+
+	if err := rp.highlight(&rep, m); err != nil {
+		return Report{}, err
+	}
+
+	return rep, nil
+}
+
+func (rp *Reporter) titleOf(r date.Range) string {
+	switch {
+	case rp.title != "":
+		return rp.title
+	case r.Days() == 1:
+		return "Daily report"
+	case r.Days() == 7:
+		return "Weekly report"
+	default:
+		return "Nutrition report"
+	}
+}
+
+// rank measures the adherence to the goals,
+// and ranks the days against the energy goal.
+func (rp *Reporter) rank(rep *Report) {
+	g := rep.Goals
+	if g.IsZero() {
+		return
+	}
+
+	percentOf := func(avg, goal float64) float64 {
+		if goal <= 0 {
+			return 0
+		}
+		return avg / goal * 100
+	}
+
+	a := Adherence{
+		Tolerance: rp.tolerance,
+		Days:      len(rep.Days),
+		Energy:    percentOf(rep.Average.Energy, g.Energy),
+		Carb:      percentOf(rep.Average.Carb, g.Carb),
+		Fat:       percentOf(rep.Average.Fat, g.Fat),
+		Protein:   percentOf(rep.Average.Protein, g.Protein),
+	}
+	rep.Adherence = &a
+
+	if g.Energy <= 0 {
+		return
+	}
+
+	days := make([]Day, 0, len(rep.Days))
+	for _, m := range rep.Days {
+		d := Day{Macros: m, Deviation: (m.Energy - g.Energy) / g.Energy}
+		if math.Abs(d.Deviation) <= a.Tolerance {
+			a.OnTarget++
+		}
+		days = append(days, d)
+	}
+
+	slices.SortStableFunc(days, func(x, y Day) int {
+		return cmp.Compare(math.Abs(x.Deviation), math.Abs(y.Deviation))
+	})
+
+	n := min(rankedDays, len(days)/2)
+	rep.Best = days[:n]
+	rep.Worst = slices.Clone(days[len(days)-n:])
+	slices.Reverse(rep.Worst)
+}
+
+// highlight compares the series of m with the reference
+// values of the profile, listing deficiencies first.
+func (rp *Reporter) highlight(rep *Report, m intake.Matrix) error {
+	var (
+		series = make([]intake.SingleRange, 0, len(rp.kinds))
+		items  = make(map[intake.Kind]reference.Item)
+	)
+
+	for _, k := range rp.kinds {
+		series = append(series, m.Series(k))
+	}
+
+	if rp.profile != nil {
+		ref, err := reference.NewReport(*rp.profile, rep.Range.End, series)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range ref.Items {
+			items[item.Reference.Kind] = item
+		}
+		rep.Uncovered = ref.Uncovered
+	}
+
+	for i, k := range rp.kinds {
+		avg := series[i].Average()
+		h := Highlight{Kind: k, Days: avg.DaysLength, Average: avg.Average}
+		if item, ok := items[k]; ok {
+			h.Reference = &item
+		}
+		rep.Highlights = append(rep.Highlights, h)
+	}
+
+	slices.SortStableFunc(rep.Highlights, func(a, b Highlight) int {
+		return cmp.Compare(severity(a), severity(b))
+	})
+
+	return nil
+}
+
+// severity orders highlights: deficient, excessive,
+// adequate, without reference, then unlogged ones.
+func severity(h Highlight) int {
+	switch {
+	case h.Days == 0:
+		return 4
+	case h.Reference == nil:
+		return 3
+	case h.Reference.Status == reference.Deficient:
+		return 0
+	case h.Reference.Status == reference.Excessive:
+		return 1
+	default:
+		return 2
+	}
+}
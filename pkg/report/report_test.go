@@ -0,0 +1,241 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/internal/testutil/fakeuser"
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/reference"
+	"github.com/controlado/go-yazio/pkg/yazio"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
+	"github.com/google/uuid"
+)
+
+var (
+	firstDay = time.Date(2025, 4, 7, 0, 0, 0, 0, time.UTC)
+	week     = date.Range{Start: firstDay, End: firstDay.AddDate(0, 0, 6)}
+
+	productID = uuid.MustParse("0b8f2a64-0d8f-4c36-9a3e-6f7f8c1b2d01")
+	profile   = reference.Profile{Sex: reference.Female, Birth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)}
+)
+
+// seeded returns a user who ate 400, 500, 200 and 600 grams of
+// a 100 kcal porridge on the first four days of the week.
+func seeded(t *testing.T) *yazio.User {
+	t.Helper()
+
+	fake := yaziotest.New(t,
+		yaziotest.WithProduct(yaziotest.Product{
+			ID:   productID,
+			Name: "Porridge",
+			Nutrients: map[string]float64{
+				"energy.energy":         100,
+				"nutrient.carb":         15,
+				"nutrient.fat":          2,
+				"nutrient.protein":      4,
+				"nutrient.dietaryfiber": 2,
+				"vitamin.c":             5,
+			},
+		}),
+	)
+
+	for i, grams := range []float64{400, 500, 200, 600} {
+		assert.NoError(t, fake.Consume(yaziotest.DefaultUsername, yaziotest.ConsumedItem{
+			ID:        uuid.New(),
+			ProductID: productID,
+			Date:      firstDay.AddDate(0, 0, i).Add(8 * time.Hour),
+			Daytime:   "breakfast",
+			Serving:   "portion",
+			Amount:    grams,
+			Quantity:  1,
+		}))
+	}
+
+	return fakeuser.Login(t, fake)
+}
+
+func newTestReporter(t *testing.T, opts ...Option) *Reporter {
+	rp := New(seeded(t), opts...)
+	rp.now = func() time.Time { return time.Date(2025, 4, 14, 9, 0, 0, 0, time.UTC) }
+	return rp
+}
+
+func TestReporter_Build(t *testing.T) {
+	t.Parallel()
+
+	rp := newTestReporter(t,
+		WithGoals(Goals{Energy: 450, Protein: 20}),
+		WithTolerance(0.15),
+		WithKinds(intake.VitaminC, intake.MineralZinc, intake.Fiber),
+		WithProfile(profile),
+		WithPeriod(date.Weekly()),
+	)
+
+	rep, err := rp.Build(context.Background(), week)
+	assert.NoError(t, err)
+
+	assert.Equal(t, rep.Title, "Weekly report")
+	assert.Equal(t, len(rep.Days), 4)
+	assert.Equal(t, rep.Calendar(), 7)
+	assert.Equal(t, rep.Average.Energy, 425.0)
+	assert.Equal(t, rep.Highest.Energy, 600.0)
+	assert.Equal(t, rep.Lowest.Energy, 200.0)
+	assert.Equal(t, len(rep.Buckets), 1)
+
+	assert.Equal(t, *rep.Adherence, Adherence{
+		Tolerance: 0.15,
+		OnTarget:  2, // 400 and 500
+		Days:      4,
+		Energy:    425.0 / 450 * 100,
+		Protein:   17.0 / 20 * 100,
+	})
+	assert.Equal(t, rep.Adherence.Rate(), 0.5)
+
+	assert.Equal(t, len(rep.Best), 2)
+	assert.Equal(t, rep.Best[0].Energy, 400.0)
+	assert.Equal(t, rep.Worst[0].Energy, 200.0)
+
+	var kinds []intake.Kind
+	for _, h := range rep.Highlights {
+		kinds = append(kinds, h.Kind)
+	}
+	assert.DeepEqual(t, kinds, []intake.Kind{intake.VitaminC, intake.Fiber, intake.MineralZinc})
+	assert.Equal(t, rep.Highlights[0].Reference.Status, reference.Deficient)
+	assert.Equal(t, rep.Highlights[2].Days, 0)
+}
+
+func TestReport_Render(t *testing.T) {
+	t.Parallel()
+
+	rep, err := newTestReporter(t,
+		WithTitle("Oats & <porridge>"),
+		WithGoals(Goals{Energy: 450}),
+		WithKinds(intake.VitaminC),
+		WithProfile(profile),
+	).Build(context.Background(), week)
+	assert.NoError(t, err)
+
+	var md bytes.Buffer
+	assert.NoError(t, rep.Render(&md, Markdown))
+
+	for _, want := range []string{
+		"# Oats & <porridge>\n\n2025-04-07 to 2025-04-13: 4 of 7 days logged.\n",
+		"| Daily average     | 425 | 63.8 | 8.5 | 17 |\n",
+		"| Average of goal   | 94% | – | – | – |\n",
+		"Energy from carb 64%, fat 19% and protein 17%.\n",
+		"- On target: 0 of 4 days within 10% of 450 kcal\n",
+		"| Mon 2025-04-07 | 400 | -11% |\n| Tue 2025-04-08 | 500 | +11% |\n",
+		"| vitamin.c | 21.3 mg | 75 mg | 28% | deficient |\n",
+		"_Generated 2025-04-14T09:00:00Z._\n",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, md.String())
+		}
+	}
+
+	var page bytes.Buffer
+	assert.NoError(t, rep.Render(&page, HTML))
+
+	for _, want := range []string{
+		"<title>Oats &amp; &lt;porridge&gt;</title>",
+		`aria-label="Daily energy"`,
+		`<title>2025-04-10: 600 kcal</title>`,
+		`goal 450</text>`,
+		`aria-label="Energy distribution"`,
+		`<title>vitamin.c: 28.3% (deficient)</title>`,
+		`<td class="deficient">deficient</td>`,
+	} {
+		if !strings.Contains(page.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, page.String())
+		}
+	}
+	assert.Equal(t, strings.Count(page.String(), "<svg "), 3)
+
+	if err := rep.Render(&page, "pdf"); !errors.Is(err, ErrUnknownFormat) {
+		t.Fatalf("got %v, want %v", err, ErrUnknownFormat)
+	}
+}
+
+func TestReport_RenderEmpty(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day = date.Range{Start: firstDay.AddDate(0, 0, 5), End: firstDay.AddDate(0, 0, 5)}
+		buf bytes.Buffer
+	)
+
+	rep, err := newTestReporter(t, WithGoals(Goals{Energy: 2000})).Build(context.Background(), day)
+	assert.NoError(t, err)
+	assert.Equal(t, rep.Title, "Daily report")
+	assert.Equal(t, len(rep.Best), 0)
+
+	assert.NoError(t, rep.Render(&buf, Markdown))
+	assert.Equal(t, buf.String(), "# Daily report\n\n"+
+		"2025-04-12 to 2025-04-12: 0 of 1 days logged.\n\n"+
+		"Nothing was logged in this period.\n\n"+
+		"_Generated 2025-04-14T09:00:00Z._\n")
+
+	buf.Reset()
+	assert.NoError(t, rep.Render(&buf, HTML))
+	assert.Equal(t, strings.Contains(buf.String(), "<svg"), false)
+}
+
+func TestReporter_BuildProfileError(t *testing.T) {
+	t.Parallel()
+
+	rp := newTestReporter(t, WithKinds(intake.VitaminC), WithProfile(reference.Profile{Sex: "other"}))
+
+	if _, err := rp.Build(context.Background(), week); !errors.Is(err, reference.ErrUnknownSex) {
+		t.Fatalf("got %v, want %v", err, reference.ErrUnknownSex)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		s       string
+		want    Format
+		wantErr bool
+	}{
+		{s: "markdown", want: Markdown},
+		{s: "MD", want: Markdown},
+		{s: "html", want: HTML},
+		{s: "pdf", wantErr: true},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.s, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseFormat(tb.s)
+			assert.WantErr(t, tb.wantErr, err)
+			assert.Equal(t, got, tb.want)
+		})
+	}
+}
+
+func TestNiceCeil(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		v, want float64
+	}{
+		{0, 1},
+		{660, 1000},
+		{1650, 2000},
+		{2200, 5000},
+		{0.3, 0.5},
+	}
+
+	for _, tb := range testBlocks {
+		assert.Equal(t, niceCeil(tb.v), tb.want)
+	}
+}
@@ -0,0 +1,243 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+
+	"github.com/controlado/go-yazio/pkg/domain/reference"
+)
+
+// Chart geometry, in SVG user units.
+const (
+	chartWidth  = 640.0
+	chartHeight = 200.0
+	chartMargin = 28.0
+	barGap      = 0.2 // barGap is the share of a slot left empty.
+	rowHeight   = 22.0
+	maxPercent  = 200.0 // maxPercent caps the bars of the reference chart.
+)
+
+// Chart colors.
+const (
+	colorBar      = "#4e79a7"
+	colorOnTarget = "#59a14f"
+	colorOff      = "#f28e2b"
+	colorGoal     = "#e15759"
+	colorCarb     = "#f1ce63"
+	colorFat      = "#e15759"
+	colorProtein  = "#4e79a7"
+	colorAlcohol  = "#b07aa1"
+	colorAxis     = "#999"
+)
+
+// svg builds an inline SVG element.
+type svg struct {
+	b strings.Builder
+}
+
+func newSVG(width, height float64, label string) *svg {
+	s := &svg{}
+	fmt.Fprintf(&s.b,
+		`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %s %s" width="%s" height="%s" role="img" aria-label="%s" font-family="sans-serif" font-size="11">`,
+		formatNumber(width), formatNumber(height), formatNumber(width), formatNumber(height), html.EscapeString(label),
+	)
+	return s
+}
+
+func (s *svg) rect(x, y, w, h float64, fill, title string) {
+	fmt.Fprintf(&s.b, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s">`, formatNumber(x), formatNumber(y), formatNumber(w), formatNumber(h), fill)
+	if title != "" {
+		fmt.Fprintf(&s.b, `<title>%s</title>`, html.EscapeString(title))
+	}
+	s.b.WriteString(`</rect>`)
+}
+
+func (s *svg) line(x1, y1, x2, y2 float64, stroke string, dashed bool) {
+	dash := ""
+	if dashed {
+		dash = ` stroke-dasharray="4 3"`
+	}
+	fmt.Fprintf(&s.b, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s"%s/>`, formatNumber(x1), formatNumber(y1), formatNumber(x2), formatNumber(y2), stroke, dash)
+}
+
+func (s *svg) text(x, y float64, anchor, fill, content string) {
+	fmt.Fprintf(&s.b, `<text x="%s" y="%s" text-anchor="%s" fill="%s">%s</text>`, formatNumber(x), formatNumber(y), anchor, fill, html.EscapeString(content))
+}
+
+func (s *svg) String() string {
+	return s.b.String() + `</svg>`
+}
+
+// energyChart draws a bar per calendar day of rep with
+// its energy, and the energy goal as a dashed line.
+func energyChart(rep Report) string {
+	var (
+		dates  = rep.Range.Dates()
+		energy = make(map[string]float64, len(rep.Days))
+		top    = rep.Goals.Energy
+	)
+
+	if len(dates) == 0 {
+		return ""
+	}
+
+	for _, m := range rep.Days {
+		energy[m.Date.Format(layoutDay)] = m.Energy
+		top = max(top, m.Energy)
+	}
+	top = niceCeil(top * 1.1)
+
+	var (
+		s     = newSVG(chartWidth, chartHeight, "Daily energy")
+		plotW = chartWidth - 2*chartMargin
+		plotH = chartHeight - 2*chartMargin
+		slot  = plotW / float64(len(dates))
+		y     = func(v float64) float64 { return chartMargin + plotH - v/top*plotH }
+		every = int(math.Ceil(float64(len(dates)) / 14)) // at most 14 x labels
+	)
+
+	s.line(chartMargin, y(0), chartMargin+plotW, y(0), colorAxis, false)
+	s.text(chartMargin-4, y(top)+4, "end", colorAxis, formatNumber(top))
+	s.text(chartMargin-4, y(0)+4, "end", colorAxis, "0")
+
+	for i, d := range dates {
+		var (
+			key   = d.Format(layoutDay)
+			x     = chartMargin + float64(i)*slot
+			color = colorBar
+		)
+
+		if v, ok := energy[key]; ok {
+			if g := rep.Goals.Energy; g > 0 {
+				color = colorOff
+				if math.Abs(v-g)/g <= toleranceOf(rep) {
+					color = colorOnTarget
+				}
+			}
+			s.rect(x+slot*barGap/2, y(v), slot*(1-barGap), y(0)-y(v), color, key+": "+formatNumber(v)+" kcal")
+		}
+
+		if i%every == 0 {
+			s.text(x+slot/2, y(0)+14, "middle", colorAxis, d.Format("Jan 2"))
+		}
+	}
+
+	if g := rep.Goals.Energy; g > 0 {
+		s.line(chartMargin, y(g), chartMargin+plotW, y(g), colorGoal, true)
+		s.text(chartMargin+plotW, y(g)-4, "end", colorGoal, "goal "+formatNumber(g))
+	}
+
+	return s.String()
+}
+
+func toleranceOf(rep Report) float64 {
+	if rep.Adherence == nil {
+		return defaultTolerance
+	}
+	return rep.Adherence.Tolerance
+}
+
+// niceCeil rounds v up to 1, 2 or 5 times a power of ten.
+func niceCeil(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+
+	pow := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, step := range []float64{1, 2, 5, 10} {
+		if v <= step*pow {
+			return step * pow
+		}
+	}
+	return 10 * pow
+}
+
+// distributionChart draws the energy share of the
+// average macros as a single stacked bar.
+func distributionChart(rep Report) string {
+	d := rep.Distribution
+	if d.Carb+d.Fat+d.Protein+d.Alcohol == 0 {
+		return ""
+	}
+
+	var (
+		s     = newSVG(chartWidth, 2*rowHeight+8, "Energy distribution")
+		x     = 0.0
+		parts = []struct {
+			name    string
+			percent float64
+			color   string
+		}{
+			{"Carb", d.Carb, colorCarb},
+			{"Fat", d.Fat, colorFat},
+			{"Protein", d.Protein, colorProtein},
+			{"Alcohol", d.Alcohol, colorAlcohol},
+		}
+	)
+
+	for _, p := range parts {
+		if p.percent <= 0 {
+			continue
+		}
+
+		w := p.percent / 100 * chartWidth
+		label := p.name + " " + formatNumber(p.percent) + "%"
+		s.rect(x, 0, w, rowHeight, p.color, label)
+		if w > 60 {
+			s.text(x+w/2, rowHeight+14, "middle", "#333", label)
+		}
+		x += w
+	}
+
+	return s.String()
+}
+
+// referenceChart draws the average of each highlight as a
+// percentage of its recommended intake, with a 100% line.
+func referenceChart(rep Report) string {
+	var rows []Highlight
+	for _, h := range rep.Highlights {
+		if h.Reference != nil && h.Days > 0 {
+			rows = append(rows, h)
+		}
+	}
+
+	if len(rows) == 0 {
+		return ""
+	}
+
+	var (
+		labelW = 140.0
+		plotW  = chartWidth - labelW - chartMargin
+		height = float64(len(rows))*rowHeight + chartMargin
+		s      = newSVG(chartWidth, height, "Micronutrients against reference intakes")
+		x      = func(percent float64) float64 { return labelW + min(percent, maxPercent)/maxPercent*plotW }
+	)
+
+	for i, h := range rows {
+		var (
+			y     = float64(i) * rowHeight
+			item  = h.Reference
+			color = colorOnTarget
+		)
+
+		switch item.Status {
+		case reference.Deficient:
+			color = colorOff
+		case reference.Excessive:
+			color = colorGoal
+		}
+
+		s.text(labelW-6, y+rowHeight/2+4, "end", "#333", h.Kind.ID())
+		s.rect(labelW, y+3, x(item.Percent)-labelW, rowHeight-6, color, fmt.Sprintf("%s: %s%% (%s)", h.Kind.ID(), formatNumber(item.Percent), item.Status))
+	}
+
+	bottom := float64(len(rows)) * rowHeight
+	s.line(x(100), 0, x(100), bottom, colorAxis, true)
+	s.text(x(100), bottom+14, "middle", colorAxis, "100%")
+	s.text(x(maxPercent), bottom+14, "end", colorAxis, formatNumber(maxPercent)+"%")
+
+	return s.String()
+}
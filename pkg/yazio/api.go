@@ -4,16 +4,25 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/controlado/go-yazio/internal/application"
 	"github.com/controlado/go-yazio/internal/infra/client"
+	"github.com/controlado/go-yazio/pkg/cache"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
 )
 
 // API is the main struct for interacting with the YAZIO API.
 //
-// It holds the HTTP client used for making requests.
+// It holds the HTTP client used for making requests,
+// and the optional cache shared by the logged users.
 type API struct {
-	client *client.Client
+	client      *client.Client
+	cache       cache.Cache
+	concurrency int
+	rangeWindow int
+	gapFilling  intake.FillMode
+	loc         *time.Location
 }
 
 // New creates a new instance of the [*API].
@@ -22,6 +31,9 @@ func New(opts ...Option) (*API, error) {
 		client: client.New(
 			client.WithBaseURL(baseURL),
 		),
+		concurrency: defaultConcurrency,
+		rangeWindow: defaultRangeWindow,
+		loc:         time.Local,
 	}
 
 	for _, opt := range opts {
@@ -78,12 +90,43 @@ func (a *API) Login(ctx context.Context, cred application.Credentials) (*User, e
 				return nil, ErrInvalidCredentials
 			}
 		}
-		return nil, fmt.Errorf("%s: %w", ErrRequestingToYazio, err)
+		return nil, fmt.Errorf("%w: %w", ErrRequestingToYazio, err)
 	}
 
 	if err := resp.BodyStruct(&dto); err != nil {
-		return nil, fmt.Errorf("%s: %w", ErrDecodingResponse, err)
+		return nil, fmt.Errorf("%w: %w", ErrDecodingResponse, err)
 	}
 
-	return dto.toUser(a.client)
+	newUser, err := dto.toUser(a.client)
+	if err != nil {
+		return nil, err
+	}
+
+	a.configure(newUser)
+	return newUser, nil
+}
+
+// Resume returns the [*User] holding tk, a token issued by a
+// previous [API.Login] (see [NewToken]), without requesting YAZIO.
+//
+// The token isn't checked: an expired one makes the user
+// methods fail with [ErrExpiredToken] until [API.Refresh].
+func (a *API) Resume(tk application.Token) *User {
+	resumed := &User{
+		client: a.client,
+		token:  tk,
+	}
+
+	a.configure(resumed)
+	return resumed
+}
+
+// configure applies the settings of a to u,
+// logged in (or resumed).
+func (a *API) configure(u *User) {
+	u.cache = newUserCache(a.cache, u.fetchData)
+	u.concurrency = a.concurrency
+	u.rangeWindow = a.rangeWindow
+	u.gapFilling = a.gapFilling
+	u.loc = a.loc
 }
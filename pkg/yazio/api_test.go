@@ -2,6 +2,7 @@ package yazio
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"testing"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/controlado/go-yazio/internal/testutil/assert"
 	"github.com/controlado/go-yazio/internal/testutil/server"
 	"github.com/controlado/go-yazio/internal/testutil/times"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
 	"github.com/google/uuid"
 )
 
@@ -144,3 +146,49 @@ func TestAPI_Refresh(t *testing.T) {
 		})
 	}
 }
+
+func TestAPI_Resume(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx  = context.Background()
+		fake = yaziotest.New(t)
+		cred = NewPasswordCred(yaziotest.DefaultUsername, yaziotest.DefaultPassword)
+	)
+
+	api, err := New(WithBaseURL(fake.URL))
+	assert.NoError(t, err)
+
+	logged, err := api.Login(ctx, cred)
+	assert.NoError(t, err)
+
+	stored := logged.Token()
+	resumed := api.Resume(NewToken(stored.Access(), stored.Refresh(), stored.ExpiresAt()))
+
+	data, err := resumed.Data(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, data.FirstName, "João")
+
+	fake.ExpireTokens()
+	expired := api.Resume(NewToken(stored.Access(), stored.Refresh(), times.Past()))
+
+	if _, err := expired.Data(ctx); !errors.Is(err, ErrExpiredToken) {
+		t.Fatalf("got %v, want %v", err, ErrExpiredToken)
+	}
+
+	assert.NoError(t, api.Refresh(ctx, expired))
+	_, err = expired.Data(ctx)
+	assert.NoError(t, err)
+}
+
+func TestAPI_LoginUnreachable(t *testing.T) {
+	t.Parallel()
+
+	api, err := New(WithBaseURL("http://127.0.0.1:0"))
+	assert.NoError(t, err)
+
+	cred := NewPasswordCred(yaziotest.DefaultUsername, yaziotest.DefaultPassword)
+	if _, err := api.Login(context.Background(), cred); !errors.Is(err, ErrRequestingToYazio) {
+		t.Fatalf("got %v, want %v", err, ErrRequestingToYazio)
+	}
+}
@@ -0,0 +1,187 @@
+package yazio
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/cache"
+	"github.com/controlado/go-yazio/pkg/domain/date"
+)
+
+const (
+	cacheKeySep = "/"
+
+	dataCacheKind   = "data"
+	macrosCacheKind = "macros"
+	intakeCacheKind = "intake"
+)
+
+type bypassCacheKey struct{}
+
+// BypassCache returns a copy of ctx that makes read methods of [User]
+// skip cached responses and always reach YAZIO.
+//
+// Fresh responses are still stored, so it can be used
+// to force a cache refresh.
+func BypassCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+func isCacheBypassed(ctx context.Context) bool {
+	bypassed, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypassed
+}
+
+// userCache scopes a [cache.Cache] to a single account.
+//
+// A nil *userCache is valid and caches nothing.
+type userCache struct {
+	store cache.Cache
+
+	mu        sync.Mutex
+	namespace string                                            // empty until resolved
+	identify  func(ctx context.Context) (getUserDataDTO, error) // fetches the profile, uncached
+}
+
+// newUserCache returns nil when store is nil (caching disabled).
+//
+// The namespace is derived from the account ID, resolved through
+// identify on first use, so it survives token refreshes and
+// resumed sessions alike. It's hashed to keep account
+// identifiers out of storage keys.
+func newUserCache(store cache.Cache, identify func(context.Context) (getUserDataDTO, error)) *userCache {
+	if store == nil {
+		return nil
+	}
+
+	uc := &userCache{
+		store:    store,
+		identify: identify,
+	}
+
+	return uc
+}
+
+// resolve returns the namespace of the account, or
+// an empty string when it can't be identified yet.
+//
+// The profile fetched to identify the account is
+// stored too, sparing the next [User.Data] call.
+func (uc *userCache) resolve(ctx context.Context) string {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.namespace != "" {
+		return uc.namespace
+	}
+
+	dto, err := uc.identify(ctx)
+	if err != nil || dto.ID == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(strings.ToLower(dto.ID)))
+	uc.namespace = hex.EncodeToString(sum[:8])
+
+	if value, err := json.Marshal(dto); err == nil {
+		uc.store.Set(uc.namespace+cacheKeySep+dataCacheKind, value)
+	}
+
+	return uc.namespace
+}
+
+// key returns the storage key of parts, or an empty
+// string (caching nothing) when the account is unknown.
+func (uc *userCache) key(ctx context.Context, parts ...string) string {
+	if uc == nil {
+		return ""
+	}
+
+	ns := uc.resolve(ctx)
+	if ns == "" {
+		return ""
+	}
+
+	return strings.Join(append([]string{ns}, parts...), cacheKeySep)
+}
+
+// rangeKey is like key, but returns an empty string when r
+// reaches today or later: those days can still change from
+// other devices, so only past (settled) ranges are cached.
+func (uc *userCache) rangeKey(ctx context.Context, r date.Range, parts ...string) string {
+	var (
+		end   = r.End.Format(layoutISO)
+		today = time.Now().In(r.End.Location()).Format(layoutISO)
+	)
+
+	if end >= today {
+		return ""
+	}
+
+	parts = append(parts, r.Start.Format(layoutISO), end)
+	return uc.key(ctx, parts...)
+}
+
+// load decodes the value stored under key into dst,
+// reporting whether it was found.
+func (uc *userCache) load(ctx context.Context, key string, dst any) bool {
+	if uc == nil || key == "" || isCacheBypassed(ctx) {
+		return false
+	}
+
+	value, ok := uc.store.Get(key)
+	if !ok {
+		return false
+	}
+
+	return json.Unmarshal(value, dst) == nil
+}
+
+func (uc *userCache) save(key string, src any) {
+	if uc == nil || key == "" {
+		return
+	}
+
+	if value, err := json.Marshal(src); err == nil {
+		uc.store.Set(key, value)
+	}
+}
+
+// invalidate drops every cached range (macros and intakes)
+// that contains day, since its aggregates are now stale.
+func (uc *userCache) invalidate(ctx context.Context, day time.Time) {
+	if uc == nil {
+		return
+	}
+
+	dayISO := day.Format(layoutISO)
+
+	for _, kind := range []string{macrosCacheKind, intakeCacheKind} {
+		prefix := uc.key(ctx, kind)
+		if prefix == "" {
+			return
+		}
+		prefix += cacheKeySep
+
+		for _, key := range uc.store.Keys(prefix) {
+			parts := strings.Split(key, cacheKeySep)
+			if len(parts) < 2 {
+				continue
+			}
+
+			var ( // keys always end with start/end (ISO)
+				start = parts[len(parts)-2]
+				end   = parts[len(parts)-1]
+			)
+
+			if start <= dayISO && dayISO <= end {
+				uc.store.Delete(key)
+			}
+		}
+	}
+}
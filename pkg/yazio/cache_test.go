@@ -0,0 +1,194 @@
+package yazio
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/cache"
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/diary"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/meal"
+	"github.com/controlado/go-yazio/pkg/visibility"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
+	"github.com/google/uuid"
+)
+
+type countingRequester struct {
+	count atomic.Int64
+}
+
+func (cr *countingRequester) Do(r *http.Request) (*http.Response, error) {
+	cr.count.Add(1)
+	return http.DefaultClient.Do(r)
+}
+
+func TestUser_Cache(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		fake      = yaziotest.New(t)
+		requester = new(countingRequester)
+		today     = time.Now()
+		yesterday = today.AddDate(0, 0, -1)
+	)
+
+	api, err := New(
+		WithBaseURL(fake.URL),
+		WithRequester(requester),
+		WithCache(cache.NewMemory(16, time.Hour)),
+	)
+	assert.NoError(t, err)
+
+	cred := NewPasswordCred(yaziotest.DefaultUsername, yaziotest.DefaultPassword)
+	u, err := api.Login(ctx, cred)
+	assert.NoError(t, err)
+
+	rice, err := food.New("Rice", food.Miscellaneous, food.Nutrients{
+		intake.Energy: 130, intake.Fat: 0.3,
+		intake.Protein: 2.7, intake.Carb: 28,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, u.AddFood(ctx, rice, visibility.PrivateFood))
+
+	err = fake.Consume(yaziotest.DefaultUsername, yaziotest.ConsumedItem{
+		ProductID: rice.ID,
+		Date:      yesterday,
+		Amount:    100,
+	})
+	assert.NoError(t, err)
+
+	var (
+		pastRange  = date.Range{Start: yesterday, End: yesterday}
+		todayRange = date.Range{Start: yesterday, End: today}
+	)
+
+	requestsBefore := requester.count.Load()
+	for range 3 {
+		_, err := u.Macros(ctx, pastRange)
+		assert.NoError(t, err)
+		_, err = u.Data(ctx)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, requester.count.Load()-requestsBefore, 2)
+
+	_, err = u.Macros(BypassCache(ctx), pastRange)
+	assert.NoError(t, err)
+	assert.Equal(t, requester.count.Load()-requestsBefore, 3)
+
+	mr, err := u.Macros(ctx, todayRange)
+	assert.NoError(t, err)
+	assert.Equal(t, len(mr), 1)
+
+	err = fake.Consume(yaziotest.DefaultUsername, yaziotest.ConsumedItem{
+		ProductID: rice.ID,
+		Date:      today,
+		Amount:    100,
+	})
+	assert.NoError(t, err)
+
+	mr, err = u.Macros(ctx, todayRange) // logged elsewhere, never cached
+	assert.NoError(t, err)
+	assert.Equal(t, len(mr), 2)
+
+	serving := food.Serving{Kind: food.Portion, Amount: 100}
+	assert.NoError(t, u.AddEntry(ctx, diary.Entry{
+		ID:      uuid.New(),
+		Date:    yesterday,
+		Meal:    meal.Lunch,
+		FoodID:  rice.ID,
+		Serving: serving,
+	}))
+
+	mr, err = u.Macros(ctx, pastRange) // invalidated by AddEntry
+	assert.NoError(t, err)
+	assert.Equal(t, mr[0].Energy, 260.0)
+
+	requestsBefore = requester.count.Load()
+	_, err = u.Macros(ctx, pastRange)
+	assert.NoError(t, err)
+	assert.Equal(t, requester.count.Load()-requestsBefore, 0)
+
+	// resumed users share the namespace despite the new token,
+	// paying only the request identifying the account
+	assert.NoError(t, api.Refresh(ctx, u))
+	resumed := api.Resume(u.Token())
+
+	requestsBefore = requester.count.Load()
+	_, err = resumed.Macros(ctx, pastRange)
+	assert.NoError(t, err)
+	_, err = resumed.Data(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, requester.count.Load()-requestsBefore, 1)
+}
+
+func TestUser_CacheInvalidation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		fake      = yaziotest.New(t)
+		requester = new(countingRequester)
+		y, m, d   = time.Now().Date()
+		day       = func(ago int) time.Time { return time.Date(y, m, d-ago, 0, 0, 0, 0, time.Local) }
+		week      = date.Range{Start: day(7), End: day(3)}
+		later     = date.Range{Start: day(2), End: day(1)}
+	)
+
+	api, err := New(
+		WithBaseURL(fake.URL),
+		WithRequester(requester),
+		WithCache(cache.NewMemory(16, time.Hour)),
+	)
+	assert.NoError(t, err)
+
+	u, err := api.Login(ctx, NewPasswordCred(yaziotest.DefaultUsername, yaziotest.DefaultPassword))
+	assert.NoError(t, err)
+
+	rice, err := food.New("Rice", food.Miscellaneous, food.Nutrients{
+		intake.Energy: 130, intake.Fat: 0.3,
+		intake.Protein: 2.7, intake.Carb: 28,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, u.AddFood(ctx, rice, visibility.PrivateFood))
+
+	// cache both past ranges
+	mr, err := u.Macros(ctx, week)
+	assert.NoError(t, err)
+	assert.Equal(t, len(mr), 0)
+	_, err = u.Intake(ctx, intake.Protein, week)
+	assert.NoError(t, err)
+	_, err = u.Macros(ctx, later)
+	assert.NoError(t, err)
+
+	assert.NoError(t, u.AddEntry(ctx, diary.Entry{
+		ID:      uuid.New(),
+		Date:    day(4).Add(12 * time.Hour),
+		Meal:    meal.Lunch,
+		FoodID:  rice.ID,
+		Serving: food.Serving{Kind: food.Portion, Amount: 100},
+	}))
+
+	requestsBefore := requester.count.Load()
+
+	mr, err = u.Macros(ctx, week)
+	assert.NoError(t, err)
+	assert.Equal(t, len(mr), 1)
+	assert.Equal(t, mr[0].Energy, 130.0)
+
+	sr, err := u.Intake(ctx, intake.Protein, week)
+	assert.NoError(t, err)
+	assert.Equal(t, len(sr), 1)
+	assert.Equal(t, sr[0].Value, 2.7)
+
+	_, err = u.Macros(ctx, later) // doesn't hold the day
+	assert.NoError(t, err)
+
+	assert.Equal(t, requester.count.Load()-requestsBefore, 2)
+}
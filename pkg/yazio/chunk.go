@@ -0,0 +1,145 @@
+package yazio
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/controlado/go-yazio/pkg/domain/date"
+)
+
+type semaphoreKey struct{}
+
+// withSemaphore returns a copy of ctx carrying a semaphore of n
+// slots, shared by every window fetched under it, so calls fanning
+// out (like [User.Intakes]) respect [WithConcurrency] as a whole.
+// A ctx already carrying one is returned as is.
+func withSemaphore(ctx context.Context, n int) context.Context {
+	if _, ok := ctx.Value(semaphoreKey{}).(chan struct{}); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, semaphoreKey{}, make(chan struct{}, max(n, 1)))
+}
+
+// acquire takes a slot of the semaphore of ctx, returning
+// the func releasing it, or the error of ctx once done.
+func acquire(ctx context.Context) (release func(), err error) {
+	semaphore := ctx.Value(semaphoreKey{}).(chan struct{})
+
+	select {
+	case semaphore <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	release = func() { <-semaphore }
+	if err := ctx.Err(); err != nil { // both were ready
+		release()
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// fetchWindows splits r into windows of [User.rangeWindow] days and
+// fetches each one, running up to [User.concurrency] at the same time.
+//
+// The results are stitched in chronological order (by dayOf), keeping
+// only the first value of days returned by more than one window. The
+// first failure cancels the pending windows and is returned as is;
+// if ctx is done before every window is fetched, its error is.
+func fetchWindows[S ~[]E, E any](
+	ctx context.Context,
+	u *User,
+	r date.Range,
+	dayOf func(E) time.Time,
+	fetch func(context.Context, date.Range) (S, error),
+) (S, error) {
+	ctx = withSemaphore(ctx, u.concurrency)
+
+	windows := r.Split(u.rangeWindow)
+	if len(windows) == 1 {
+		release, err := acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		return fetch(ctx, windows[0])
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		skipped  atomic.Bool
+		parts    = make([]S, len(windows))
+	)
+
+	for i, w := range windows {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			release, err := acquire(ctx)
+			if err != nil { // another window failed, or parent is done
+				skipped.Store(true)
+				return
+			}
+			defer release()
+
+			part, err := fetch(ctx, w)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			parts[i] = part
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if skipped.Load() {
+		return nil, parent.Err()
+	}
+
+	return stitch(parts, dayOf), nil
+}
+
+// stitch concatenates parts sorted by day, dropping
+// values of days already seen in previous parts.
+func stitch[S ~[]E, E any](parts []S, dayOf func(E) time.Time) S {
+	var (
+		out  S
+		seen = make(map[string]bool)
+	)
+
+	for _, part := range parts {
+		for _, e := range part {
+			day := dayOf(e).Format(layoutISO)
+			if seen[day] {
+				continue
+			}
+			seen[day] = true
+			out = append(out, e)
+		}
+	}
+
+	slices.SortStableFunc(out, func(a, b E) int {
+		return dayOf(a).Compare(dayOf(b))
+	})
+
+	return out
+}
@@ -0,0 +1,207 @@
+package yazio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/visibility"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
+)
+
+func TestUser_MacrosChunked(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		fake      = yaziotest.New(t)
+		requester = new(countingRequester)
+		firstDay  = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		lastDay   = firstDay.AddDate(0, 0, 4)
+	)
+
+	testBlocks := []struct {
+		name         string
+		window       int
+		concurrency  int
+		wantRequests int64
+	}{
+		{name: "no chunking", window: 0, concurrency: 1, wantRequests: 2},
+		{name: "sequential windows", window: 2, concurrency: 1, wantRequests: 6},
+		{name: "concurrent windows", window: 2, concurrency: 3, wantRequests: 6},
+	}
+
+	api, err := New(WithBaseURL(fake.URL))
+	assert.NoError(t, err)
+
+	cred := NewPasswordCred(yaziotest.DefaultUsername, yaziotest.DefaultPassword)
+	seeder, err := api.Login(ctx, cred)
+	assert.NoError(t, err)
+
+	oats, err := food.New("Oats", food.Miscellaneous, food.Nutrients{
+		intake.Energy: 380, intake.Fat: 7,
+		intake.Protein: 13, intake.Carb: 60,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, seeder.AddFood(ctx, oats, visibility.PrivateFood))
+
+	for i := range 5 {
+		err := fake.Consume(yaziotest.DefaultUsername, yaziotest.ConsumedItem{
+			ProductID: oats.ID,
+			Date:      firstDay.AddDate(0, 0, i),
+			Amount:    float64(100 * (i + 1)),
+		})
+		assert.NoError(t, err)
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			requester.count.Store(0)
+
+			api, err := New(
+				WithBaseURL(fake.URL),
+				WithRequester(requester),
+				WithRangeWindow(tb.window),
+				WithConcurrency(tb.concurrency),
+			)
+			assert.NoError(t, err)
+
+			u, err := api.Login(ctx, cred)
+			assert.NoError(t, err)
+
+			r := date.Range{Start: firstDay, End: lastDay}
+
+			mr, err := u.Macros(ctx, r)
+			assert.NoError(t, err)
+			assert.Equal(t, len(mr), 5)
+
+			sr, err := u.Intake(ctx, intake.Carb, r)
+			assert.NoError(t, err)
+			assert.Equal(t, len(sr), 5)
+
+			for i := range 5 {
+				wantDay := firstDay.AddDate(0, 0, i)
+				assert.Equal(t, mr[i].Date, wantDay)
+				assert.Equal(t, mr[i].Energy, float64(380*(i+1)))
+				assert.Equal(t, sr[i].Date, wantDay)
+			}
+
+			// login + macros windows + intake windows
+			assert.Equal(t, requester.count.Load(), 1+tb.wantRequests)
+		})
+	}
+}
+
+func TestStitch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day   = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		parts = []intake.MacrosRange{
+			{{Date: day.AddDate(0, 0, 1), Energy: 2}, {Date: day, Energy: 1}},
+			{{Date: day.AddDate(0, 0, 1), Energy: 99}, {Date: day.AddDate(0, 0, 2), Energy: 3}},
+		}
+	)
+
+	got := stitch(parts, func(m intake.Macros) time.Time { return m.Date })
+	assert.DeepEqual(t, got, intake.MacrosRange{
+		{Date: day, Energy: 1},
+		{Date: day.AddDate(0, 0, 1), Energy: 2},
+		{Date: day.AddDate(0, 0, 2), Energy: 3},
+	})
+}
+
+// gatedRequester tracks the requests in flight, holding each for
+// a moment so concurrent ones overlap, and calls after (if set)
+// once a request is done.
+type gatedRequester struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	after       func()
+}
+
+func (gr *gatedRequester) Do(r *http.Request) (*http.Response, error) {
+	gr.mu.Lock()
+	gr.inFlight++
+	gr.maxInFlight = max(gr.maxInFlight, gr.inFlight)
+	gr.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+	resp, err := http.DefaultClient.Do(r)
+
+	gr.mu.Lock()
+	gr.inFlight--
+	gr.mu.Unlock()
+
+	if gr.after != nil {
+		gr.after()
+	}
+
+	return resp, err
+}
+
+func TestUser_ChunkedConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fake      = yaziotest.New(t)
+		requester = new(gatedRequester)
+		firstDay  = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		r         = date.Range{Start: firstDay, End: firstDay.AddDate(0, 0, 5)}
+	)
+
+	api, err := New(
+		WithBaseURL(fake.URL),
+		WithRequester(requester),
+		WithRangeWindow(2),
+		WithConcurrency(2),
+	)
+	assert.NoError(t, err)
+
+	u, err := api.Login(context.Background(), NewPasswordCred(yaziotest.DefaultUsername, yaziotest.DefaultPassword))
+	assert.NoError(t, err)
+
+	_, err = u.Intakes(context.Background(), []intake.Kind{intake.Carb, intake.Fat, intake.Protein}, r)
+	assert.NoError(t, err)
+	assert.Equal(t, requester.maxInFlight, 2) // not 2 per kind
+}
+
+func TestUser_ChunkedCancel(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fake        = yaziotest.New(t)
+		ctx, cancel = context.WithCancel(context.Background())
+		requester   = new(gatedRequester)
+		firstDay    = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		r           = date.Range{Start: firstDay, End: firstDay.AddDate(0, 0, 5)}
+	)
+	defer cancel()
+
+	api, err := New(
+		WithBaseURL(fake.URL),
+		WithRequester(requester),
+		WithRangeWindow(2),
+		WithConcurrency(1),
+	)
+	assert.NoError(t, err)
+
+	u, err := api.Login(ctx, NewPasswordCred(yaziotest.DefaultUsername, yaziotest.DefaultPassword))
+	assert.NoError(t, err)
+
+	requester.after = cancel // once the first window is fetched
+
+	mr, err := u.Macros(ctx, r)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want %v", err, context.Canceled)
+	}
+	assert.Equal(t, len(mr), 0)
+}
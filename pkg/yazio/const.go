@@ -29,11 +29,19 @@ const (
 	loginEndpoint         string = "/v18/oauth/token"
 	userDataEndpoint      string = "/v18/user"
 	entryFoodEndpoint     string = "/v18/user/consumed-items"
+	diaryEndpoint         string = "/v18/user/consumed-items" // GET
 	addFoodEndpoint       string = "/v18/user/products"
+	productEndpoint       string = "/v18/products/" // GET, followed by the product ID
 	singleIntakesEndpoint string = "/v18/user/consumed-items/specific-nutrient-daily"
 	macrosIntakesEndpoint string = "/v18/user/consumed-items/nutrients-daily"
 )
 
+// Client
+const (
+	defaultConcurrency int = 4
+	defaultRangeWindow int = 90 // days
+)
+
 // Time layout
 const (
 	layoutISO  string = "2006-01-02"
@@ -2,11 +2,15 @@ package yazio
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/controlado/go-yazio/internal/infra/client"
+	"github.com/controlado/go-yazio/pkg/domain/diary"
 	"github.com/controlado/go-yazio/pkg/domain/food"
 	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/meal"
+	"github.com/controlado/go-yazio/pkg/domain/unit"
 	"github.com/controlado/go-yazio/pkg/domain/user"
 	"github.com/controlado/go-yazio/pkg/visibility"
 	"github.com/google/uuid"
@@ -137,6 +141,7 @@ func (d getSingleIntakeDTO) toRangeSingle(k intake.Kind) (sr intake.SingleRange,
 		sr = append(sr, s)
 	}
 
+	sr.Sort() // map iteration order is random
 	return sr, nil
 }
 
@@ -197,3 +202,107 @@ func newAddFoodBody(f food.Food, vis visibility.Food) client.Payload[any] {
 		"servings":   mapServings(f.Servings),
 	}
 }
+
+type getProductDTO struct {
+	Name      string             `json:"name"`
+	Category  string             `json:"category"`
+	BaseUnit  string             `json:"base_unit"`
+	Nutrients map[string]float64 `json:"nutrients"`
+	Servings  servingsDTO        `json:"servings"`
+}
+
+// toFood converts the product identified by id. Nutrients
+// this client doesn't know are left out.
+func (d *getProductDTO) toFood(id food.ID) (food.Food, error) {
+	f := food.Food{
+		ID:        id,
+		Name:      d.Name,
+		BaseUnit:  unit.Base(d.BaseUnit),
+		Nutrients: make(food.Nutrients, len(d.Nutrients)),
+		Servings:  make([]food.Serving, 0, len(d.Servings)),
+	}
+
+	if d.Name == "" {
+		return f, fmt.Errorf("product %s without name", id)
+	}
+
+	if f.BaseUnit == "" {
+		f.BaseUnit = unit.Gram
+	}
+
+	// categories and serving kinds missing from the catalogue
+	// (e.g. added to YAZIO later) are kept as is
+	f.Category, _ = food.LookupCategory(d.Category)
+
+	for nutrientID, value := range d.Nutrients {
+		if k, err := intake.KindByID(nutrientID); err == nil {
+			f.Nutrients[k] = value
+		}
+	}
+
+	for _, s := range d.Servings {
+		kind, _ := food.LookupServingKind(s.Type)
+		f.Servings = append(f.Servings, food.Serving{Kind: kind, Amount: s.Amount})
+	}
+
+	return f, nil
+}
+
+type getDiaryDTO struct {
+	Products []consumedProductDTO `json:"products"`
+}
+
+type consumedProductDTO struct {
+	ID        string   `json:"id"`
+	Date      string   `json:"date"`
+	Daytime   string   `json:"daytime"`
+	ProductID string   `json:"product_id"`
+	Serving   string   `json:"serving"`
+	Amount    float64  `json:"amount"`
+	Quantity  *float64 `json:"serving_quantity"`
+}
+
+// toEntries converts the entries, reading their
+// wall clock times in loc.
+func (d *getDiaryDTO) toEntries(loc *time.Location) ([]diary.Entry, error) {
+	entries := make([]diary.Entry, 0, len(d.Products))
+
+	for _, p := range d.Products {
+		entryID, err := uuid.Parse(p.ID)
+		if err != nil {
+			return nil, fmt.Errorf("parsing entry uuid (%q): %w", p.ID, err)
+		}
+
+		productID, err := uuid.Parse(p.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("parsing entry product uuid (%q): %w", p.ProductID, err)
+		}
+
+		entryDate, err := time.ParseInLocation(layoutDate, p.Date, loc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing entry date (%q): %w", p.Date, err)
+		}
+
+		quantity := 1.0 // entries logged by base amount have no quantity
+		if p.Quantity != nil && *p.Quantity > 0 {
+			quantity = *p.Quantity
+		}
+
+		kind, _ := food.LookupServingKind(p.Serving) // none when logged by base amount
+
+		entries = append(entries, diary.Entry{
+			ID:       entryID,
+			Date:     entryDate,
+			Meal:     meal.Time(p.Daytime),
+			FoodID:   productID,
+			Serving:  food.Serving{Kind: kind, Amount: p.Amount},
+			Quantity: quantity,
+		})
+	}
+
+	slices.SortStableFunc(entries, func(a, b diary.Entry) int {
+		return a.Date.Compare(b.Date)
+	})
+
+	return entries, nil
+}
@@ -0,0 +1,32 @@
+package yazio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+)
+
+func TestGetSingleIntakeDTO_toRangeSingle(t *testing.T) {
+	t.Parallel()
+
+	dto := getSingleIntakeDTO{
+		"2025-04-14": 3,
+		"2025-04-12": 1,
+		"2025-04-15": 4,
+		"2025-04-13": 2,
+		"2025-04-11": 0,
+	}
+
+	for range 10 { // map iteration order changes between runs
+		sr, err := dto.toRangeSingle(intake.Water)
+		assert.NoError(t, err)
+
+		for i, s := range sr {
+			wantDate := time.Date(2025, 4, 11+i, 0, 0, 0, 0, time.UTC)
+			assert.Equal(t, s.Date, wantDate)
+			assert.Equal(t, s.Value, float64(i))
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package yazio
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+)
 
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
@@ -10,3 +16,39 @@ var (
 	ErrRequestingToYazio = errors.New("failed to request to yazio's api")
 	ErrDecodingResponse  = errors.New("failed to decode response's body -> internal dto")
 )
+
+// KindError is the failure of fetching a single [intake.Kind].
+type KindError struct {
+	Kind intake.Kind
+	Err  error
+}
+
+// IntakesError reports the kinds [User.Intakes] failed to fetch.
+//
+// It unwraps to every underlying error, so [errors.Is]
+// works with [ErrExpiredToken] and friends.
+type IntakesError struct {
+	Failed    []KindError
+	Requested int
+}
+
+func (e *IntakesError) Error() string {
+	failures := make([]string, len(e.Failed))
+	for i, ke := range e.Failed {
+		failures[i] = fmt.Sprintf("%s: %v", ke.Kind.ID(), ke.Err)
+	}
+
+	return fmt.Sprintf("fetching %d of %d intake kinds: %s",
+		len(e.Failed),
+		e.Requested,
+		strings.Join(failures, "; "),
+	)
+}
+
+func (e *IntakesError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, ke := range e.Failed {
+		errs[i] = ke.Err
+	}
+	return errs
+}
@@ -1,6 +1,12 @@
 package yazio
 
-import "github.com/controlado/go-yazio/internal/infra/client"
+import (
+	"time"
+
+	"github.com/controlado/go-yazio/internal/infra/client"
+	"github.com/controlado/go-yazio/pkg/cache"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+)
 
 type Option func(a *API)
 
@@ -15,3 +21,67 @@ func WithBaseURL(bu string) Option {
 		a.client.BaseURL = bu
 	}
 }
+
+// WithCache makes users logged through the [API] cache
+// the responses of [User.Data], [User.Macros] and [User.Intake]
+// in c, keyed per account (its ID) and request.
+//
+// Only ranges ending before today are cached, since days
+// still being logged (e.g. from the phone) change anyway.
+// Cached ranges containing a day written by [User.AddEntry]
+// are invalidated. Use [BypassCache] to skip the cache
+// on a single call.
+func WithCache(c cache.Cache) Option {
+	return func(a *API) {
+		a.cache = c
+	}
+}
+
+// WithConcurrency bounds how many requests a single call
+// like [User.Intakes] or [User.Macros] runs at the same time.
+//
+// Use 1 to fetch the windows of long ranges sequentially.
+//
+// Values below 1 are ignored.
+func WithConcurrency(n int) Option {
+	return func(a *API) {
+		if n > 0 {
+			a.concurrency = n
+		}
+	}
+}
+
+// WithRangeWindow defines the size (in days) of the windows
+// [User.Macros] and [User.Intake] split long ranges into.
+//
+// Values below 1 disable the splitting, sending every
+// range in a single request.
+func WithRangeWindow(days int) Option {
+	return func(a *API) {
+		a.rangeWindow = days
+	}
+}
+
+// WithGapFilling makes [User.Intake] (and [User.Intakes]) fill
+// the days of the requested range without data, see
+// [intake.SingleRange.Fill].
+//
+// The default, [intake.FillNone], leaves them out.
+func WithGapFilling(mode intake.FillMode) Option {
+	return func(a *API) {
+		a.gapFilling = mode
+	}
+}
+
+// WithLocation sets the time zone of the diary: YAZIO stores
+// the wall clock of entries, so [User.Diary] reads them (and
+// [User.AddEntry] writes them) in loc.
+//
+// It defaults to [time.Local]. A nil loc is ignored.
+func WithLocation(loc *time.Location) Option {
+	return func(a *API) {
+		if loc != nil {
+			a.loc = loc
+		}
+	}
+}
@@ -15,6 +15,16 @@ type Token struct {
 	refresh   string
 }
 
+// NewToken rebuilds a [*Token] from its parts, e.g. to resume
+// a stored session with [API.Resume] instead of logging in again.
+func NewToken(access, refresh string, expiresAt time.Time) *Token {
+	return &Token{
+		expiresAt: expiresAt,
+		access:    access,
+		refresh:   refresh,
+	}
+}
+
 func (t *Token) String() string {
 	var tokenStatus = "Valid"
 
@@ -2,13 +2,16 @@ package yazio
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/controlado/go-yazio/internal/application"
 	"github.com/controlado/go-yazio/internal/infra/client"
 	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/diary"
 	"github.com/controlado/go-yazio/pkg/domain/food"
 	"github.com/controlado/go-yazio/pkg/domain/intake"
 	"github.com/controlado/go-yazio/pkg/domain/meal"
@@ -26,8 +29,22 @@ import (
 // The zero value is not functional; obtain a User through the
 // login flow provided in application.API.
 type User struct {
-	client *client.Client
-	token  application.Token
+	client      *client.Client
+	token       application.Token
+	cache       *userCache
+	concurrency int
+	rangeWindow int
+	gapFilling  intake.FillMode
+	loc         *time.Location // loc is the time zone of the diary wall clock.
+}
+
+// location returns the time zone of the diary,
+// [time.Local] unless set through [WithLocation].
+func (u *User) location() *time.Location {
+	if u.loc == nil {
+		return time.Local
+	}
+	return u.loc
 }
 
 // Token returns the [application.Token] held by u.
@@ -38,6 +55,7 @@ func (u *User) Token() application.Token {
 // EntryFood adds a food-intake to the authenticated user's diary.
 //
 // It always targets today, saving the [meal.Time] [food.ID] [food.Serving].
+// Use [User.AddEntry] to log other days.
 //
 //   - YAZIO does not validate the product ID:
 //
@@ -49,10 +67,49 @@ func (u *User) Token() application.Token {
 //   - [ErrExpiredToken]
 //   - [ErrRequestingToYazio]
 func (u *User) EntryFood(ctx context.Context, mealTime meal.Time, foodID food.ID, serving food.Serving) error {
+	e := diary.Entry{
+		ID:       uuid.New(),
+		Date:     time.Now(),
+		Meal:     mealTime,
+		FoodID:   foodID,
+		Serving:  serving,
+		Quantity: 1,
+	}
+
+	err := u.AddEntry(ctx, e)
+	if errors.Is(err, diary.ErrAlreadyExists) {
+		// theoretically it's not possible because
+		// we generate a uuid for each call.
+		return food.ErrAlreadyExists
+	}
+
+	return err
+}
+
+// AddEntry logs e in the authenticated user's diary, on the
+// day (and time) of e.Date in the location set by [WithLocation].
+//
+// The ID of e identifies the entry: logging an entry with an
+// ID already in the diary fails, which makes retries safe.
+// A zero quantity is logged as one serving. Cached ranges
+// holding the day of e (see [WithCache]) are dropped.
+//
+// Like [User.EntryFood], YAZIO does not validate e.FoodID.
+//
+// On failure the error wraps either:
+//   - [ErrExpiredToken]
+//   - [ErrRequestingToYazio]
+//   - [diary.ErrAlreadyExists]
+func (u *User) AddEntry(ctx context.Context, e diary.Entry) error {
 	if u.token.IsExpired() {
 		return ErrExpiredToken
 	}
 
+	quantity := e.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+
 	var (
 		req = client.Request{
 			Method:   http.MethodPost,
@@ -61,13 +118,13 @@ func (u *User) EntryFood(ctx context.Context, mealTime meal.Time, foodID food.ID
 			Body: client.Payload[any]{
 				"products": []map[string]any{
 					{
-						"id":               uuid.New(),
-						"date":             time.Now().Format(layoutDate),
-						"daytime":          mealTime,
-						"product_id":       foodID,
-						"serving":          serving.Kind,
-						"amount":           serving.Amount,
-						"serving_quantity": 1,
+						"id":               e.ID,
+						"date":             e.Date.In(u.location()).Format(layoutDate),
+						"daytime":          e.Meal,
+						"product_id":       e.FoodID,
+						"serving":          e.Serving.Kind,
+						"amount":           e.Serving.Amount,
+						"serving_quantity": quantity,
 					},
 				},
 				"simple_products": []any{},
@@ -83,20 +140,21 @@ func (u *User) EntryFood(ctx context.Context, mealTime meal.Time, foodID food.ID
 			case http.StatusUnauthorized:
 				return ErrExpiredToken
 			case http.StatusConflict:
-				// theoretically it's not possible because
-				// we generate a uuid for each call.
-				return food.ErrAlreadyExists
+				return fmt.Errorf("%w: %s", diary.ErrAlreadyExists, e.ID)
 			}
 		}
-		return fmt.Errorf("%s: %w", ErrRequestingToYazio, err)
+		return fmt.Errorf("%w: %w", ErrRequestingToYazio, err)
 	}
 
+	u.cache.invalidate(ctx, e.Date.In(u.location()))
 	return nil
 }
 
 // AddFood registers a new food (product) using the account.
 //
 // AddFood doesn't entry a new intake. Just regist a new food.
+// YAZIO accepts implausible nutrients, so consider
+// checking f with [food.Food.Validate] first.
 //
 // On failure the error wraps either:
 //   - [ErrExpiredToken]
@@ -139,7 +197,7 @@ func (u *User) AddFood(ctx context.Context, f food.Food, vis visibility.Food) er
 				return food.ErrAlreadyExists
 			}
 		}
-		return fmt.Errorf("%s: %w", ErrRequestingToYazio, err)
+		return fmt.Errorf("%w: %w", ErrRequestingToYazio, err)
 	}
 
 	return nil
@@ -161,11 +219,75 @@ func (u *User) Data(ctx context.Context) (d user.Data, err error) {
 	}
 
 	var (
-		dto getUserDataDTO
+		dto      getUserDataDTO
+		cacheKey = u.cache.key(ctx, dataCacheKind)
+	)
+
+	if u.cache.load(ctx, cacheKey, &dto) {
+		return dto.toUserData()
+	}
+
+	if dto, err = u.fetchData(ctx); err != nil {
+		return d, err
+	}
+
+	u.cache.save(cacheKey, dto)
+	return dto.toUserData()
+}
+
+// fetchData requests the profile of [User.Data], skipping the cache.
+func (u *User) fetchData(ctx context.Context) (dto getUserDataDTO, err error) {
+	req := client.Request{
+		Method:   http.MethodGet,
+		Endpoint: userDataEndpoint,
+		Headers:  defaultHeaders(u.token),
+	}
+
+	resp, err := u.client.Request(ctx, req)
+	if err != nil {
+		if resp.Response != nil {
+			switch resp.StatusCode {
+			case http.StatusUnauthorized:
+				return dto, ErrExpiredToken
+			}
+		}
+		return dto, fmt.Errorf("%w: %w", ErrRequestingToYazio, err)
+	}
+
+	if err := resp.BodyStruct(&dto); err != nil {
+		return dto, fmt.Errorf("%w: %w", ErrDecodingResponse, err)
+	}
+
+	return dto, nil
+}
+
+// Diary returns the foods logged in the diary of
+// the authenticated user on day, sorted by time.
+//
+// Times are read in the location set by [WithLocation].
+//
+// Only products are returned: recipes and
+// simple (quick) entries are not supported yet.
+//
+// On failure the error wraps either:
+//   - [ErrExpiredToken]
+//   - [ErrRequestingToYazio]
+//   - [ErrDecodingResponse]
+//   - Other: generic (DTO related)
+func (u *User) Diary(ctx context.Context, day time.Time) ([]diary.Entry, error) {
+	if u.token.IsExpired() {
+		return nil, ErrExpiredToken
+	}
+
+	var (
+		dto getDiaryDTO
 		req = client.Request{
 			Method:   http.MethodGet,
-			Endpoint: userDataEndpoint,
+			Endpoint: diaryEndpoint,
 			Headers:  defaultHeaders(u.token),
+			QueryParams: client.Payload[string]{
+				"date": day.Format(layoutISO),
+			},
 		}
 	)
 
@@ -174,21 +296,72 @@ func (u *User) Data(ctx context.Context) (d user.Data, err error) {
 		if resp.Response != nil {
 			switch resp.StatusCode {
 			case http.StatusUnauthorized:
-				return d, ErrExpiredToken
+				return nil, ErrExpiredToken
 			}
 		}
-		return d, fmt.Errorf("%s: %w", ErrRequestingToYazio, err)
+		return nil, fmt.Errorf("%w: %w", ErrRequestingToYazio, err)
 	}
 
 	if err := resp.BodyStruct(&dto); err != nil {
-		return d, fmt.Errorf("%s: %w", ErrDecodingResponse, err)
+		return nil, fmt.Errorf("%w: %w", ErrDecodingResponse, err)
 	}
 
-	return dto.toUserData()
+	return dto.toEntries(u.location())
+}
+
+// Food returns the product identified by id,
+// such as the foods referenced by [User.Diary].
+//
+// On failure the error wraps either:
+//   - [ErrExpiredToken]
+//   - [food.ErrNotFound]
+//   - [ErrRequestingToYazio]
+//   - [ErrDecodingResponse]
+//   - Other: generic (DTO related)
+func (u *User) Food(ctx context.Context, id food.ID) (food.Food, error) {
+	if u.token.IsExpired() {
+		return food.Food{}, ErrExpiredToken
+	}
+
+	var (
+		dto getProductDTO
+		req = client.Request{
+			Method:   http.MethodGet,
+			Endpoint: productEndpoint + id.String(),
+			Headers:  defaultHeaders(u.token),
+		}
+	)
+
+	resp, err := u.client.Request(ctx, req)
+	if err != nil {
+		if resp.Response != nil {
+			switch resp.StatusCode {
+			case http.StatusUnauthorized:
+				return food.Food{}, ErrExpiredToken
+			case http.StatusNotFound:
+				return food.Food{}, fmt.Errorf("%w: %s", food.ErrNotFound, id)
+			}
+		}
+		return food.Food{}, fmt.Errorf("%w: %w", ErrRequestingToYazio, err)
+	}
+
+	if err := resp.BodyStruct(&dto); err != nil {
+		return food.Food{}, fmt.Errorf("%w: %w", ErrDecodingResponse, err)
+	}
+
+	return dto.toFood(id)
 }
 
 // Intake returns a series of single-nutrient
-// intake values for the given date range.
+// intake values for the given date range,
+// sorted by date.
+//
+// Days without data are left out, unless
+// filled through [WithGapFilling].
+//
+// Long ranges are split into windows of [WithRangeWindow] days,
+// fetched up to [WithConcurrency] at a time, and stitched back
+// in chronological order.
 //
 // On failure the error wraps either:
 //   - [ErrExpiredToken]
@@ -200,6 +373,25 @@ func (u *User) Intake(ctx context.Context, k intake.Kind, r date.Range) (intake.
 		return nil, ErrExpiredToken
 	}
 
+	sr, err := fetchWindows(ctx, u, r,
+		func(s intake.Single) time.Time { return s.Date },
+		func(ctx context.Context, w date.Range) (intake.SingleRange, error) {
+			return u.intakeWindow(ctx, k, w)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.gapFilling == intake.FillNone {
+		return sr, nil
+	}
+
+	return sr.Fill(k, r, u.gapFilling), nil
+}
+
+// intakeWindow fetches (a window of) [User.Intake] in a single request.
+func (u *User) intakeWindow(ctx context.Context, k intake.Kind, r date.Range) (intake.SingleRange, error) {
 	var (
 		dto getSingleIntakeDTO
 		req = client.Request{
@@ -212,8 +404,13 @@ func (u *User) Intake(ctx context.Context, k intake.Kind, r date.Range) (intake.
 				"nutrient": k.ID(),
 			},
 		}
+		cacheKey = u.cache.rangeKey(ctx, r, intakeCacheKind, k.ID())
 	)
 
+	if u.cache.load(ctx, cacheKey, &dto) {
+		return dto.toRangeSingle(k)
+	}
+
 	resp, err := u.client.Request(ctx, req)
 	if err != nil {
 		if resp.Response != nil {
@@ -222,16 +419,59 @@ func (u *User) Intake(ctx context.Context, k intake.Kind, r date.Range) (intake.
 				return nil, ErrExpiredToken
 			}
 		}
-		return nil, fmt.Errorf("%s: %w", ErrRequestingToYazio, err)
+		return nil, fmt.Errorf("%w: %w", ErrRequestingToYazio, err)
 	}
 
 	if err := resp.BodyStruct(&dto); err != nil {
-		return nil, fmt.Errorf("%s: %w", ErrDecodingResponse, err)
+		return nil, fmt.Errorf("%w: %w", ErrDecodingResponse, err)
 	}
 
+	u.cache.save(cacheKey, dto)
 	return dto.toRangeSingle(k)
 }
 
+// Intakes fetches the single-nutrient series of every kind in
+// ks for the date range r, running up to [WithConcurrency]
+// requests at the same time across every kind and window.
+//
+// The returned [intake.Matrix] has one column per kind in ks.
+// When some kinds fail, m still holds the ones fetched and
+// err is an [*IntakesError] listing the failures.
+//
+// On failure the error wraps, per kind, the errors of [User.Intake].
+func (u *User) Intakes(ctx context.Context, ks []intake.Kind, r date.Range) (m intake.Matrix, err error) {
+	var (
+		wg      sync.WaitGroup
+		results = make([]intake.SingleRange, len(ks))
+		errs    = make([]error, len(ks))
+	)
+
+	ctx = withSemaphore(ctx, u.concurrency) // shared by the windows of every kind
+
+	for i, k := range ks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = u.Intake(ctx, k, r)
+		}()
+	}
+	wg.Wait()
+
+	intakesErr := &IntakesError{Requested: len(ks)}
+	for i, err := range errs {
+		if err != nil {
+			intakesErr.Failed = append(intakesErr.Failed, KindError{Kind: ks[i], Err: err})
+		}
+	}
+
+	m = intake.NewMatrix(ks, results...)
+	if len(intakesErr.Failed) > 0 {
+		return m, intakesErr
+	}
+
+	return m, nil
+}
+
 // Macros returns aggregated values for each
 // day within the provided date range:
 //
@@ -241,6 +481,10 @@ func (u *User) Intake(ctx context.Context, k intake.Kind, r date.Range) (intake.
 //	  - Fat
 //	  - Protein
 //
+// Long ranges are split into windows of [WithRangeWindow] days,
+// fetched up to [WithConcurrency] at a time, and stitched back
+// in chronological order.
+//
 // On failure the error wraps either:
 //   - [ErrExpiredToken]
 //   - [ErrRequestingToYazio]
@@ -251,6 +495,14 @@ func (u *User) Macros(ctx context.Context, r date.Range) (intake.MacrosRange, er
 		return nil, ErrExpiredToken
 	}
 
+	return fetchWindows(ctx, u, r,
+		func(m intake.Macros) time.Time { return m.Date },
+		u.macrosWindow,
+	)
+}
+
+// macrosWindow fetches (a window of) [User.Macros] in a single request.
+func (u *User) macrosWindow(ctx context.Context, r date.Range) (intake.MacrosRange, error) {
 	var (
 		dto getMacroIntakeDTO
 		req = client.Request{
@@ -262,8 +514,13 @@ func (u *User) Macros(ctx context.Context, r date.Range) (intake.MacrosRange, er
 				"end":   r.End.Format(layoutISO),
 			},
 		}
+		cacheKey = u.cache.rangeKey(ctx, r, macrosCacheKind)
 	)
 
+	if u.cache.load(ctx, cacheKey, &dto) {
+		return dto.toRangeMacro()
+	}
+
 	resp, err := u.client.Request(ctx, req)
 	if err != nil {
 		if resp.Response != nil {
@@ -272,12 +529,13 @@ func (u *User) Macros(ctx context.Context, r date.Range) (intake.MacrosRange, er
 				return nil, ErrExpiredToken
 			}
 		}
-		return nil, fmt.Errorf("%s: %w", ErrRequestingToYazio, err)
+		return nil, fmt.Errorf("%w: %w", ErrRequestingToYazio, err)
 	}
 
 	if err := resp.BodyStruct(&dto); err != nil {
-		return nil, fmt.Errorf("%s: %w", ErrRequestingToYazio, err)
+		return nil, fmt.Errorf("%w: %w", ErrRequestingToYazio, err)
 	}
 
+	u.cache.save(cacheKey, dto)
 	return dto.toRangeMacro()
 }
@@ -2,7 +2,11 @@ package yazio
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -11,12 +15,14 @@ import (
 	"github.com/controlado/go-yazio/internal/testutil/server"
 	"github.com/controlado/go-yazio/internal/testutil/times"
 	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/diary"
 	"github.com/controlado/go-yazio/pkg/domain/food"
 	"github.com/controlado/go-yazio/pkg/domain/intake"
 	"github.com/controlado/go-yazio/pkg/domain/meal"
 	"github.com/controlado/go-yazio/pkg/domain/unit"
 	"github.com/controlado/go-yazio/pkg/domain/user"
 	"github.com/controlado/go-yazio/pkg/visibility"
+	"github.com/controlado/go-yazio/pkg/yaziotest"
 	"github.com/google/uuid"
 )
 
@@ -441,3 +447,288 @@ func TestUser_EntryFood(t *testing.T) {
 		})
 	}
 }
+
+func TestUser_Intakes(t *testing.T) {
+	t.Parallel()
+
+	var (
+		day          = times.PastDate(time.UTC)
+		failingKind  = intake.MineralZinc
+		wantKinds    = []intake.Kind{intake.Water, intake.VitaminC, failingKind, intake.Iron}
+		inFlight     atomic.Int64
+		peakInFlight atomic.Int64
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			peak := peakInFlight.Load()
+			if current <= peak || peakInFlight.CompareAndSwap(peak, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		nutrientID := r.URL.Query().Get("nutrient")
+		if nutrientID == failingKind.ID() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]float64{
+			day.Format(layoutISO): float64(len(nutrientID)),
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	u := User{
+		client:      client.New(client.WithBaseURL(srv.URL)),
+		concurrency: 2,
+		token: &Token{
+			expiresAt: times.Future(),
+			access:    uuid.NewString(),
+			refresh:   uuid.NewString(),
+		},
+	}
+
+	m, err := u.Intakes(context.Background(), wantKinds, date.Range{Start: day, End: day})
+
+	var intakesErr *IntakesError
+	if !errors.As(err, &intakesErr) {
+		t.Fatalf("\nwant *IntakesError\ngot %v", err)
+	}
+	assert.Equal(t, intakesErr.Requested, len(wantKinds))
+	assert.Equal(t, len(intakesErr.Failed), 1)
+	assert.Equal(t, intakesErr.Failed[0].Kind, failingKind)
+
+	if peak := peakInFlight.Load(); peak > 2 {
+		t.Fatalf("\nwant at most 2 concurrent requests\ngot %d", peak)
+	}
+
+	assert.EqualSlicesItems(t, m.Kinds, wantKinds)
+	assert.Equal(t, len(m.Rows), 1)
+
+	value, ok := m.Value(day, intake.Water)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, value, float64(len(intake.Water.ID())))
+
+	_, ok = m.Value(day, failingKind)
+	assert.Equal(t, ok, false)
+}
+
+func TestUser_IntakeGapFilling(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		fake     = yaziotest.New(t)
+		firstDay = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		r        = date.Range{Start: firstDay, End: firstDay.AddDate(0, 0, 2)}
+	)
+
+	api, err := New(
+		WithBaseURL(fake.URL),
+		WithGapFilling(intake.FillNoData),
+	)
+	assert.NoError(t, err)
+
+	cred := NewPasswordCred(yaziotest.DefaultUsername, yaziotest.DefaultPassword)
+	u, err := api.Login(ctx, cred)
+	assert.NoError(t, err)
+
+	milk, err := food.New("Milk", food.Miscellaneous, food.Nutrients{
+		intake.Energy: 64, intake.Fat: 3.6, intake.Protein: 3.3,
+		intake.Carb: 4.8, intake.Water: 88,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, u.AddFood(ctx, milk, visibility.PrivateFood))
+
+	err = fake.Consume(yaziotest.DefaultUsername, yaziotest.ConsumedItem{
+		ProductID: milk.ID,
+		Date:      firstDay.AddDate(0, 0, 1),
+		Amount:    250,
+	})
+	assert.NoError(t, err)
+
+	sr, err := u.Intake(ctx, intake.Water, r)
+	assert.NoError(t, err)
+	assert.Equal(t, len(sr), 3)
+	assert.Equal(t, sr[0].HasData(), false)
+	assert.Equal(t, sr[1].Value, 220.0)
+	assert.Equal(t, sr[2].HasData(), false)
+	assert.Equal(t, sr.Average(), intake.SingleAverage{Kind: intake.Water, DaysLength: 1, Average: 220})
+}
+
+func TestUser_Diary(t *testing.T) {
+	t.Parallel()
+
+	var (
+		entryID   = uuid.New()
+		productID = uuid.New()
+		day       = time.Date(2025, 4, 12, 0, 0, 0, 0, time.UTC)
+	)
+
+	srv, err := server.New(t,
+		server.AssertMethod(http.MethodGet),
+		server.AssertEndpoint(diaryEndpoint),
+		server.AssertQueryParams(map[string]string{"date": "2025-04-12"}),
+		server.RespondBodyAny(map[string]any{
+			"products": []map[string]any{
+				{
+					"id":               entryID.String(),
+					"date":             "2025-04-12 19:10:00",
+					"daytime":          "dinner",
+					"type":             "product",
+					"product_id":       productID.String(),
+					"serving":          "slice",
+					"amount":           30,
+					"serving_quantity": 2,
+				},
+				{
+					"id":               uuid.NewString(),
+					"date":             "2025-04-12 08:00:00",
+					"daytime":          "breakfast",
+					"type":             "product",
+					"product_id":       productID.String(),
+					"amount":           150,
+					"serving_quantity": nil,
+				},
+			},
+			"recipe_portions": []any{},
+			"simple_products": []any{},
+		}),
+	)
+	assert.NoError(t, err)
+
+	u := User{
+		client: client.New(client.WithBaseURL(srv.URL)),
+		token: &Token{
+			expiresAt: times.Future(),
+			access:    uuid.NewString(),
+			refresh:   uuid.NewString(),
+		},
+		loc: time.FixedZone("UTC-3", -3*60*60),
+	}
+
+	entries, err := u.Diary(context.Background(), day)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entries), 2)
+
+	assert.Equal(t, entries[0].Meal, meal.Breakfast)
+	assert.Equal(t, entries[0].Quantity, 1.0)
+	assert.Equal(t, entries[0].BaseAmount(), 150.0)
+
+	assert.Equal(t, entries[1].ID, entryID)
+	assert.Equal(t, entries[1].FoodID, productID)
+	assert.Equal(t, entries[1].Date.Equal(time.Date(2025, 4, 12, 22, 10, 0, 0, time.UTC)), true)
+	assert.Equal(t, entries[1].Serving, food.Serving{Kind: food.Slice, Amount: 30})
+	assert.Equal(t, entries[1].BaseAmount(), 60.0)
+
+	u.token = &Token{expiresAt: times.Past()}
+	if _, err := u.Diary(context.Background(), day); !errors.Is(err, ErrExpiredToken) {
+		t.Fatalf("got %v, want %v", err, ErrExpiredToken)
+	}
+}
+
+func TestUser_AddEntry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx  = context.Background()
+		fake = yaziotest.New(t)
+		day  = time.Date(2025, 4, 12, 0, 0, 0, 0, time.UTC)
+	)
+
+	api, err := New(WithBaseURL(fake.URL), WithLocation(time.UTC))
+	assert.NoError(t, err)
+
+	u, err := api.Login(ctx, NewPasswordCred(yaziotest.DefaultUsername, yaziotest.DefaultPassword))
+	assert.NoError(t, err)
+
+	e := diary.Entry{
+		ID:      uuid.New(),
+		Date:    day.Add(19*time.Hour + 10*time.Minute),
+		Meal:    meal.Dinner,
+		FoodID:  uuid.New(),
+		Serving: food.Serving{Kind: food.Slice, Amount: 30},
+	}
+	assert.NoError(t, u.AddEntry(ctx, e))
+
+	entries, err := u.Diary(ctx, day)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entries), 1)
+	assert.Equal(t, entries[0].ID, e.ID)
+	assert.Equal(t, entries[0].Date, e.Date)
+	assert.Equal(t, entries[0].Quantity, 1.0)
+
+	if err := u.AddEntry(ctx, e); !errors.Is(err, diary.ErrAlreadyExists) {
+		t.Fatalf("got %v, want %v", err, diary.ErrAlreadyExists)
+	}
+}
+
+func TestUser_Food(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		productID = uuid.New()
+		gadgetID  = uuid.New()
+		fake      = yaziotest.New(t, yaziotest.WithProduct(yaziotest.Product{
+			ID:        gadgetID,
+			Name:      "Gizmo",
+			Category:  "gadgets",
+			Nutrients: map[string]float64{intake.Energy.ID(): 1},
+			Servings:  []yaziotest.Serving{{Serving: "spoonful", Amount: 5}},
+		}), yaziotest.WithProduct(yaziotest.Product{
+			ID:       productID,
+			Name:     "Whole bread",
+			Category: food.Bread.String(),
+			BaseUnit: unit.Gram.String(),
+			Nutrients: map[string]float64{
+				intake.Energy.ID():  247,
+				intake.Protein.ID(): 13,
+				"nutrient.unknown":  1,
+			},
+			Servings: []yaziotest.Serving{{Serving: food.Slice.String(), Amount: 30}},
+		}))
+	)
+
+	api, err := New(WithBaseURL(fake.URL))
+	assert.NoError(t, err)
+
+	u, err := api.Login(ctx, NewPasswordCred(yaziotest.DefaultUsername, yaziotest.DefaultPassword))
+	assert.NoError(t, err)
+
+	f, err := u.Food(ctx, productID)
+	assert.NoError(t, err)
+	assert.Equal(t, f.ID, productID)
+	assert.Equal(t, f.Name, "Whole bread")
+	assert.Equal(t, f.Category, food.Bread)
+	assert.Equal(t, f.BaseUnit, unit.Gram)
+	assert.DeepEqual(t, f.Nutrients, food.Nutrients{intake.Energy: 247, intake.Protein: 13})
+	assert.DeepEqual(t, f.Servings, []food.Serving{{Kind: food.Slice, Amount: 30}})
+
+	// kept as returned, and through a round trip
+	gadget, err := u.Food(ctx, gadgetID)
+	assert.NoError(t, err)
+	assert.Equal(t, gadget.Category, food.Category("gadgets"))
+	assert.DeepEqual(t, gadget.Servings, []food.Serving{{Kind: "spoonful", Amount: 5}})
+
+	encoded, err := json.Marshal(gadget)
+	assert.NoError(t, err)
+
+	var decoded food.Food
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.DeepEqual(t, decoded, gadget)
+
+	if _, err := u.Food(ctx, uuid.New()); !errors.Is(err, food.ErrNotFound) {
+		t.Fatalf("got %v, want %v", err, food.ErrNotFound)
+	}
+
+	fake.ExpireTokens()
+	if _, err := u.Food(ctx, productID); !errors.Is(err, ErrExpiredToken) {
+		t.Fatalf("got %v, want %v", err, ErrExpiredToken)
+	}
+}
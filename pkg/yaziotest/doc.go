@@ -0,0 +1,16 @@
+/*
+Package yaziotest provides a stateful, in-memory fake of the YAZIO API
+for end-to-end tests of code built on top of package yazio.
+
+The fake implements the oauth, user, products and consumed-items endpoints.
+Products registered through AddFood and entries logged through EntryFood are
+kept in memory, so the diary entries returned by Diary and the aggregates
+returned by Macros and Intake reflect them:
+
+	fake := yaziotest.New(t)
+
+	api, _ := yazio.New(yazio.WithBaseURL(fake.URL))
+	cred := yazio.NewPasswordCred(yaziotest.DefaultUsername, yaziotest.DefaultPassword)
+	user, _ := api.Login(ctx, cred)
+*/
+package yaziotest
@@ -0,0 +1,353 @@
+package yaziotest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type accountKey struct{}
+
+var (
+	requiredNutrients = []string{
+		"energy.energy", "nutrient.fat",
+		"nutrient.protein", "nutrient.carb",
+	}
+)
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// authorized rejects requests without a valid bearer
+// token, and exposes the token's account to next.
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accessToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		s.mu.Lock()
+		sess, ok := s.sessions[accessToken]
+		s.mu.Unlock()
+
+		if !ok || sess.isExpired() {
+			writeError(w, http.StatusUnauthorized, "invalid or expired token")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), accountKey{}, sess.account)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+func accountFrom(r *http.Request) *Account {
+	return r.Context().Value(accountKey{}).(*Account)
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		GrantType    string `json:"grant_type"`
+		Username     string `json:"username"`
+		Password     string `json:"password"`
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var acc *Account
+
+	switch body.GrantType {
+	case "password":
+		a, ok := s.accounts[body.Username]
+		if !ok || a.Password != body.Password {
+			writeError(w, http.StatusBadRequest, "invalid_grant")
+			return
+		}
+		acc = a
+	case "refresh_token":
+		a, ok := s.refresh[body.RefreshToken]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "invalid_grant")
+			return
+		}
+		delete(s.refresh, body.RefreshToken) // rotated
+		acc = a
+	default:
+		writeError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	var (
+		accessToken  = strings.ReplaceAll(uuid.NewString(), "-", "")
+		refreshToken = strings.ReplaceAll(uuid.NewString(), "-", "")
+	)
+
+	s.sessions[accessToken] = &session{
+		account:   acc,
+		expiresAt: time.Now().Add(s.tokenTTL),
+	}
+	s.refresh[refreshToken] = acc
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"expires_in":    int64(s.tokenTTL / time.Second),
+		"token_type":    "bearer",
+	})
+}
+
+func (s *Server) handleUser(w http.ResponseWriter, r *http.Request) {
+	acc := accountFrom(r)
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"uuid":                      acc.ID.String(),
+		"user_token":                strings.ReplaceAll(acc.ID.String(), "-", ""),
+		"first_name":                acc.FirstName,
+		"last_name":                 acc.LastName,
+		"profile_image":             "",
+		"email":                     acc.Username,
+		"email_confirmation_status": "confirmed",
+		"registration_date":         acc.Registration.Format(layoutDate),
+		"date_of_birth":             acc.Birth.Format(layoutISO),
+	})
+}
+
+func (s *Server) handleAddProduct(w http.ResponseWriter, r *http.Request) {
+	var p Product
+
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid product body")
+		return
+	}
+
+	for _, id := range requiredNutrients {
+		if _, ok := p.Nutrients[id]; !ok {
+			writeError(w, http.StatusBadRequest, "missing nutrient "+id)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.products[p.ID]; ok {
+		writeError(w, http.StatusConflict, "product already exists")
+		return
+	}
+
+	s.products[p.ID] = p
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid product id")
+		return
+	}
+
+	s.mu.Lock()
+	p, ok := s.products[id]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "product not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *Server) handleConsume(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Products []struct {
+			ID        uuid.UUID `json:"id"`
+			Date      string    `json:"date"`
+			Daytime   string    `json:"daytime"`
+			ProductID uuid.UUID `json:"product_id"`
+			Serving   string    `json:"serving"`
+			Amount    float64   `json:"amount"`
+			Quantity  float64   `json:"serving_quantity"`
+		} `json:"products"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid consumed items body")
+		return
+	}
+
+	items := make([]ConsumedItem, 0, len(body.Products))
+	for _, p := range body.Products {
+		parsedDate, err := time.Parse(layoutDate, p.Date)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid date "+p.Date)
+			return
+		}
+
+		items = append(items, ConsumedItem{
+			ID:        p.ID,
+			ProductID: p.ProductID,
+			Date:      parsedDate,
+			Daytime:   p.Daytime,
+			Serving:   p.Serving,
+			Amount:    p.Amount,
+			Quantity:  p.Quantity,
+		})
+	}
+
+	var (
+		acc = accountFrom(r)
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ci := range items {
+		if slices.ContainsFunc(s.consumed[acc], func(other ConsumedItem) bool { return other.ID == ci.ID }) {
+			writeError(w, http.StatusConflict, "consumed item already exists")
+			return
+		}
+	}
+
+	for _, ci := range items {
+		if err := s.consume(acc, ci); err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseRange reads the "start" and "end" query params.
+func parseRange(r *http.Request) (start, end time.Time, ok bool) {
+	q := r.URL.Query()
+
+	start, err := time.Parse(layoutISO, q.Get("start"))
+	if err != nil {
+		return start, end, false
+	}
+
+	end, err = time.Parse(layoutISO, q.Get("end"))
+	if err != nil || end.Before(start) {
+		return start, end, false
+	}
+
+	return start, end, true
+}
+
+func (s *Server) handleDiary(w http.ResponseWriter, r *http.Request) {
+	day, err := time.Parse(layoutISO, r.URL.Query().Get("date"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid date")
+		return
+	}
+
+	s.mu.Lock()
+	items := slices.Clone(s.consumed[accountFrom(r)])
+	s.mu.Unlock()
+
+	products := make([]map[string]any, 0, len(items))
+	for _, ci := range items {
+		if ci.Date.Format(layoutISO) != day.Format(layoutISO) {
+			continue
+		}
+
+		products = append(products, map[string]any{
+			"id":               ci.ID,
+			"date":             ci.Date.Format(layoutDate),
+			"daytime":          ci.Daytime,
+			"type":             "product",
+			"product_id":       ci.ProductID,
+			"serving":          ci.Serving,
+			"amount":           ci.Amount,
+			"serving_quantity": ci.Quantity,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"products":        products,
+		"recipe_portions": []any{},
+		"simple_products": []any{},
+	})
+}
+
+func (s *Server) handleMacros(w http.ResponseWriter, r *http.Request) {
+	start, end, ok := parseRange(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid start/end")
+		return
+	}
+
+	s.mu.Lock()
+	daily := s.dailyNutrients(accountFrom(r), start, end)
+	s.mu.Unlock()
+
+	days := make([]string, 0, len(daily))
+	for day := range daily {
+		days = append(days, day)
+	}
+	slices.Sort(days)
+
+	out := make([]map[string]any, 0, len(days))
+	for _, day := range days {
+		nuts := daily[day]
+		out = append(out, map[string]any{
+			"date":        day,
+			"energy":      nuts["energy.energy"],
+			"carb":        nuts["nutrient.carb"],
+			"fat":         nuts["nutrient.fat"],
+			"protein":     nuts["nutrient.protein"],
+			"energy_goal": 2000,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleSingle(w http.ResponseWriter, r *http.Request) {
+	start, end, ok := parseRange(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid start/end")
+		return
+	}
+
+	nutrientID := r.URL.Query().Get("nutrient")
+	if nutrientID == "" {
+		writeError(w, http.StatusBadRequest, "missing nutrient")
+		return
+	}
+
+	s.mu.Lock()
+	daily := s.dailyNutrients(accountFrom(r), start, end)
+	s.mu.Unlock()
+
+	out := make(map[string]float64, len(daily))
+	for day, nuts := range daily {
+		if value, ok := nuts[nutrientID]; ok {
+			out[day] = value
+		}
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,29 @@
+package yaziotest
+
+import "time"
+
+type Option func(s *Server)
+
+// WithAccount registers a in the server,
+// besides the default account.
+func WithAccount(a Account) Option {
+	return func(s *Server) {
+		s.addAccount(a)
+	}
+}
+
+// WithTokenTTL defines how long issued
+// access tokens stay valid.
+func WithTokenTTL(d time.Duration) Option {
+	return func(s *Server) {
+		s.tokenTTL = d
+	}
+}
+
+// WithProduct registers p as an
+// already existing product.
+func WithProduct(p Product) Option {
+	return func(s *Server) {
+		s.products[p.ID] = p
+	}
+}
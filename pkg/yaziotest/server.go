@@ -0,0 +1,224 @@
+package yaziotest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultUsername = "joao@yaziotest.local"
+	DefaultPassword = "yaziotest"
+
+	defaultTokenTTL = 48 * time.Hour
+)
+
+// API
+const (
+	loginEndpoint         string = "/v18/oauth/token"
+	userDataEndpoint      string = "/v18/user"
+	entryFoodEndpoint     string = "/v18/user/consumed-items"
+	addFoodEndpoint       string = "/v18/user/products"
+	productEndpoint       string = "/v18/products/{id}"
+	singleIntakesEndpoint string = "/v18/user/consumed-items/specific-nutrient-daily"
+	macrosIntakesEndpoint string = "/v18/user/consumed-items/nutrients-daily"
+)
+
+// Time layout
+const (
+	layoutISO  string = "2006-01-02"
+	layoutDate string = "2006-01-02 15:04:05"
+)
+
+// Server is an in-memory fake of the YAZIO API.
+//
+// It embeds the running [*httptest.Server], so URL
+// can be given straight to yazio.WithBaseURL.
+//
+// Instances of Server should be created using [New].
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	tokenTTL time.Duration
+	accounts map[string]*Account         // by username
+	sessions map[string]*session         // by access token
+	refresh  map[string]*Account         // by refresh token
+	products map[uuid.UUID]Product       // by product id
+	consumed map[*Account][]ConsumedItem // by owner
+}
+
+// New starts a new [*Server] that is closed
+// when the test (or benchmark) t finishes.
+//
+// The server always knows an account identified
+// by [DefaultUsername] and [DefaultPassword].
+func New(t testing.TB, opts ...Option) *Server {
+	t.Helper()
+
+	s := &Server{
+		tokenTTL: defaultTokenTTL,
+		accounts: make(map[string]*Account),
+		sessions: make(map[string]*session),
+		refresh:  make(map[string]*Account),
+		products: make(map[uuid.UUID]Product),
+		consumed: make(map[*Account][]ConsumedItem),
+	}
+
+	s.addAccount(Account{
+		ID:           uuid.New(),
+		Username:     DefaultUsername,
+		Password:     DefaultPassword,
+		FirstName:    "João",
+		LastName:     "da Silva",
+		Registration: time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC),
+		Birth:        time.Date(1995, 8, 26, 0, 0, 0, 0, time.UTC),
+	})
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+loginEndpoint, s.handleToken)
+	mux.HandleFunc("GET "+userDataEndpoint, s.authorized(s.handleUser))
+	mux.HandleFunc("POST "+addFoodEndpoint, s.authorized(s.handleAddProduct))
+	mux.HandleFunc("GET "+productEndpoint, s.authorized(s.handleProduct))
+	mux.HandleFunc("POST "+entryFoodEndpoint, s.authorized(s.handleConsume))
+	mux.HandleFunc("GET "+entryFoodEndpoint, s.authorized(s.handleDiary))
+	mux.HandleFunc("GET "+macrosIntakesEndpoint, s.authorized(s.handleMacros))
+	mux.HandleFunc("GET "+singleIntakesEndpoint, s.authorized(s.handleSingle))
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *Server) addAccount(a Account) {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	s.accounts[a.Username] = &a
+}
+
+// ExpireTokens invalidates every access token issued so far.
+//
+// Refresh tokens keep working, which makes it possible
+// to exercise the refresh flow of the client.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiredAt := time.Now().Add(-time.Second)
+	for _, sess := range s.sessions {
+		sess.expiresAt = expiredAt
+	}
+}
+
+// Products returns every registered product,
+// sorted by name.
+func (s *Server) Products() []Product {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Product, 0, len(s.products))
+	for _, p := range s.products {
+		out = append(out, p)
+	}
+
+	slices.SortFunc(out, func(a, b Product) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return out
+}
+
+// Consumed returns the diary entries logged by the account
+// identified by username, in chronological order.
+func (s *Server) Consumed(username string) []ConsumedItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, ok := s.accounts[username]
+	if !ok {
+		return nil
+	}
+
+	return slices.Clone(s.consumed[acc])
+}
+
+// Consume logs ci in the diary of the account identified by
+// username, as if it was sent through the consumed-items endpoint.
+//
+// It's useful to seed entries on days other than today.
+func (s *Server) Consume(username string, ci ConsumedItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, ok := s.accounts[username]
+	if !ok {
+		return fmt.Errorf("unknown account %q", username)
+	}
+
+	if ci.ID == uuid.Nil {
+		ci.ID = uuid.New()
+	}
+
+	return s.consume(acc, ci)
+}
+
+// consume must be called holding s.mu.
+func (s *Server) consume(acc *Account, ci ConsumedItem) error {
+	for _, other := range s.consumed[acc] {
+		if other.ID == ci.ID {
+			return fmt.Errorf("consumed item %s already exists", ci.ID)
+		}
+	}
+
+	items := append(s.consumed[acc], ci)
+	slices.SortStableFunc(items, func(a, b ConsumedItem) int {
+		return a.Date.Compare(b.Date)
+	})
+	s.consumed[acc] = items
+
+	return nil
+}
+
+// dailyNutrients sums the nutrients of the items consumed by acc
+// within [start, end], keyed by day (ISO layout) and nutrient ID.
+//
+// Entries pointing to unknown products are ignored, like the
+// real API silently does. Must be called holding s.mu.
+func (s *Server) dailyNutrients(acc *Account, start, end time.Time) map[string]map[string]float64 {
+	out := make(map[string]map[string]float64)
+
+	for _, ci := range s.consumed[acc] {
+		day := ci.Date.Format(layoutISO)
+		if day < start.Format(layoutISO) || day > end.Format(layoutISO) {
+			continue
+		}
+
+		p, ok := s.products[ci.ProductID]
+		if !ok {
+			continue
+		}
+
+		if out[day] == nil {
+			out[day] = make(map[string]float64)
+		}
+
+		for id, per100 := range p.Nutrients {
+			out[day][id] += per100 * ci.baseAmount() / 100
+		}
+	}
+
+	return out
+}
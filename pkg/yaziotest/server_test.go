@@ -0,0 +1,195 @@
+package yaziotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/controlado/go-yazio/internal/application"
+	"github.com/controlado/go-yazio/internal/testutil/assert"
+	"github.com/controlado/go-yazio/pkg/domain/date"
+	"github.com/controlado/go-yazio/pkg/domain/food"
+	"github.com/controlado/go-yazio/pkg/domain/intake"
+	"github.com/controlado/go-yazio/pkg/domain/meal"
+	"github.com/controlado/go-yazio/pkg/visibility"
+	"github.com/controlado/go-yazio/pkg/yazio"
+	"github.com/google/uuid"
+)
+
+func login(t *testing.T, fake *Server, username, password string) (*yazio.API, *yazio.User) {
+	t.Helper()
+
+	api, err := yazio.New(
+		yazio.WithBaseURL(fake.URL),
+	)
+	assert.NoError(t, err)
+
+	cred := yazio.NewPasswordCred(username, password)
+	user, err := api.Login(context.Background(), cred)
+	assert.NoError(t, err)
+
+	return api, user
+}
+
+func TestServer_Login(t *testing.T) {
+	t.Parallel()
+
+	testBlocks := []struct {
+		name     string
+		username string
+		password string
+		wantErr  error
+	}{
+		{
+			name:     "default account",
+			username: DefaultUsername,
+			password: DefaultPassword,
+		},
+		{
+			name:     "extra account",
+			username: "maria@yaziotest.local",
+			password: "maria",
+		},
+		{
+			name:     "wrong password",
+			username: DefaultUsername,
+			password: "wrong",
+			wantErr:  yazio.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tb := range testBlocks {
+		t.Run(tb.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := New(t,
+				WithAccount(Account{Username: "maria@yaziotest.local", Password: "maria"}),
+			)
+
+			api, err := yazio.New(
+				yazio.WithBaseURL(fake.URL),
+			)
+			assert.NoError(t, err)
+
+			cred := yazio.NewPasswordCred(tb.username, tb.password)
+			_, err = api.Login(context.Background(), cred)
+			if !errors.Is(err, tb.wantErr) {
+				t.Fatalf("\ngot err %v\nwant err %v", err, tb.wantErr)
+			}
+		})
+	}
+}
+
+func TestServer_Refresh(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		fake      = New(t)
+		api, user = login(t, fake, DefaultUsername, DefaultPassword)
+	)
+
+	fake.ExpireTokens()
+
+	_, err := user.Data(ctx)
+	if !errors.Is(err, yazio.ErrExpiredToken) {
+		t.Fatalf("\ngot err %v\nwant err %v", err, yazio.ErrExpiredToken)
+	}
+
+	// the client only refreshes tokens it knows are expired
+	user.Token().Update(staleToken{refresh: user.Token().Refresh()})
+	assert.NoError(t, api.Refresh(ctx, user))
+
+	userData, err := user.Data(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, userData.Email.Value, DefaultUsername)
+}
+
+func TestServer_Diary(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		fake    = New(t)
+		_, user = login(t, fake, DefaultUsername, DefaultPassword)
+	)
+
+	banana, err := food.New("Banana", food.Miscellaneous, food.Nutrients{
+		intake.Energy:  90,
+		intake.Fat:     0.5,
+		intake.Protein: 1,
+		intake.Carb:    20,
+		intake.Water:   75,
+	})
+	assert.NoError(t, err)
+
+	err = user.AddFood(ctx, banana, visibility.PrivateFood)
+	assert.NoError(t, err)
+
+	err = user.AddFood(ctx, banana, visibility.PrivateFood)
+	if !errors.Is(err, food.ErrAlreadyExists) {
+		t.Fatalf("\ngot err %v\nwant err %v", err, food.ErrAlreadyExists)
+	}
+
+	serving := food.Serving{Kind: food.Portion, Amount: 200}
+	assert.NoError(t, user.EntryFood(ctx, meal.Breakfast, banana.ID, serving))
+	assert.NoError(t, user.EntryFood(ctx, meal.Snack, banana.ID, serving))
+	assert.NoError(t, user.EntryFood(ctx, meal.Snack, uuid.New(), serving)) // unknown product
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	err = fake.Consume(DefaultUsername, ConsumedItem{
+		ProductID: banana.ID,
+		Date:      yesterday,
+		Daytime:   meal.Lunch.String(),
+		Serving:   food.Portion.String(),
+		Amount:    100,
+		Quantity:  1,
+	})
+	assert.NoError(t, err)
+
+	var (
+		today    = time.Now()
+		dayRange = date.Range{Start: yesterday, End: today}
+	)
+
+	macros, err := user.Macros(ctx, dayRange)
+	assert.NoError(t, err)
+	assert.Equal(t, len(macros), 2)
+	assert.Equal(t, macros[0].Energy, 90.0)
+	assert.Equal(t, macros[1].Energy, 360.0)
+	assert.Equal(t, macros[1].Carb, 80.0)
+
+	water, err := user.Intake(ctx, intake.Water, dayRange)
+	assert.NoError(t, err)
+	assert.Equal(t, water.Average().Average, (75.0+300.0)/2)
+
+	assert.Equal(t, len(fake.Products()), 1)
+	assert.Equal(t, len(fake.Consumed(DefaultUsername)), 4)
+
+	entries, err := user.Diary(ctx, today)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entries), 3)
+	assert.Equal(t, entries[0].Meal, meal.Breakfast)
+	assert.Equal(t, entries[0].FoodID, banana.ID)
+	assert.Equal(t, entries[0].BaseAmount(), 200.0)
+
+	entries, err = user.Diary(ctx, yesterday)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entries), 1)
+	assert.Equal(t, entries[0].Meal, meal.Lunch)
+}
+
+// staleToken keeps the refresh token of a session,
+// but reports it as already expired to the client.
+type staleToken struct {
+	refresh string
+}
+
+func (st staleToken) Update(application.Token) {}
+func (st staleToken) ExpiresAt() time.Time     { return time.Now().Add(-time.Hour) }
+func (st staleToken) Refresh() string          { return st.refresh }
+func (st staleToken) Access() string           { return "" }
+func (st staleToken) Bearer() string           { return "Bearer " }
+func (st staleToken) String() string           { return "Token(Expired)" }
+func (st staleToken) IsExpired() bool          { return true }
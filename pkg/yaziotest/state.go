@@ -0,0 +1,70 @@
+package yaziotest
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Account is a user known by the fake [Server].
+type Account struct {
+	ID           uuid.UUID // ID is the user uuid returned by the user endpoint.
+	Username     string    // Username is the login used with the password grant.
+	Password     string    // Password is the secret used with the password grant.
+	FirstName    string    // FirstName is the user's first name.
+	LastName     string    // LastName is the user's last name.
+	Registration time.Time // Registration is the account registration instant.
+	Birth        time.Time // Birth is the user's date of birth.
+}
+
+// Product is a food registered in the fake [Server].
+//
+// Nutrients are keyed by the nutrient ID (e.g. "nutrient.fat")
+// and hold the amount per 100 base units, as sent by AddFood.
+type Product struct {
+	ID        uuid.UUID          `json:"id"`
+	Name      string             `json:"name"`
+	Category  string             `json:"category"`
+	BaseUnit  string             `json:"base_unit"`
+	Private   bool               `json:"is_private"`
+	Nutrients map[string]float64 `json:"nutrients"`
+	Servings  []Serving          `json:"servings"`
+}
+
+// Serving is a predefined serving of a [Product].
+type Serving struct {
+	Serving string  `json:"serving"`
+	Amount  float64 `json:"amount"`
+}
+
+// ConsumedItem is a diary entry logged by an [Account].
+//
+// The amount of base units it represents is Amount * Quantity.
+type ConsumedItem struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+	Date      time.Time
+	Daytime   string
+	Serving   string
+	Amount    float64
+	Quantity  float64
+}
+
+// baseAmount returns how many base units
+// of the product the item represents.
+func (ci ConsumedItem) baseAmount() float64 {
+	quantity := ci.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	return ci.Amount * quantity
+}
+
+type session struct {
+	account   *Account
+	expiresAt time.Time
+}
+
+func (s *session) isExpired() bool {
+	return s.expiresAt.Before(time.Now())
+}